@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	adaptersdb "tweetschallenge/internal/adapters/db"
+	app "tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/domain"
+)
+
+// runAdmin implementa `server admin grant|revoke <handle>`: el rol admin no
+// se asigna por la API, así que el primero sale de acá.
+func runAdmin(args []string, out io.Writer) error {
+	if len(args) != 2 || (args[0] != "grant" && args[0] != "revoke") {
+		return fmt.Errorf("usage: admin grant|revoke <handle>")
+	}
+	role := domain.RoleAdmin
+	if args[0] == "revoke" {
+		role = domain.RoleUser
+	}
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("db: %w", err)
+	}
+	defer adaptersdb.Close(db)
+
+	uc := app.SetUserRole{Users: adaptersdb.NewUserRepoGorm(db)}
+	u, err := uc.Exec(context.Background(), app.SetUserRoleInput{Handle: args[1], Role: role})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s (%s) is now %s\n", u.Handle, u.ID, u.Role)
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	adaptersdb "tweetschallenge/internal/adapters/db"
+)
+
+// runHashtags implementa `server hashtags reindex`.
+func runHashtags(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "reindex" {
+		return fmt.Errorf("usage: hashtags reindex")
+	}
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("db: %w", err)
+	}
+	defer adaptersdb.Close(db)
+
+	n, err := adaptersdb.ReindexHashtags(context.Background(), db)
+	fmt.Fprintf(out, "reindexed %d tweet(s)\n", n)
+	return err
+}
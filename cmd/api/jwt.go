@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	adapterjwt "tweetschallenge/internal/adapters/jwt"
+)
+
+// runJWT implementa `server jwt genkey eddsa|hs256 [kid]`: escribe la clave en
+// JWT_KEYS_DIR como <kid>.pem o <kid>.hs256. El kid por defecto es la fecha,
+// así la más nueva queda activa si no se fija JWT_ACTIVE_KID.
+func runJWT(args []string, out io.Writer) error {
+	if len(args) < 2 || args[0] != "genkey" || (args[1] != "eddsa" && args[1] != "hs256") {
+		return fmt.Errorf("usage: jwt genkey eddsa|hs256 [kid]")
+	}
+	dir := adapterjwt.ConfigFromEnv().KeysDir
+	if dir == "" {
+		return fmt.Errorf("JWT_KEYS_DIR is not set")
+	}
+	kid := time.Now().UTC().Format("2006-01-02T150405")
+	if len(args) > 2 {
+		kid = args[2]
+	}
+
+	var name string
+	var data []byte
+	switch args[1] {
+	case "eddsa":
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return err
+		}
+		if data, err = adapterjwt.MarshalEdDSAPEM(priv); err != nil {
+			return err
+		}
+		name = kid + ".pem"
+	case "hs256":
+		secret := make([]byte, 48)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		data = []byte(base64.RawURLEncoding.EncodeToString(secret) + "\n")
+		name = kid + ".hs256"
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "wrote %s (kid %s)\n", path, kid)
+	return nil
+}
@@ -11,9 +11,41 @@ import (
 	"github.com/joho/godotenv"
 )
 
+// @title Hexa Microblog API
+// @version 1.0
+// @description API de ejemplo con arquitectura hexagonal.
+// @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description API key (`Bearer tck_…`, también en el header `X-API-Key`) o access token JWT (`Bearer eyJ…`, ver /v1/auth/token).
 func main() {
 	_ = godotenv.Load()
 
+	if len(os.Args) > 1 {
+		var err error
+		switch os.Args[1] {
+		case "migrate":
+			err = runMigrate(os.Args[2:], os.Stdout)
+		case "hashtags":
+			err = runHashtags(os.Args[2:], os.Stdout)
+		case "search":
+			err = runSearch(os.Args[2:], os.Stdout)
+		case "jwt":
+			err = runJWT(os.Args[2:], os.Stdout)
+		case "admin":
+			err = runAdmin(os.Args[2:], os.Stdout)
+		case "users":
+			err = runUsers(os.Args[2:], os.Stdout)
+		default:
+			log.Fatalf("unknown command %q", os.Args[1])
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	if m := os.Getenv("GIN_MODE"); m != "" {
 		gin.SetMode(m)
 	}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	adaptersdb "tweetschallenge/internal/adapters/db"
+	"tweetschallenge/internal/adapters/db/migrate"
+)
+
+// runMigrate implementa `server migrate up|down [n]|status`.
+func runMigrate(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [steps]|status")
+	}
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("db: %w", err)
+	}
+	defer adaptersdb.Close(db)
+
+	m, err := migrate.New(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		n, err := m.Up(ctx)
+		fmt.Fprintf(out, "applied %d migration(s)\n", n)
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps <= 0 {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+		}
+		n, err := m.Down(ctx, steps)
+		fmt.Fprintf(out, "reverted %d migration(s)\n", n)
+		return err
+	case "status":
+		list, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, st := range list {
+			state := "pending"
+			if st.Applied {
+				state = "applied " + time.Unix(st.AppliedAt, 0).UTC().Format(time.RFC3339)
+			}
+			if st.Modified {
+				state += " (checksum mismatch)"
+			}
+			fmt.Fprintf(out, "%04d  %-30s %s\n", st.Version, st.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	adaptersdb "tweetschallenge/internal/adapters/db"
+)
+
+// runSearch implementa `server search rebuild`.
+func runSearch(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "rebuild" {
+		return fmt.Errorf("usage: search rebuild")
+	}
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("db: %w", err)
+	}
+	defer adaptersdb.Close(db)
+
+	fts, err := adaptersdb.RebuildSearchIndex(context.Background(), db)
+	if err != nil {
+		return err
+	}
+	if !fts {
+		fmt.Fprintln(out, "no FTS5 index (LIKE fallback), nothing to rebuild")
+		return nil
+	}
+	fmt.Fprintln(out, "search index rebuilt")
+	return nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	adaptersdb "tweetschallenge/internal/adapters/db"
+)
+
+// runUsers implementa `server users reconcile-counters`: recalcula
+// follower_count, following_count y tweet_count. Pensado para correr por cron.
+func runUsers(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "reconcile-counters" {
+		return fmt.Errorf("usage: users reconcile-counters")
+	}
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
+	if err != nil {
+		return fmt.Errorf("db: %w", err)
+	}
+	defer adaptersdb.Close(db)
+
+	n, err := adaptersdb.ReconcileUserCounters(context.Background(), db)
+	fmt.Fprintf(out, "fixed counters of %d user(s)\n", n)
+	return err
+}
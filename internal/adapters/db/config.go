@@ -0,0 +1,43 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DriverSQLite   = "sqlite"
+	DriverPostgres = "postgres"
+)
+
+// Config selecciona el motor (DB_DRIVER) y su configuración específica.
+type Config struct {
+	Driver   string
+	SQLite   SQLiteConfig
+	Postgres PostgresConfig
+}
+
+func ConfigFromEnv() Config {
+	driver := os.Getenv("DB_DRIVER")
+	if driver == "" {
+		driver = DriverSQLite
+	}
+	return Config{
+		Driver:   driver,
+		SQLite:   SQLiteConfigFromEnv(),
+		Postgres: PostgresConfigFromEnv(),
+	}
+}
+
+func Open(cfg Config) (*gorm.DB, error) {
+	switch cfg.Driver {
+	case "", DriverSQLite:
+		return OpenSQLite(cfg.SQLite)
+	case DriverPostgres, "postgresql":
+		return OpenPostgres(cfg.Postgres)
+	default:
+		return nil, fmt.Errorf("db: unknown driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"tweetschallenge/internal/domain"
+)
+
+type APIKeyModel struct {
+	ID        string `gorm:"primaryKey"`
+	UserID    string
+	Name      string
+	Hint      string
+	Hash      string // idx_api_keys_hash (único)
+	CreatedAt int64
+	RevokedAt *int64
+}
+
+func (APIKeyModel) TableName() string { return "api_keys" }
+
+type APIKeyRepoGorm struct{ db *gorm.DB }
+
+func NewAPIKeyRepoGorm(db *gorm.DB) APIKeyRepoGorm { return APIKeyRepoGorm{db: db} }
+
+func apiKeyToModel(k domain.APIKey) APIKeyModel {
+	m := APIKeyModel{ID: k.ID, UserID: k.UserID, Name: k.Name, Hint: k.Hint, Hash: k.Hash, CreatedAt: k.CreatedAt}
+	if k.RevokedAt != 0 {
+		m.RevokedAt = &k.RevokedAt
+	}
+	return m
+}
+
+func (m APIKeyModel) toDomain() domain.APIKey {
+	k := domain.APIKey{ID: m.ID, UserID: m.UserID, Name: m.Name, Hint: m.Hint, Hash: m.Hash, CreatedAt: m.CreatedAt}
+	if m.RevokedAt != nil {
+		k.RevokedAt = *m.RevokedAt
+	}
+	return k
+}
+
+func (r APIKeyRepoGorm) Create(ctx context.Context, k *domain.APIKey) error {
+	m := apiKeyToModel(*k)
+	return r.db.WithContext(ctx).Create(&m).Error
+}
+
+func (r APIKeyRepoGorm) Get(ctx context.Context, id string) (domain.APIKey, error) {
+	return r.first(r.db.WithContext(ctx).Where("id = ?", id))
+}
+
+func (r APIKeyRepoGorm) ByHash(ctx context.Context, hash string) (domain.APIKey, error) {
+	return r.first(r.db.WithContext(ctx).Where("hash = ?", hash))
+}
+
+func (r APIKeyRepoGorm) first(tx *gorm.DB) (domain.APIKey, error) {
+	var m APIKeyModel
+	err := tx.First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.APIKey{}, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	return m.toDomain(), nil
+}
+
+func (r APIKeyRepoGorm) ListByUser(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	var rows []APIKeyModel
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).
+		Order("created_at DESC").Order("id DESC").Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.APIKey, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, m.toDomain())
+	}
+	return out, nil
+}
+
+func (r APIKeyRepoGorm) Revoke(ctx context.Context, id string, at int64) error {
+	return revokeAPIKey(r.db.WithContext(ctx), id, at)
+}
+
+func (r APIKeyRepoGorm) Rotate(ctx context.Context, oldID string, next *domain.APIKey, at int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := revokeAPIKey(tx, oldID, at); err != nil {
+			return err
+		}
+		m := apiKeyToModel(*next)
+		return tx.Create(&m).Error
+	})
+}
+
+func revokeAPIKey(tx *gorm.DB, id string, at int64) error {
+	return tx.Model(&APIKeyModel{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at).Error
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type BlockModel struct {
+	ID        string `gorm:"primaryKey"`
+	BlockerID string `gorm:"index:idx_blocks_pair,unique"`
+	BlockedID string `gorm:"index:idx_blocks_pair,unique"`
+	CreatedAt int64
+}
+
+func (BlockModel) TableName() string { return "blocks" }
+
+type BlockRepoGorm struct{ db *gorm.DB }
+
+func NewBlockRepoGorm(db *gorm.DB) BlockRepoGorm { return BlockRepoGorm{db: db} }
+
+func (r BlockRepoGorm) Create(ctx context.Context, b *domain.Block) error {
+	m := BlockModel{ID: b.ID, BlockerID: b.BlockerID, BlockedID: b.BlockedID, CreatedAt: b.CreatedAt}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "blocker_id"}, {Name: "blocked_id"}},
+		DoNothing: true,
+	}).Create(&m).Error
+}
+
+func (r BlockRepoGorm) Delete(ctx context.Context, blockerID, blockedID string) error {
+	return r.db.WithContext(ctx).
+		Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).
+		Delete(&BlockModel{}).Error
+}
+
+func (r BlockRepoGorm) Between(ctx context.Context, a, b string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&BlockModel{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", a, b, b, a).
+		Count(&n).Error
+	return n > 0, err
+}
+
+func (r BlockRepoGorm) BlockerIDs(ctx context.Context, userID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Model(&BlockModel{}).
+		Where("blocked_id = ?", userID).
+		Pluck("blocker_id", &ids).Error
+	return ids, err
+}
+
+func (r BlockRepoGorm) Page(ctx context.Context, blockerID string, q ports.PageQuery) (ports.Page[domain.Block], error) {
+	var rows []BlockModel
+	tx := r.db.WithContext(ctx).Where("blocker_id = ?", blockerID)
+	if err := keyset(tx, q, "created_at", "blocked_id").Find(&rows).Error; err != nil {
+		return ports.Page[domain.Block]{}, err
+	}
+	out := make([]domain.Block, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, domain.Block{ID: m.ID, BlockerID: m.BlockerID, BlockedID: m.BlockedID, CreatedAt: m.CreatedAt})
+	}
+	return finishPage(out, q), nil
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// idx_follows_followee_created e idx_follows_follower_created (migración 0016)
+// cubren los listados paginados de seguidores y seguidos.
+type FollowModel struct {
+	ID         string `gorm:"primaryKey"`
+	FollowerID string `gorm:"index:idx_follow_pair,unique"`
+	FolloweeID string `gorm:"index:idx_follow_pair,unique"`
+	CreatedAt  int64  `gorm:"index"`
+}
+
+type FollowRepoGorm struct{ db *gorm.DB }
+
+func NewFollowRepoGorm(db *gorm.DB) FollowRepoGorm { return FollowRepoGorm{db: db} }
+
+// Create y Unfollow ajustan follower_count/following_count en la misma
+// transacción, sólo cuando la fila realmente se insertó o se borró.
+func (r FollowRepoGorm) Create(ctx context.Context, f *domain.Follow) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// idempotente: si el par ya existe no hace nada (ON CONFLICT DO NOTHING)
+		m := FollowModel{ID: f.ID, FollowerID: f.FollowerID, FolloweeID: f.FolloweeID, CreatedAt: f.CreatedAt}
+		res := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "follower_id"}, {Name: "followee_id"}},
+			DoNothing: true,
+		}).Create(&m)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return bumpFollowCounters(tx, f.FollowerID, f.FolloweeID, 1)
+	})
+}
+
+func (r FollowRepoGorm) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&FollowModel{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return bumpFollowCounters(tx, followerID, followeeID, -1)
+	})
+}
+
+func (r FollowRepoGorm) Exists(ctx context.Context, followerID, followeeID string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&FollowModel{}).
+		Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&n).Error
+	return n > 0, err
+}
+
+func (r FollowRepoGorm) CountFollowers(ctx context.Context, followeeID string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&FollowModel{}).Where("followee_id = ?", followeeID).Count(&n).Error
+	return n, err
+}
+
+func bumpFollowCounters(tx *gorm.DB, followerID, followeeID string, delta int) error {
+	if err := bumpUserCounter(tx, followerID, "following_count", delta); err != nil {
+		return err
+	}
+	return bumpUserCounter(tx, followeeID, "follower_count", delta)
+}
+
+func (r FollowRepoGorm) FollowingIDs(ctx context.Context, followerID string) ([]string, error) {
+	var rows []FollowModel
+	if err := r.db.WithContext(ctx).Where("follower_id = ?", followerID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, m.FolloweeID)
+	}
+	return out, nil
+}
+
+func (r FollowRepoGorm) FollowersIDs(ctx context.Context, followeeID string) ([]string, error) {
+	var rows []FollowModel
+	if err := r.db.WithContext(ctx).Where("followee_id = ?", followeeID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, m.FollowerID)
+	}
+	return out, nil
+}
+
+func (r FollowRepoGorm) FollowersPage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.Follow], error) {
+	return r.page(r.db.WithContext(ctx).Where("followee_id = ?", userID), q, "follower_id")
+}
+
+func (r FollowRepoGorm) FollowingPage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.Follow], error) {
+	return r.page(r.db.WithContext(ctx).Where("follower_id = ?", userID), q, "followee_id")
+}
+
+// page desempata por el otro extremo del follow, que es único para un usuario fijo.
+func (r FollowRepoGorm) page(tx *gorm.DB, q ports.PageQuery, idCol string) (ports.Page[domain.Follow], error) {
+	var rows []FollowModel
+	if err := keyset(tx, q, "created_at", idCol).Find(&rows).Error; err != nil {
+		return ports.Page[domain.Follow]{}, err
+	}
+	out := make([]domain.Follow, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, domain.Follow{ID: m.ID, FollowerID: m.FollowerID, FolloweeID: m.FolloweeID, CreatedAt: m.CreatedAt})
+	}
+	return finishPage(out, q), nil
+}
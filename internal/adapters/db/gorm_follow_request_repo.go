@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type FollowRequestModel struct {
+	ID          string `gorm:"primaryKey"`
+	RequesterID string `gorm:"index:idx_follow_requests_pair,unique"`
+	TargetID    string `gorm:"index:idx_follow_requests_pair,unique"`
+	CreatedAt   int64
+}
+
+func (FollowRequestModel) TableName() string { return "follow_requests" }
+
+func (m FollowRequestModel) toDomain() domain.FollowRequest {
+	return domain.FollowRequest{ID: m.ID, RequesterID: m.RequesterID, TargetID: m.TargetID, CreatedAt: m.CreatedAt}
+}
+
+type FollowRequestRepoGorm struct{ db *gorm.DB }
+
+func NewFollowRequestRepoGorm(db *gorm.DB) FollowRequestRepoGorm {
+	return FollowRequestRepoGorm{db: db}
+}
+
+func (r FollowRequestRepoGorm) Create(ctx context.Context, fr *domain.FollowRequest) error {
+	m := FollowRequestModel{ID: fr.ID, RequesterID: fr.RequesterID, TargetID: fr.TargetID, CreatedAt: fr.CreatedAt}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "requester_id"}, {Name: "target_id"}},
+		DoNothing: true,
+	}).Create(&m).Error
+}
+
+func (r FollowRequestRepoGorm) Get(ctx context.Context, requesterID, targetID string) (domain.FollowRequest, error) {
+	var m FollowRequestModel
+	err := r.db.WithContext(ctx).Where("requester_id = ? AND target_id = ?", requesterID, targetID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.FollowRequest{}, domain.ErrFollowRequestNotFound
+	}
+	if err != nil {
+		return domain.FollowRequest{}, err
+	}
+	return m.toDomain(), nil
+}
+
+func (r FollowRequestRepoGorm) Delete(ctx context.Context, requesterID, targetID string) error {
+	return r.db.WithContext(ctx).
+		Where("requester_id = ? AND target_id = ?", requesterID, targetID).
+		Delete(&FollowRequestModel{}).Error
+}
+
+func (r FollowRequestRepoGorm) Page(ctx context.Context, targetID string, q ports.PageQuery) (ports.Page[domain.FollowRequest], error) {
+	var rows []FollowRequestModel
+	tx := r.db.WithContext(ctx).Where("target_id = ?", targetID)
+	if err := keyset(tx, q, "created_at", "requester_id").Find(&rows).Error; err != nil {
+		return ports.Page[domain.FollowRequest]{}, err
+	}
+	out := make([]domain.FollowRequest, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, m.toDomain())
+	}
+	return finishPage(out, q), nil
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// TweetHashtagModel indexa los hashtags normalizados de cada tweet; CreatedAt
+// copia el del tweet para paginar el timeline del tag sin join previo.
+type TweetHashtagModel struct {
+	TweetID   string `gorm:"primaryKey"`
+	Tag       string `gorm:"primaryKey"`
+	CreatedAt int64
+}
+
+func (TweetHashtagModel) TableName() string { return "tweet_hashtags" }
+
+func indexHashtags(tx *gorm.DB, tweetID string, createdAt int64, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	rows := make([]TweetHashtagModel, 0, len(tags))
+	for _, tag := range tags {
+		rows = append(rows, TweetHashtagModel{TweetID: tweetID, Tag: tag, CreatedAt: createdAt})
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r TweetRepoGorm) HashtagTimelinePage(ctx context.Context, tag string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	var rows []TweetModel
+	tx := r.db.WithContext(ctx).
+		Table("tweet_hashtags AS h").
+		Select("t.*").
+		Joins("JOIN tweet_models t ON t.id = h.tweet_id AND t.deleted_at IS NULL").
+		Where("h.tag = ?", tag)
+	if err := keyset(tx, q, "h.created_at", "h.tweet_id").Find(&rows).Error; err != nil {
+		return ports.Page[domain.Tweet]{}, err
+	}
+	out := make([]domain.Tweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, r.toDomain(m))
+	}
+	return finishPage(out, q), nil
+}
+
+// ReindexHashtags recalcula el índice de hashtags de todos los tweets vivos.
+// Es idempotente; sirve para tweets anteriores a la migración 0010 o si
+// cambian las reglas de extracción.
+func ReindexHashtags(ctx context.Context, db *gorm.DB) (indexed int, err error) {
+	var batch []TweetModel
+	res := db.WithContext(ctx).Scopes(live).Select("id", "text", "created_at").
+		FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+			return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				for _, m := range batch {
+					if err := tx.Where("tweet_id = ?", m.ID).Delete(&TweetHashtagModel{}).Error; err != nil {
+						return err
+					}
+					if err := indexHashtags(tx, m.ID, m.CreatedAt, domain.ExtractHashtags(m.Text)); err != nil {
+						return err
+					}
+				}
+				indexed += len(batch)
+				return nil
+			})
+		})
+	return indexed, res.Error
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type HomeTimelineEntryModel struct {
+	OwnerID   string `gorm:"primaryKey"`
+	TweetID   string `gorm:"primaryKey"`
+	AuthorID  string
+	CreatedAt int64
+}
+
+func (HomeTimelineEntryModel) TableName() string { return "home_timeline_entries" }
+
+type HeavyAuthorModel struct {
+	AuthorID string `gorm:"primaryKey"`
+	MarkedAt int64
+}
+
+func (HeavyAuthorModel) TableName() string { return "fanout_heavy_authors" }
+
+type HomeTimelineRepoGorm struct{ db *gorm.DB }
+
+func NewHomeTimelineRepoGorm(db *gorm.DB) HomeTimelineRepoGorm { return HomeTimelineRepoGorm{db: db} }
+
+func (r HomeTimelineRepoGorm) insert(ctx context.Context, rows []HomeTimelineEntryModel) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(rows, 500).Error
+}
+
+func (r HomeTimelineRepoGorm) Push(ctx context.Context, ownerIDs []string, t domain.Tweet) error {
+	rows := make([]HomeTimelineEntryModel, 0, len(ownerIDs))
+	for _, owner := range ownerIDs {
+		rows = append(rows, HomeTimelineEntryModel{OwnerID: owner, TweetID: t.ID, AuthorID: t.UserID, CreatedAt: t.CreatedAt})
+	}
+	return r.insert(ctx, rows)
+}
+
+func (r HomeTimelineRepoGorm) Backfill(ctx context.Context, ownerID string, tweets []domain.Tweet) error {
+	rows := make([]HomeTimelineEntryModel, 0, len(tweets))
+	for _, t := range tweets {
+		rows = append(rows, HomeTimelineEntryModel{OwnerID: ownerID, TweetID: t.ID, AuthorID: t.UserID, CreatedAt: t.CreatedAt})
+	}
+	return r.insert(ctx, rows)
+}
+
+func (r HomeTimelineRepoGorm) PurgeAuthor(ctx context.Context, ownerID, authorID string) error {
+	return r.db.WithContext(ctx).
+		Where("owner_id = ? AND author_id = ?", ownerID, authorID).
+		Delete(&HomeTimelineEntryModel{}).Error
+}
+
+func (r HomeTimelineRepoGorm) RemoveTweet(ctx context.Context, tweetID string) error {
+	return r.db.WithContext(ctx).Where("tweet_id = ?", tweetID).Delete(&HomeTimelineEntryModel{}).Error
+}
+
+func (r HomeTimelineRepoGorm) Page(ctx context.Context, ownerID string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	var rows []TweetModel
+	tx := r.db.WithContext(ctx).
+		Table("home_timeline_entries AS e").
+		Select("t.*").
+		Joins("JOIN tweet_models t ON t.id = e.tweet_id AND t.deleted_at IS NULL").
+		Where("e.owner_id = ?", ownerID)
+	if err := keyset(tx, q, "e.created_at", "e.tweet_id").Find(&rows).Error; err != nil {
+		return ports.Page[domain.Tweet]{}, err
+	}
+	out := make([]domain.Tweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, TweetRepoGorm{}.toDomain(m))
+	}
+	return finishPage(out, q), nil
+}
+
+func (r HomeTimelineRepoGorm) MarkHeavy(ctx context.Context, authorID string, markedAt int64) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&HeavyAuthorModel{AuthorID: authorID, MarkedAt: markedAt}).Error
+}
+
+func (r HomeTimelineRepoGorm) IsHeavy(ctx context.Context, authorID string) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&HeavyAuthorModel{}).Where("author_id = ?", authorID).Count(&n).Error
+	return n > 0, err
+}
+
+func (r HomeTimelineRepoGorm) HeavyFollowees(ctx context.Context, followerID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Table("fanout_heavy_authors AS h").
+		Joins("JOIN follow_models f ON f.followee_id = h.author_id").
+		Where("f.follower_id = ?", followerID).
+		Pluck("h.author_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type LikeModel struct {
+	ID        string `gorm:"primaryKey"`
+	UserID    string `gorm:"index:idx_likes_pair,unique"`
+	TweetID   string `gorm:"index:idx_likes_pair,unique"`
+	CreatedAt int64
+}
+
+func (LikeModel) TableName() string { return "likes" }
+
+type LikeRepoGorm struct{ db *gorm.DB }
+
+func NewLikeRepoGorm(db *gorm.DB) LikeRepoGorm { return LikeRepoGorm{db: db} }
+
+// Create inserta el like y suma al contador sólo si la fila es nueva.
+func (r LikeRepoGorm) Create(ctx context.Context, l *domain.Like) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		m := LikeModel{ID: l.ID, UserID: l.UserID, TweetID: l.TweetID, CreatedAt: l.CreatedAt}
+		res := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "tweet_id"}},
+			DoNothing: true,
+		}).Create(&m)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&TweetModel{}).Where("id = ?", l.TweetID).
+			UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+	})
+}
+
+func (r LikeRepoGorm) Unlike(ctx context.Context, userID, tweetID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ? AND tweet_id = ?", userID, tweetID).Delete(&LikeModel{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Model(&TweetModel{}).Where("id = ? AND like_count > 0", tweetID).
+			UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error
+	})
+}
+
+func (r LikeRepoGorm) LikersPage(ctx context.Context, tweetID string, q ports.PageQuery) (ports.Page[domain.Like], error) {
+	var rows []LikeModel
+	err := keyset(r.db.WithContext(ctx).Where("tweet_id = ?", tweetID), q, "created_at", "user_id").
+		Find(&rows).Error
+	if err != nil {
+		return ports.Page[domain.Like]{}, err
+	}
+	out := make([]domain.Like, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, domain.Like{ID: m.ID, UserID: m.UserID, TweetID: m.TweetID, CreatedAt: m.CreatedAt})
+	}
+	return finishPage(out, q), nil
+}
+
+type likedTweetRow struct {
+	TweetModel
+	LikedAt int64
+}
+
+func (r LikeRepoGorm) LikedTweetsPage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.LikedTweet], error) {
+	var rows []likedTweetRow
+	tx := r.db.WithContext(ctx).
+		Table("likes AS l").
+		Select("t.*, l.created_at AS liked_at").
+		Joins("JOIN tweet_models t ON t.id = l.tweet_id AND t.deleted_at IS NULL").
+		Where("l.user_id = ?", userID)
+	if err := keyset(tx, q, "l.created_at", "l.tweet_id").Find(&rows).Error; err != nil {
+		return ports.Page[domain.LikedTweet]{}, err
+	}
+	out := make([]domain.LikedTweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, domain.LikedTweet{Tweet: TweetRepoGorm{}.toDomain(m.TweetModel), LikedAt: m.LikedAt})
+	}
+	return finishPage(out, q), nil
+}
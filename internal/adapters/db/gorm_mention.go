@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// TweetMentionModel indexa a quién menciona cada tweet (una fila por usuario,
+// aunque aparezca varias veces); los offsets viven en tweet_models.mentions.
+type TweetMentionModel struct {
+	TweetID   string `gorm:"primaryKey"`
+	UserID    string `gorm:"primaryKey"`
+	CreatedAt int64
+}
+
+func (TweetMentionModel) TableName() string { return "tweet_mentions" }
+
+func indexMentions(tx *gorm.DB, tweetID string, createdAt int64, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	rows := make([]TweetMentionModel, 0, len(userIDs))
+	for _, id := range userIDs {
+		rows = append(rows, TweetMentionModel{TweetID: tweetID, UserID: id, CreatedAt: createdAt})
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// mentionsJSON replica el serializer del modelo para Updates con map.
+func mentionsJSON(ms []domain.Mention) string {
+	b, _ := json.Marshal(ms)
+	return string(b)
+}
+
+func (r TweetRepoGorm) MentionTimelinePage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	var rows []TweetModel
+	tx := r.db.WithContext(ctx).
+		Table("tweet_mentions AS m").
+		Select("t.*").
+		Joins("JOIN tweet_models t ON t.id = m.tweet_id AND t.deleted_at IS NULL").
+		Where("m.user_id = ?", userID)
+	if err := keyset(tx, q, "m.created_at", "m.tweet_id").Find(&rows).Error; err != nil {
+		return ports.Page[domain.Tweet]{}, err
+	}
+	out := make([]domain.Tweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, r.toDomain(m))
+	}
+	return finishPage(out, q), nil
+}
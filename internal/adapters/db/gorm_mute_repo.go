@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+)
+
+type MuteModel struct {
+	ID        string `gorm:"primaryKey"`
+	UserID    string `gorm:"index:idx_mutes_user_value,unique"`
+	Kind      string `gorm:"index:idx_mutes_user_value,unique"`
+	Value     string `gorm:"index:idx_mutes_user_value,unique"`
+	ExpiresAt int64
+	CreatedAt int64
+}
+
+func (MuteModel) TableName() string { return "mutes" }
+
+func (m MuteModel) toDomain() domain.Mute {
+	return domain.Mute{ID: m.ID, UserID: m.UserID, Kind: m.Kind, Value: m.Value, ExpiresAt: m.ExpiresAt, CreatedAt: m.CreatedAt}
+}
+
+type MuteRepoGorm struct{ db *gorm.DB }
+
+func NewMuteRepoGorm(db *gorm.DB) MuteRepoGorm { return MuteRepoGorm{db: db} }
+
+func (r MuteRepoGorm) Create(ctx context.Context, m *domain.Mute) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := MuteModel{ID: m.ID, UserID: m.UserID, Kind: m.Kind, Value: m.Value, ExpiresAt: m.ExpiresAt, CreatedAt: m.CreatedAt}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}, {Name: "value"}},
+			DoUpdates: clause.AssignmentColumns([]string{"expires_at"}),
+		}).Create(&row).Error
+		if err != nil {
+			return err
+		}
+		var stored MuteModel
+		if err := tx.Where("user_id = ? AND kind = ? AND value = ?", m.UserID, m.Kind, m.Value).First(&stored).Error; err != nil {
+			return err
+		}
+		*m = stored.toDomain()
+		return nil
+	})
+}
+
+func (r MuteRepoGorm) Delete(ctx context.Context, userID, id string) error {
+	return r.db.WithContext(ctx).Where("user_id = ? AND id = ?", userID, id).Delete(&MuteModel{}).Error
+}
+
+func (r MuteRepoGorm) Active(ctx context.Context, userID string, now int64) ([]domain.Mute, error) {
+	var rows []MuteModel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND (expires_at = 0 OR expires_at > ?)", userID, now).
+		Order("created_at DESC, id DESC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.Mute, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, m.toDomain())
+	}
+	return out, nil
+}
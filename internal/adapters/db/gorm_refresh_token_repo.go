@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"tweetschallenge/internal/domain"
+)
+
+type RefreshTokenModel struct {
+	ID        string `gorm:"primaryKey"`
+	UserID    string
+	APIKeyID  string // '' en los anteriores a la migración 0021
+	Hash      string // idx_refresh_tokens_hash (único)
+	Scopes    string // separados por espacio, como el claim `scope`
+	CreatedAt int64
+	ExpiresAt int64
+	RevokedAt *int64
+}
+
+func (RefreshTokenModel) TableName() string { return "refresh_tokens" }
+
+type RefreshTokenRepoGorm struct{ db *gorm.DB }
+
+func NewRefreshTokenRepoGorm(db *gorm.DB) RefreshTokenRepoGorm { return RefreshTokenRepoGorm{db: db} }
+
+func refreshToModel(t domain.RefreshToken) RefreshTokenModel {
+	m := RefreshTokenModel{
+		ID: t.ID, UserID: t.UserID, APIKeyID: t.APIKeyID, Hash: t.Hash, Scopes: domain.JoinScopes(t.Scopes),
+		CreatedAt: t.CreatedAt, ExpiresAt: t.ExpiresAt,
+	}
+	if t.RevokedAt != 0 {
+		m.RevokedAt = &t.RevokedAt
+	}
+	return m
+}
+
+func (m RefreshTokenModel) toDomain() domain.RefreshToken {
+	t := domain.RefreshToken{
+		ID: m.ID, UserID: m.UserID, APIKeyID: m.APIKeyID, Hash: m.Hash, Scopes: domain.SplitScopes(m.Scopes),
+		CreatedAt: m.CreatedAt, ExpiresAt: m.ExpiresAt,
+	}
+	if m.RevokedAt != nil {
+		t.RevokedAt = *m.RevokedAt
+	}
+	return t
+}
+
+func (r RefreshTokenRepoGorm) Create(ctx context.Context, t *domain.RefreshToken) error {
+	m := refreshToModel(*t)
+	return r.db.WithContext(ctx).Create(&m).Error
+}
+
+func (r RefreshTokenRepoGorm) ByHash(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	var m RefreshTokenModel
+	err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.RefreshToken{}, domain.ErrUnauthenticated
+	}
+	if err != nil {
+		return domain.RefreshToken{}, err
+	}
+	return m.toDomain(), nil
+}
+
+func (r RefreshTokenRepoGorm) Rotate(ctx context.Context, oldID string, next *domain.RefreshToken, at int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// el UPDATE condicionado serializa refresh concurrentes del mismo token
+		res := tx.Model(&RefreshTokenModel{}).Where("id = ? AND revoked_at IS NULL", oldID).Update("revoked_at", at)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrUnauthenticated
+		}
+		m := refreshToModel(*next)
+		return tx.Create(&m).Error
+	})
+}
+
+func (r RefreshTokenRepoGorm) RevokeUser(ctx context.Context, userID string, at int64) error {
+	return r.db.WithContext(ctx).Model(&RefreshTokenModel{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", at).Error
+}
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// tweet_search es una tabla FTS5 de contenido externo sobre tweet_models
+// (rowid = rowid del tweet); la mantienen triggers, así que cualquier escritura
+// de text (crear, editar, tombstone) queda indexada en la misma transacción.
+// No va en una migración porque FTS5 depende de compilar con -tags sqlite_fts5.
+var searchIndexDDL = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS tweet_search USING fts5(
+		text, content='tweet_models', tokenize='unicode61 remove_diacritics 2')`,
+	`CREATE TRIGGER IF NOT EXISTS tweet_search_ai AFTER INSERT ON tweet_models BEGIN
+		INSERT INTO tweet_search(rowid, text) VALUES (new.rowid, new.text);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS tweet_search_ad AFTER DELETE ON tweet_models BEGIN
+		INSERT INTO tweet_search(tweet_search, rowid, text) VALUES ('delete', old.rowid, old.text);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS tweet_search_au AFTER UPDATE OF text ON tweet_models BEGIN
+		INSERT INTO tweet_search(tweet_search, rowid, text) VALUES ('delete', old.rowid, old.text);
+		INSERT INTO tweet_search(rowid, text) VALUES (new.rowid, new.text);
+	END`,
+}
+
+// TweetSearchGorm busca con FTS5 si está disponible. Sin FTS5 (build sin el
+// tag o PostgreSQL) cae a LIKE por término: mismo filtrado y paginación, pero
+// sin ranking por relevancia ni plegado de acentos.
+type TweetSearchGorm struct {
+	db  *gorm.DB
+	fts bool
+}
+
+func NewTweetSearchGorm(ctx context.Context, db *gorm.DB) (TweetSearchGorm, error) {
+	fts, err := EnsureSearchIndex(ctx, db)
+	if err != nil {
+		return TweetSearchGorm{}, err
+	}
+	return TweetSearchGorm{db: db, fts: fts}, nil
+}
+
+// FTS indica si las búsquedas usan el índice FTS5.
+func (s TweetSearchGorm) FTS() bool { return s.fts }
+
+// EnsureSearchIndex crea tweet_search y sus triggers si faltan. Cuando los
+// triggers no existían (primera vez, o tweet_models se recreó) reconstruye el
+// índice desde tweet_models. Devuelve false si SQLite se compiló sin FTS5
+// o el motor es PostgreSQL.
+func EnsureSearchIndex(ctx context.Context, db *gorm.DB) (bool, error) {
+	if db.Dialector.Name() != "sqlite" {
+		return false, nil
+	}
+	tx := db.WithContext(ctx)
+	var fts5 bool
+	if err := tx.Raw(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&fts5).Error; err != nil || !fts5 {
+		return false, err
+	}
+	var triggers int64
+	err := tx.Raw(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger'
+		AND name IN ('tweet_search_ai', 'tweet_search_ad', 'tweet_search_au')`).Scan(&triggers).Error
+	if err != nil {
+		return false, err
+	}
+	return true, tx.Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range searchIndexDDL {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		if triggers < int64(len(searchIndexDDL)-1) {
+			return tx.Exec(`INSERT INTO tweet_search(tweet_search) VALUES ('rebuild')`).Error
+		}
+		return nil
+	})
+}
+
+// RebuildSearchIndex regenera tweet_search desde tweet_models. Hace falta
+// tras un VACUUM, que puede renumerar los rowid de tweet_models. Devuelve
+// false (sin hacer nada) si no hay FTS5.
+func RebuildSearchIndex(ctx context.Context, db *gorm.DB) (bool, error) {
+	fts, err := EnsureSearchIndex(ctx, db)
+	if err != nil || !fts {
+		return false, err
+	}
+	return true, db.WithContext(ctx).Exec(`INSERT INTO tweet_search(tweet_search) VALUES ('rebuild')`).Error
+}
+
+type searchRow struct {
+	TweetModel `gorm:"embedded"`
+	Score      int64
+}
+
+func (s TweetSearchGorm) Search(ctx context.Context, q domain.SearchQuery, pq ports.PageQuery) (ports.Page[domain.SearchHit], error) {
+	score := "t.created_at"
+	sub := s.db.WithContext(ctx).Table("tweet_models AS t").
+		Where("t.deleted_at IS NULL AND t.retweet_of_id = ''")
+	switch {
+	case s.fts && q.HasText():
+		if q.Order == domain.SearchRelevance {
+			// bm25 es menor cuanto más relevante; se escala a entero para el cursor.
+			score = "CAST(-bm25(tweet_search) * 1000000 AS INTEGER)"
+		}
+		sub = sub.Joins("JOIN tweet_search ON tweet_search.rowid = t.rowid").
+			Where("tweet_search MATCH ?", ftsMatch(q))
+	default:
+		for _, term := range append(append([]string{}, q.Terms...), q.Phrases...) {
+			sub = sub.Where(`LOWER(t.text) LIKE ? ESCAPE '\'`, "%"+likeEscape(strings.ToLower(term))+"%")
+		}
+	}
+	if q.From != "" {
+		sub = sub.Where("t.user_id = ?", q.From)
+	}
+	if q.Since != 0 {
+		sub = sub.Where("t.created_at >= ?", q.Since)
+	}
+	if q.Until != 0 {
+		sub = sub.Where("t.created_at < ?", q.Until)
+	}
+	sub = sub.Select("t.*, " + score + " AS score")
+
+	var rows []searchRow
+	tx := s.db.WithContext(ctx).Table("(?) AS s", sub).Select("s.*")
+	if err := keyset(tx, pq, "s.score", "s.id").Find(&rows).Error; err != nil {
+		return ports.Page[domain.SearchHit]{}, err
+	}
+	repo := TweetRepoGorm{db: s.db}
+	out := make([]domain.SearchHit, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, domain.SearchHit{Tweet: repo.toDomain(r.TweetModel), Score: r.Score})
+	}
+	return finishPage(out, pq), nil
+}
+
+// ftsMatch arma la expresión MATCH: cada término y frase va entre comillas
+// (escapadas) para que nada del usuario se interprete como sintaxis FTS5.
+func ftsMatch(q domain.SearchQuery) string {
+	parts := make([]string, 0, len(q.Terms)+len(q.Phrases))
+	for _, s := range append(append([]string{}, q.Terms...), q.Phrases...) {
+		parts = append(parts, `"`+strings.ReplaceAll(s, `"`, `""`)+`"`)
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,384 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// ----------------------------------------------------------------------------
+// Model & Repo
+// ----------------------------------------------------------------------------
+
+// idx_tweets_user_created_id cubre `user_id IN (...) ORDER BY created_at DESC, id DESC`
+// (keyset) sin sort adicional tanto en SQLite como en PostgreSQL.
+type TweetModel struct {
+	ID        string `gorm:"primaryKey;index:idx_tweets_user_created_id,priority:3,sort:desc"`
+	UserID    string `gorm:"index;index:idx_tweets_user_created_id,priority:1"`
+	Text      string // TEXT: 280 grafemas pueden ocupar muchos más bytes/code points
+	CreatedAt int64  `gorm:"index;index:idx_tweets_user_created_id,priority:2,sort:desc"`
+	EditedAt  int64
+	DeletedAt *int64
+	// Respuestas: Depth y ThreadPath (raíz/.../id) se calculan al crear a partir del padre.
+	InReplyToID    string
+	ConversationID string `gorm:"index:idx_tweets_conversation_path,priority:1"`
+	Depth          int
+	ThreadPath     string `gorm:"index:idx_tweets_conversation_path,priority:2"`
+	// idx_tweets_user_retweet (parcial, ver migración 0007): un retweet vivo por usuario.
+	RetweetOfID   string
+	QuotedTweetID string
+	LikeCount     int64 // lo mantiene LikeRepoGorm
+	// Menciones ya resueltas, con offsets; tweet_mentions es el índice por usuario.
+	Mentions []domain.Mention `gorm:"serializer:json"`
+}
+
+type TweetRevisionModel struct {
+	ID         string `gorm:"primaryKey"`
+	TweetID    string
+	Revision   int
+	Text       string
+	CreatedAt  int64
+	ReplacedAt int64
+}
+
+func (TweetRevisionModel) TableName() string { return "tweet_revisions" }
+
+type TweetRepoGorm struct{ db *gorm.DB }
+
+func NewTweetRepoGorm(db *gorm.DB) TweetRepoGorm { return TweetRepoGorm{db: db} }
+
+func (r TweetRepoGorm) toDomain(m TweetModel) domain.Tweet {
+	t := domain.Tweet{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		Text:      m.Text,
+		CreatedAt: m.CreatedAt,
+		EditedAt:  m.EditedAt,
+
+		InReplyToID:    m.InReplyToID,
+		ConversationID: m.ConversationID,
+		RetweetOfID:    m.RetweetOfID,
+		QuotedTweetID:  m.QuotedTweetID,
+		LikeCount:      m.LikeCount,
+		Hashtags:       domain.ExtractHashtags(m.Text),
+		Mentions:       m.Mentions,
+	}
+	if m.DeletedAt != nil {
+		t.DeletedAt = *m.DeletedAt
+	}
+	return t
+}
+
+// live excluye tombstones.
+func live(tx *gorm.DB) *gorm.DB { return tx.Where("deleted_at IS NULL") }
+
+func (r TweetRepoGorm) Create(ctx context.Context, t *domain.Tweet) error {
+	m := TweetModel{
+		ID: t.ID, UserID: t.UserID, Text: t.Text, CreatedAt: t.CreatedAt,
+		InReplyToID: t.InReplyToID, ConversationID: t.ConversationID, ThreadPath: t.ID,
+		RetweetOfID: t.RetweetOfID, QuotedTweetID: t.QuotedTweetID, Mentions: t.Mentions,
+	}
+	if m.ConversationID == "" {
+		m.ConversationID = t.ID
+	}
+	tx := r.db.WithContext(ctx)
+	if t.InReplyToID != "" {
+		// El padre puede haberse borrado después de validarlo: la respuesta
+		// igual se cuelga de su tombstone.
+		var parent TweetModel
+		err := tx.Select("depth", "thread_path").Where("id = ?", t.InReplyToID).First(&parent).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		m.Depth = parent.Depth + 1
+		m.ThreadPath = parent.ThreadPath + "/" + t.ID
+	}
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&m).Error; err != nil {
+			return err
+		}
+		if err := indexMentions(tx, m.ID, m.CreatedAt, domain.MentionedIDs(m.Mentions)); err != nil {
+			return err
+		}
+		if err := indexHashtags(tx, m.ID, m.CreatedAt, domain.ExtractHashtags(m.Text)); err != nil {
+			return err
+		}
+		return bumpTweetCount(tx, m, 1)
+	})
+}
+
+// bumpTweetCount ajusta users.tweet_count; los retweets no cuentan.
+func bumpTweetCount(tx *gorm.DB, m TweetModel, delta int) error {
+	if m.RetweetOfID != "" {
+		return nil
+	}
+	return bumpUserCounter(tx, m.UserID, "tweet_count", delta)
+}
+
+func (r TweetRepoGorm) Get(ctx context.Context, id string) (domain.Tweet, error) {
+	var m TweetModel
+	err := r.db.WithContext(ctx).Scopes(live).Where("id = ?", id).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Tweet{}, domain.ErrTweetNotFound
+	}
+	if err != nil {
+		return domain.Tweet{}, err
+	}
+	return r.toDomain(m), nil
+}
+
+func (r TweetRepoGorm) GetMany(ctx context.Context, ids []string) ([]domain.Tweet, error) {
+	if len(ids) == 0 {
+		return []domain.Tweet{}, nil
+	}
+	var rows []TweetModel
+	if err := r.db.WithContext(ctx).Scopes(live).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.Tweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, r.toDomain(m))
+	}
+	return out, nil
+}
+
+// Retweet inserta t salvo que userID ya tenga un retweet vivo del mismo
+// original; en ese caso carga el existente en t y devuelve false. El índice
+// parcial resuelve la carrera entre dos requests concurrentes.
+func (r TweetRepoGorm) Retweet(ctx context.Context, t *domain.Tweet) (bool, error) {
+	m := TweetModel{
+		ID: t.ID, UserID: t.UserID, CreatedAt: t.CreatedAt,
+		ConversationID: t.ID, ThreadPath: t.ID, RetweetOfID: t.RetweetOfID,
+	}
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "user_id"}, {Name: "retweet_of_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "retweet_of_id <> '' AND deleted_at IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&m)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+	existing, err := r.FindRetweet(ctx, t.UserID, t.RetweetOfID)
+	if err != nil {
+		return false, err
+	}
+	*t = existing
+	return false, nil
+}
+
+func (r TweetRepoGorm) FindRetweet(ctx context.Context, userID, originalID string) (domain.Tweet, error) {
+	var m TweetModel
+	err := r.db.WithContext(ctx).Scopes(live).
+		Where("user_id = ? AND retweet_of_id = ?", userID, originalID).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Tweet{}, domain.ErrTweetNotFound
+	}
+	if err != nil {
+		return domain.Tweet{}, err
+	}
+	return r.toDomain(m), nil
+}
+
+func (r TweetRepoGorm) Edit(ctx context.Context, t *domain.Tweet, rev *domain.TweetRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&TweetModel{}).Scopes(live).
+			Where("id = ?", t.ID).
+			Updates(map[string]any{"text": t.Text, "edited_at": t.EditedAt, "mentions": mentionsJSON(t.Mentions)})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrTweetNotFound
+		}
+		if err := tx.Where("tweet_id = ?", t.ID).Delete(&TweetHashtagModel{}).Error; err != nil {
+			return err
+		}
+		if err := indexHashtags(tx, t.ID, t.CreatedAt, domain.ExtractHashtags(t.Text)); err != nil {
+			return err
+		}
+		if err := tx.Where("tweet_id = ?", t.ID).Delete(&TweetMentionModel{}).Error; err != nil {
+			return err
+		}
+		if err := indexMentions(tx, t.ID, t.CreatedAt, domain.MentionedIDs(t.Mentions)); err != nil {
+			return err
+		}
+		var last int
+		if err := tx.Model(&TweetRevisionModel{}).Where("tweet_id = ?", t.ID).
+			Select("COALESCE(MAX(revision), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		rev.Revision = last + 1
+		m := TweetRevisionModel{
+			ID: rev.ID, TweetID: rev.TweetID, Revision: rev.Revision,
+			Text: rev.Text, CreatedAt: rev.CreatedAt, ReplacedAt: rev.ReplacedAt,
+		}
+		return tx.Create(&m).Error
+	})
+}
+
+func (r TweetRepoGorm) Revisions(ctx context.Context, tweetID string) ([]domain.TweetRevision, error) {
+	var rows []TweetRevisionModel
+	if err := r.db.WithContext(ctx).Where("tweet_id = ?", tweetID).Order("revision").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.TweetRevision, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, domain.TweetRevision{
+			ID: m.ID, TweetID: m.TweetID, Revision: m.Revision,
+			Text: m.Text, CreatedAt: m.CreatedAt, ReplacedAt: m.ReplacedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r TweetRepoGorm) SoftDelete(ctx context.Context, id string, deletedAt int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m TweetModel
+		err := tx.Scopes(live).Select("id", "user_id", "retweet_of_id").Where("id = ?", id).First(&m).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrTweetNotFound
+		}
+		if err != nil {
+			return err
+		}
+		res := tx.Model(&TweetModel{}).Scopes(live).
+			Where("id = ?", id).
+			Updates(map[string]any{"deleted_at": deletedAt, "text": ""})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return domain.ErrTweetNotFound
+		}
+		return bumpTweetCount(tx, m, -1)
+	})
+}
+
+// HardDelete es para moderación: a diferencia de SoftDelete no deja
+// tombstone. Las respuestas sobreviven colgando de un padre inexistente, como
+// si el padre se hubiera borrado antes de crearlas.
+func (r TweetRepoGorm) HardDelete(ctx context.Context, id string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&TweetModel{}).Where("id = ? OR retweet_of_id = ?", id, id).
+			Order("id").Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if !slices.Contains(ids, id) {
+			return domain.ErrTweetNotFound
+		}
+		var m TweetModel
+		if err := tx.Select("id", "user_id", "retweet_of_id", "deleted_at").Where("id = ?", id).First(&m).Error; err != nil {
+			return err
+		}
+		if m.DeletedAt == nil {
+			if err := bumpTweetCount(tx, m, -1); err != nil {
+				return err
+			}
+		}
+		for _, m := range []any{&TweetRevisionModel{}, &TweetHashtagModel{}, &TweetMentionModel{}, &LikeModel{}} {
+			if err := tx.Where("tweet_id IN ?", ids).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("id IN ?", ids).Delete(&TweetModel{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r TweetRepoGorm) Timeline(ctx context.Context, userID string, limit, offset int) ([]domain.Tweet, error) {
+	limit = ports.PageLimit(limit)
+	var rows []TweetModel
+	err := r.db.WithContext(ctx).Scopes(live).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.Tweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, r.toDomain(m))
+	}
+	return out, nil
+}
+
+func (r TweetRepoGorm) TimelineForUsers(ctx context.Context, userIDs []string, limit, offset int) ([]domain.Tweet, error) {
+	if len(userIDs) == 0 {
+		return []domain.Tweet{}, nil
+	}
+	limit = ports.PageLimit(limit)
+	var rows []TweetModel
+	err := r.db.WithContext(ctx).Scopes(live).
+		Where("user_id IN ?", userIDs).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.Tweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, r.toDomain(m))
+	}
+	return out, nil
+}
+
+func (r TweetRepoGorm) TimelineForUsersPage(ctx context.Context, userIDs []string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	if len(userIDs) == 0 {
+		return ports.Page[domain.Tweet]{Items: []domain.Tweet{}}, nil
+	}
+	var rows []TweetModel
+	err := keyset(r.db.WithContext(ctx).Scopes(live).Where("user_id IN ?", userIDs), q, "created_at", "id").
+		Find(&rows).Error
+	if err != nil {
+		return ports.Page[domain.Tweet]{}, err
+	}
+	out := make([]domain.Tweet, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, r.toDomain(m))
+	}
+	return finishPage(out, q), nil
+}
+
+// Conversation devuelve el árbol de una conversación en orden DFS (cada
+// respuesta después de su padre, hermanos por antigüedad). Incluye tombstones.
+// El cursor pagina por thread_path: "older" avanza y "newer" retrocede.
+func (r TweetRepoGorm) Conversation(ctx context.Context, conversationID string, q ports.PageQuery) (ports.Page[domain.ThreadEntry], error) {
+	tx := r.db.WithContext(ctx).Where("conversation_id = ?", conversationID).Limit(ports.PageLimit(q.Limit) + 1)
+	switch c := q.Cursor; {
+	case c == nil:
+		tx = tx.Order("thread_path ASC")
+	case c.Newer:
+		tx = tx.Where("thread_path < ?", c.ID).Order("thread_path DESC")
+	default:
+		tx = tx.Where("thread_path > ?", c.ID).Order("thread_path ASC")
+	}
+	var rows []TweetModel
+	if err := tx.Find(&rows).Error; err != nil {
+		return ports.Page[domain.ThreadEntry]{}, err
+	}
+	out := make([]domain.ThreadEntry, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, domain.ThreadEntry{Tweet: r.toDomain(m), Depth: m.Depth, Path: m.ThreadPath})
+	}
+	return finishPage(out, q), nil
+}
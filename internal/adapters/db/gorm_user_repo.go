@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"tweetschallenge/internal/domain"
+)
+
+// UserModel: idx_users_handle_lower (migración 0012) hace único el handle
+// sin distinguir mayúsculas.
+type UserModel struct {
+	ID          string `gorm:"primaryKey"`
+	Handle      string
+	DisplayName string
+	Bio         string
+	CreatedAt   int64
+	UpdatedAt   int64 `gorm:"autoUpdateTime:false"` // lo fija el dominio (ports.Clock)
+	Role        string
+	SuspendedAt *int64
+	Protected   bool
+	// Los mantienen FollowRepoGorm y TweetRepoGorm (ver bumpUserCounter);
+	// Create/Update nunca los escriben.
+	FollowerCount  int64 `gorm:"->"`
+	FollowingCount int64 `gorm:"->"`
+	TweetCount     int64 `gorm:"->"`
+}
+
+func (UserModel) TableName() string { return "users" }
+
+type UserRepoGorm struct{ db *gorm.DB }
+
+func NewUserRepoGorm(db *gorm.DB) UserRepoGorm { return UserRepoGorm{db: db} }
+
+func userToModel(u domain.User) UserModel {
+	m := UserModel{ID: u.ID, Handle: u.Handle, DisplayName: u.DisplayName, Bio: u.Bio, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt, Role: u.Role, Protected: u.Protected}
+	if m.Role == "" {
+		m.Role = domain.RoleUser
+	}
+	if u.SuspendedAt != 0 {
+		m.SuspendedAt = &u.SuspendedAt
+	}
+	return m
+}
+
+func (m UserModel) toDomain() domain.User {
+	u := domain.User{
+		ID: m.ID, Handle: m.Handle, DisplayName: m.DisplayName, Bio: m.Bio, CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt,
+		FollowerCount: m.FollowerCount, FollowingCount: m.FollowingCount, TweetCount: m.TweetCount, Role: m.Role,
+		Protected: m.Protected,
+	}
+	if m.SuspendedAt != nil {
+		u.SuspendedAt = *m.SuspendedAt
+	}
+	return u
+}
+
+func (r UserRepoGorm) Create(ctx context.Context, u *domain.User) error {
+	m := userToModel(*u)
+	return handleConflict(r.db.WithContext(ctx).Create(&m).Error)
+}
+
+func (r UserRepoGorm) Get(ctx context.Context, id string) (domain.User, error) {
+	var m UserModel
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&m).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+	return m.toDomain(), nil
+}
+
+func (r UserRepoGorm) Update(ctx context.Context, u *domain.User) error {
+	m := userToModel(*u)
+	res := r.db.WithContext(ctx).Model(&UserModel{}).Where("id = ?", u.ID).
+		Select("handle", "display_name", "bio", "protected", "updated_at").Updates(&m)
+	if res.Error != nil {
+		return handleConflict(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r UserRepoGorm) ByHandles(ctx context.Context, handles []string) ([]domain.User, error) {
+	if len(handles) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(handles))
+	for _, h := range handles {
+		keys = append(keys, strings.ToLower(h))
+	}
+	var rows []UserModel
+	if err := r.db.WithContext(ctx).Where("LOWER(handle) IN ?", keys).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make([]domain.User, 0, len(rows))
+	for _, m := range rows {
+		out = append(out, m.toDomain())
+	}
+	return out, nil
+}
+
+func (r UserRepoGorm) SetRole(ctx context.Context, id, role string) error {
+	return r.set(ctx, id, "role", role)
+}
+
+func (r UserRepoGorm) SetSuspended(ctx context.Context, id string, at int64) error {
+	var v *int64
+	if at != 0 {
+		v = &at
+	}
+	return r.set(ctx, id, "suspended_at", v)
+}
+
+func (r UserRepoGorm) set(ctx context.Context, id, column string, value any) error {
+	res := r.db.WithContext(ctx).Model(&UserModel{}).Where("id = ?", id).Update(column, value)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r UserRepoGorm) SuspendedIDs(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var out []string
+	err := r.db.WithContext(ctx).Model(&UserModel{}).
+		Where("id IN ? AND suspended_at IS NOT NULL", ids).Pluck("id", &out).Error
+	return out, err
+}
+
+func (r UserRepoGorm) ProtectedIDs(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var out []string
+	err := r.db.WithContext(ctx).Model(&UserModel{}).
+		Where("id IN ? AND protected", ids).Pluck("id", &out).Error
+	return out, err
+}
+
+// bumpUserCounter suma delta a un contador de users dentro de tx; un id sin
+// usuario (datos previos a la migración 0012) no es error. Nunca baja de 0.
+func bumpUserCounter(tx *gorm.DB, userID, column string, delta int) error {
+	q := tx.Table("users").Where("id = ?", userID) // sin Model: las columnas son de sólo lectura ahí
+	if delta < 0 {
+		q = q.Where(column+" >= ?", -delta)
+	}
+	return q.UpdateColumn(column, gorm.Expr(column+" + ?", delta)).Error
+}
+
+// Conteos reales de cada contador (mismo criterio que la migración 0017).
+const (
+	followerCountSQL  = `(SELECT COUNT(*) FROM follow_models f WHERE f.followee_id = users.id)`
+	followingCountSQL = `(SELECT COUNT(*) FROM follow_models f WHERE f.follower_id = users.id)`
+	tweetCountSQL     = `(SELECT COUNT(*) FROM tweet_models t
+		WHERE t.user_id = users.id AND t.deleted_at IS NULL AND t.retweet_of_id = '')`
+)
+
+// ReconcileUserCounters recalcula los contadores desfasados (p. ej. por
+// escrituras fuera de la app) y devuelve cuántos usuarios corrigió.
+func ReconcileUserCounters(ctx context.Context, db *gorm.DB) (fixed int64, err error) {
+	res := db.WithContext(ctx).Exec(`UPDATE users SET
+		follower_count = ` + followerCountSQL + `,
+		following_count = ` + followingCountSQL + `,
+		tweet_count = ` + tweetCountSQL + `
+		WHERE follower_count <> ` + followerCountSQL + `
+		OR following_count <> ` + followingCountSQL + `
+		OR tweet_count <> ` + tweetCountSQL)
+	return res.RowsAffected, res.Error
+}
+
+// handleConflict: la única restricción única además de la PK (ULID) es el handle.
+func handleConflict(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrHandleTaken
+	}
+	return err
+}
@@ -0,0 +1,254 @@
+// Package migrate aplica migraciones SQL versionadas (up/down) registradas en
+// la tabla schema_migrations. Cada dialecto tiene su propio set de archivos
+// embebidos en sql/<dialecto>/NNNN_nombre.{up,down}.sql.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql
+var files embed.FS
+
+var ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt int64
+	Modified  bool // el archivo cambió después de aplicarse
+}
+
+type appliedRow struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt int64
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New arma un Migrator con las migraciones embebidas del dialecto de db.
+func New(db *gorm.DB) (*Migrator, error) {
+	dialect := db.Dialector.Name()
+	sub, err := fs.Sub(files, path.Join("sql", dialect))
+	if err != nil {
+		return nil, err
+	}
+	ms, err := Load(sub)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: load %s: %w", dialect, err)
+	}
+	if len(ms) == 0 {
+		return nil, fmt.Errorf("migrate: no migrations for dialect %q", dialect)
+	}
+	return &Migrator{db: db, migrations: ms}, nil
+}
+
+// Load lee pares NNNN_nombre.up.sql / NNNN_nombre.down.sql de fsys.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int]*Migration{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+		base := strings.TrimSuffix(name, ".sql")
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up"):
+			direction, base = "up", strings.TrimSuffix(base, ".up")
+		case strings.HasSuffix(base, ".down"):
+			direction, base = "down", strings.TrimSuffix(base, ".down")
+		default:
+			return nil, fmt.Errorf("%s: expected .up.sql or .down.sql", name)
+		}
+		num, label, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("%s: expected NNNN_name", name)
+		}
+		version, err := strconv.Atoi(num)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("%s: invalid version", name)
+		}
+		body, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("version %d used by %q and %q", version, m.Name, label)
+		}
+		if direction == "up" {
+			m.Up = string(body)
+			sum := sha256.Sum256(body)
+			m.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			m.Down = string(body)
+		}
+	}
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("version %d (%s): missing up migration", m.Version, m.Name)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	return m.db.WithContext(ctx).Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    BIGINT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		checksum   TEXT NOT NULL,
+		applied_at BIGINT NOT NULL
+	)`).Error
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedRow, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	var rows []appliedRow
+	if err := m.db.WithContext(ctx).Table("schema_migrations").Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	out := make(map[int]appliedRow, len(rows))
+	for _, r := range rows {
+		out[r.Version] = r
+	}
+	return out, nil
+}
+
+// verify falla si una migración aplicada cambió o ya no existe en el binario.
+func (m *Migrator) verify(applied map[int]appliedRow) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, mg := range m.migrations {
+		known[mg.Version] = mg
+	}
+	for v, row := range applied {
+		mg, ok := known[v]
+		if !ok {
+			return fmt.Errorf("migrate: version %d (%s) applied but unknown to this binary", v, row.Name)
+		}
+		if mg.Checksum != row.Checksum {
+			return fmt.Errorf("%w: version %d (%s)", ErrChecksumMismatch, v, mg.Name)
+		}
+	}
+	return nil
+}
+
+// Up aplica todas las migraciones pendientes en orden, cada una en su propia tx.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, mg := range m.migrations {
+		if _, ok := applied[mg.Version]; ok {
+			continue
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(mg.Up).Error; err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+				mg.Version, mg.Name, mg.Checksum, time.Now().Unix()).Error
+		})
+		if err != nil {
+			return n, fmt.Errorf("migrate: up %04d_%s: %w", mg.Version, mg.Name, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Down revierte las últimas `steps` migraciones aplicadas.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+	n := 0
+	for i := len(m.migrations) - 1; i >= 0 && n < steps; i-- {
+		mg := m.migrations[i]
+		if _, ok := applied[mg.Version]; !ok {
+			continue
+		}
+		if mg.Down == "" {
+			return n, fmt.Errorf("migrate: %04d_%s is irreversible", mg.Version, mg.Name)
+		}
+		err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(mg.Down).Error; err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", mg.Version).Error
+		})
+		if err != nil {
+			return n, fmt.Errorf("migrate: down %04d_%s: %w", mg.Version, mg.Name, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Status lista cada migración conocida con su estado; no falla ante checksums
+// distintos, los marca como Modified.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Status, 0, len(m.migrations))
+	for _, mg := range m.migrations {
+		st := Status{Version: mg.Version, Name: mg.Name}
+		if row, ok := applied[mg.Version]; ok {
+			st.Applied = true
+			st.AppliedAt = row.AppliedAt
+			st.Modified = row.Checksum != mg.Checksum
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
@@ -0,0 +1,170 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:migrate_%d?mode=memory&cache=shared", time.Now().UnixNano())
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+
+	n, err := m.Up(ctx)
+	if err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if n != len(m.migrations) {
+		t.Fatalf("applied %d, want %d", n, len(m.migrations))
+	}
+	if !db.Migrator().HasTable("tweet_models") || !db.Migrator().HasTable("follow_models") {
+		t.Fatal("expected tables after up")
+	}
+	if n, err := m.Up(ctx); err != nil || n != 0 {
+		t.Fatalf("second up should be a no-op, got n=%d err=%v", n, err)
+	}
+
+	list, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	for _, st := range list {
+		if !st.Applied || st.Modified {
+			t.Fatalf("unexpected status %+v", st)
+		}
+	}
+
+	if _, err := m.Down(ctx, len(m.migrations)); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if db.Migrator().HasTable("tweet_models") {
+		t.Fatal("expected tweet_models dropped after down")
+	}
+	list, _ = m.Status(ctx)
+	if list[0].Applied {
+		t.Fatalf("expected pending after down, got %+v", list[0])
+	}
+}
+
+func TestMigrator_ChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if err := db.Exec("UPDATE schema_migrations SET checksum = 'x' WHERE version = 1").Error; err != nil {
+		t.Fatalf("tamper: %v", err)
+	}
+
+	if _, err := m.Up(ctx); !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("want ErrChecksumMismatch, got %v", err)
+	}
+	list, err := m.Status(ctx)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if !list[0].Modified {
+		t.Fatalf("expected version 1 flagged as modified: %+v", list[0])
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ms, err := Load(fstest.MapFS{
+		"0002_second.up.sql":  {Data: []byte("CREATE TABLE b (id TEXT);")},
+		"0001_first.up.sql":   {Data: []byte("CREATE TABLE a (id TEXT);")},
+		"0001_first.down.sql": {Data: []byte("DROP TABLE a;")},
+		"README.md":           {Data: []byte("ignored")},
+	})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(ms) != 2 || ms[0].Version != 1 || ms[1].Version != 2 {
+		t.Fatalf("unexpected order: %+v", ms)
+	}
+	if ms[0].Down == "" || ms[1].Down != "" || ms[0].Checksum == "" {
+		t.Fatalf("unexpected contents: %+v", ms)
+	}
+
+	if _, err := Load(fstest.MapFS{"0001_a.down.sql": {Data: []byte("x")}}); err == nil {
+		t.Fatal("expected error for down without up")
+	}
+	if _, err := Load(fstest.MapFS{"first.up.sql": {Data: []byte("x")}}); err == nil {
+		t.Fatal("expected error for missing version")
+	}
+}
+
+func TestMigration0012_BackfillsUsers(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	m, err := New(db)
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	for m.migrations[len(m.migrations)-1].Version > 11 {
+		if _, err := m.Down(ctx, 1); err != nil {
+			t.Fatalf("down: %v", err)
+		}
+		m.migrations = m.migrations[:len(m.migrations)-1]
+	}
+	for _, q := range []string{
+		`INSERT INTO tweet_models (id, user_id, text, created_at) VALUES ('T1', 'ana', 'hola', 5), ('T2', 'ANA', 'dup', 7)`,
+		`INSERT INTO follow_models (id, follower_id, followee_id, created_at) VALUES ('F1', 'bob', 'ana', 3)`,
+	} {
+		if err := db.Exec(q).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	if m, err = New(db); err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up 0012: %v", err)
+	}
+	var rows []struct {
+		ID, Handle string
+		CreatedAt  int64
+	}
+	db.Raw("SELECT id, handle, created_at FROM users ORDER BY id").Scan(&rows)
+	if len(rows) != 2 || rows[0].ID != "ana" || rows[0].CreatedAt != 3 || rows[1].ID != "bob" {
+		t.Fatalf("unexpected backfill: %+v", rows)
+	}
+
+	// 0017 calcula los contadores sobre lo que dejó 0012.
+	var counts []struct{ FollowerCount, FollowingCount, TweetCount int64 }
+	db.Raw("SELECT follower_count, following_count, tweet_count FROM users ORDER BY id").Scan(&counts)
+	if len(counts) != 2 || counts[0].FollowerCount != 1 || counts[0].TweetCount != 1 || counts[1].FollowingCount != 1 {
+		t.Fatalf("unexpected counters: %+v", counts)
+	}
+}
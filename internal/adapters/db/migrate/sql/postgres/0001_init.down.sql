@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS follow_models;
+DROP TABLE IF EXISTS tweet_models;
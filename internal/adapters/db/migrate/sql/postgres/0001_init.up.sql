@@ -0,0 +1,19 @@
+-- Esquema inicial (equivalente al AutoMigrate previo; IF NOT EXISTS adopta bases existentes).
+CREATE TABLE IF NOT EXISTS tweet_models (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT,
+    text       VARCHAR(280),
+    created_at BIGINT
+);
+CREATE INDEX IF NOT EXISTS idx_tweet_models_user_id ON tweet_models (user_id);
+CREATE INDEX IF NOT EXISTS idx_tweet_models_created_at ON tweet_models (created_at);
+CREATE INDEX IF NOT EXISTS idx_tweets_user_created ON tweet_models (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS follow_models (
+    id          TEXT PRIMARY KEY,
+    follower_id TEXT,
+    followee_id TEXT,
+    created_at  BIGINT
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_follow_pair ON follow_models (follower_id, followee_id);
+CREATE INDEX IF NOT EXISTS idx_follow_models_created_at ON follow_models (created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tweets_user_created_id;
+CREATE INDEX IF NOT EXISTS idx_tweets_user_created ON tweet_models (user_id, created_at DESC);
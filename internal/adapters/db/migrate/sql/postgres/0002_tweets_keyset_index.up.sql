@@ -0,0 +1,3 @@
+-- Keyset (created_at, id) para cursores del timeline.
+DROP INDEX IF EXISTS idx_tweets_user_created;
+CREATE INDEX IF NOT EXISTS idx_tweets_user_created_id ON tweet_models (user_id, created_at DESC, id DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_home_tweet;
+ALTER TABLE tweet_models DROP COLUMN deleted_at;
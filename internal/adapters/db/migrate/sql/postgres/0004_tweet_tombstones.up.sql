@@ -0,0 +1,3 @@
+-- Soft delete: la fila queda como tombstone (deleted_at + texto vacío).
+ALTER TABLE tweet_models ADD COLUMN deleted_at BIGINT;
+CREATE INDEX idx_home_tweet ON home_timeline_entries (tweet_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tweets_conversation_path;
+ALTER TABLE tweet_models DROP COLUMN thread_path;
+ALTER TABLE tweet_models DROP COLUMN depth;
+ALTER TABLE tweet_models DROP COLUMN conversation_id;
+ALTER TABLE tweet_models DROP COLUMN in_reply_to_id;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tweets_user_retweet;
+ALTER TABLE tweet_models DROP COLUMN quoted_tweet_id;
+ALTER TABLE tweet_models DROP COLUMN retweet_of_id;
@@ -0,0 +1,6 @@
+-- Retweets y citas. Un retweet vivo por (usuario, original); al deshacerlo
+-- queda como tombstone y libera el índice.
+ALTER TABLE tweet_models ADD COLUMN retweet_of_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE tweet_models ADD COLUMN quoted_tweet_id TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX idx_tweets_user_retweet ON tweet_models (user_id, retweet_of_id)
+    WHERE retweet_of_id <> '' AND deleted_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE tweet_models DROP COLUMN like_count;
+DROP TABLE IF EXISTS likes;
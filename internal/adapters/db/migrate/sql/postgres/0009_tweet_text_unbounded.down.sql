@@ -0,0 +1,2 @@
+-- Falla si quedaron textos de más de 280 code points.
+ALTER TABLE tweet_models ALTER COLUMN text TYPE VARCHAR(280);
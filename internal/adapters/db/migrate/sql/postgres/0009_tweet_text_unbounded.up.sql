@@ -0,0 +1,3 @@
+-- El largo se valida en grafemas (domain.TweetLength); VARCHAR(280) cuenta
+-- code points y rechazaría tweets válidos con emoji compuestos o URLs largas.
+ALTER TABLE tweet_models ALTER COLUMN text TYPE TEXT;
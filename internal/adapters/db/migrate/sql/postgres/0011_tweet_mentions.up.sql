@@ -0,0 +1,9 @@
+-- Menciones: offsets en tweet_models.mentions (JSON) e índice por usuario mencionado.
+ALTER TABLE tweet_models ADD COLUMN mentions TEXT; -- NULL = sin menciones
+CREATE TABLE tweet_mentions (
+    tweet_id   TEXT NOT NULL,
+    user_id    TEXT NOT NULL,
+    created_at BIGINT NOT NULL,
+    PRIMARY KEY (tweet_id, user_id)
+);
+CREATE INDEX idx_tweet_mentions_user_created ON tweet_mentions (user_id, created_at DESC, tweet_id DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_follows_follower_created;
+DROP INDEX IF EXISTS idx_follows_followee_created;
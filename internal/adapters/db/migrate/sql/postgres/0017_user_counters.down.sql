@@ -0,0 +1,3 @@
+ALTER TABLE users DROP COLUMN tweet_count;
+ALTER TABLE users DROP COLUMN following_count;
+ALTER TABLE users DROP COLUMN follower_count;
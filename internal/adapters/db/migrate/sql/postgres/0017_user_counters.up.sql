@@ -0,0 +1,10 @@
+-- Contadores desnormalizados del perfil; los mantienen FollowRepo y TweetRepo
+-- en la misma transacción (y `server users reconcile-counters` los recalcula).
+ALTER TABLE users ADD COLUMN follower_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN following_count BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN tweet_count BIGINT NOT NULL DEFAULT 0;
+UPDATE users SET
+    follower_count  = (SELECT COUNT(*) FROM follow_models f WHERE f.followee_id = users.id),
+    following_count = (SELECT COUNT(*) FROM follow_models f WHERE f.follower_id = users.id),
+    tweet_count     = (SELECT COUNT(*) FROM tweet_models t
+                       WHERE t.user_id = users.id AND t.deleted_at IS NULL AND t.retweet_of_id = '');
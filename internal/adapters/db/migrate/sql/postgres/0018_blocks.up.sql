@@ -0,0 +1,11 @@
+-- Bloqueos: un bloqueo por (bloqueador, bloqueado). El listado pagina por
+-- (created_at, blocked_id) DESC; idx_blocks_blocked resuelve quién bloqueó a un usuario.
+CREATE TABLE blocks (
+    id         TEXT PRIMARY KEY,
+    blocker_id TEXT NOT NULL,
+    blocked_id TEXT NOT NULL,
+    created_at BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX idx_blocks_pair ON blocks (blocker_id, blocked_id);
+CREATE INDEX idx_blocks_blocker_created ON blocks (blocker_id, created_at DESC, blocked_id DESC);
+CREATE INDEX idx_blocks_blocked ON blocks (blocked_id);
@@ -0,0 +1,11 @@
+-- Cuentas protegidas: los follows hacia ellas quedan como solicitudes
+-- pendientes hasta que la cuenta las aprueba.
+ALTER TABLE users ADD COLUMN protected BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE TABLE follow_requests (
+    id           TEXT PRIMARY KEY,
+    requester_id TEXT NOT NULL,
+    target_id    TEXT NOT NULL,
+    created_at   BIGINT NOT NULL
+);
+CREATE UNIQUE INDEX idx_follow_requests_pair ON follow_requests (requester_id, target_id);
+CREATE INDEX idx_follow_requests_target_created ON follow_requests (target_id, created_at DESC, requester_id DESC);
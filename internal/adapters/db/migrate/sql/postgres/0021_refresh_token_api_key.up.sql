@@ -0,0 +1,3 @@
+-- Refresh tokens atados a la API key que inició la cadena. Los existentes
+-- quedan con '' y ya no se pueden refrescar.
+ALTER TABLE refresh_tokens ADD COLUMN api_key_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fanout_heavy_authors;
+DROP TABLE IF EXISTS home_timeline_entries;
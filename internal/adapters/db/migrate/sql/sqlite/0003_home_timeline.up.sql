@@ -0,0 +1,22 @@
+-- Timeline materializado (fan-out on write).
+CREATE TABLE home_timeline_entries (
+    owner_id   TEXT NOT NULL,
+    tweet_id   TEXT NOT NULL,
+    author_id  TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (owner_id, tweet_id)
+);
+CREATE INDEX idx_home_owner_created ON home_timeline_entries (owner_id, created_at DESC, tweet_id DESC);
+CREATE INDEX idx_home_owner_author ON home_timeline_entries (owner_id, author_id);
+
+-- Autores con demasiados seguidores: sus tweets se leen con fan-out on read.
+CREATE TABLE fanout_heavy_authors (
+    author_id TEXT PRIMARY KEY,
+    marked_at INTEGER NOT NULL
+);
+
+-- Backfill de los follows existentes.
+INSERT INTO home_timeline_entries (owner_id, tweet_id, author_id, created_at)
+SELECT f.follower_id, t.id, t.user_id, t.created_at
+FROM follow_models f
+JOIN tweet_models t ON t.user_id = f.followee_id;
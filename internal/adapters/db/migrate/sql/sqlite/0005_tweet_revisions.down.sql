@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tweet_revisions;
+ALTER TABLE tweet_models DROP COLUMN edited_at;
@@ -0,0 +1,11 @@
+-- Edición de tweets: textos anteriores en tweet_revisions.
+ALTER TABLE tweet_models ADD COLUMN edited_at INTEGER NOT NULL DEFAULT 0;
+CREATE TABLE tweet_revisions (
+    id          TEXT PRIMARY KEY,
+    tweet_id    TEXT NOT NULL,
+    revision    INTEGER NOT NULL,
+    text        TEXT NOT NULL,
+    created_at  INTEGER NOT NULL,
+    replaced_at INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX idx_tweet_revisions_tweet ON tweet_revisions (tweet_id, revision);
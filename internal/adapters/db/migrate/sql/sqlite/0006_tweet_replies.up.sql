@@ -0,0 +1,7 @@
+-- Respuestas y conversaciones. thread_path = raíz/.../id ordena el árbol en DFS.
+ALTER TABLE tweet_models ADD COLUMN in_reply_to_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE tweet_models ADD COLUMN conversation_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE tweet_models ADD COLUMN depth INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE tweet_models ADD COLUMN thread_path TEXT NOT NULL DEFAULT '';
+UPDATE tweet_models SET conversation_id = id, thread_path = id WHERE conversation_id = '';
+CREATE INDEX idx_tweets_conversation_path ON tweet_models (conversation_id, thread_path);
@@ -0,0 +1,11 @@
+-- Likes: un like por (usuario, tweet) y contador desnormalizado en tweet_models.
+CREATE TABLE likes (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    tweet_id   TEXT NOT NULL,
+    created_at INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX idx_likes_pair ON likes (user_id, tweet_id);
+CREATE INDEX idx_likes_user_created ON likes (user_id, created_at DESC, tweet_id DESC);
+CREATE INDEX idx_likes_tweet_created ON likes (tweet_id, created_at DESC, user_id DESC);
+ALTER TABLE tweet_models ADD COLUMN like_count INTEGER NOT NULL DEFAULT 0;
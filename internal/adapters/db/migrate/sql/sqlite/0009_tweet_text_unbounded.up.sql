@@ -0,0 +1,3 @@
+-- SQLite no aplica largos en TEXT: nada que cambiar. Se mantiene la versión
+-- alineada con postgres/0009.
+SELECT 1;
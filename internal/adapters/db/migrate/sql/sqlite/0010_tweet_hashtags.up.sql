@@ -0,0 +1,9 @@
+-- Índice de hashtags normalizados. Los tweets previos se indexan con
+-- `server hashtags reindex` (la extracción es Unicode y vive en Go).
+CREATE TABLE tweet_hashtags (
+    tweet_id   TEXT NOT NULL,
+    tag        TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    PRIMARY KEY (tweet_id, tag)
+);
+CREATE INDEX idx_tweet_hashtags_tag_created ON tweet_hashtags (tag, created_at DESC, tweet_id DESC);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tweet_mentions;
+ALTER TABLE tweet_models DROP COLUMN mentions;
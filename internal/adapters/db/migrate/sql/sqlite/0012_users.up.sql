@@ -0,0 +1,25 @@
+-- Usuarios: handle único sin distinguir mayúsculas.
+CREATE TABLE users (
+    id           TEXT PRIMARY KEY,
+    handle       TEXT NOT NULL,
+    display_name TEXT NOT NULL,
+    bio          TEXT NOT NULL DEFAULT '',
+    created_at   INTEGER NOT NULL,
+    updated_at   INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX idx_users_handle_lower ON users (LOWER(handle));
+
+-- Hasta acá los user_id eran strings libres y las menciones usaban el id
+-- como handle: se da de alta cada id ya usado con handle = id. Si dos ids
+-- sólo difieren en mayúsculas queda el primero.
+INSERT INTO users (id, handle, display_name, bio, created_at, updated_at)
+SELECT user_id, user_id, user_id, '', MIN(created_at), MIN(created_at)
+FROM (
+    SELECT user_id, created_at FROM tweet_models
+    UNION ALL SELECT follower_id, created_at FROM follow_models
+    UNION ALL SELECT followee_id, created_at FROM follow_models
+) AS seen
+WHERE user_id <> ''
+GROUP BY user_id
+ORDER BY MIN(created_at), user_id
+ON CONFLICT DO NOTHING;
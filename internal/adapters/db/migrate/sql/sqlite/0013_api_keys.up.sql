@@ -0,0 +1,12 @@
+-- API keys: sólo se guarda el SHA-256 del token.
+CREATE TABLE api_keys (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    name       TEXT NOT NULL DEFAULT '',
+    hint       TEXT NOT NULL,
+    hash       TEXT NOT NULL,
+    created_at INTEGER NOT NULL,
+    revoked_at INTEGER
+);
+CREATE UNIQUE INDEX idx_api_keys_hash ON api_keys (hash);
+CREATE INDEX idx_api_keys_user_created ON api_keys (user_id, created_at DESC);
@@ -0,0 +1,12 @@
+-- Refresh tokens de un solo uso; como las API keys, sólo se guarda el SHA-256.
+CREATE TABLE refresh_tokens (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    hash       TEXT NOT NULL,
+    scopes     TEXT NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    revoked_at INTEGER
+);
+CREATE UNIQUE INDEX idx_refresh_tokens_hash ON refresh_tokens (hash);
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens (user_id);
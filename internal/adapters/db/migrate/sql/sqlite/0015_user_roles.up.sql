@@ -0,0 +1,3 @@
+-- Rol (user|admin) y suspensión; suspended_at NULL = cuenta activa.
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN suspended_at INTEGER;
@@ -0,0 +1,3 @@
+-- Listados paginados de seguidores/seguidos por (created_at, otro extremo) DESC.
+CREATE INDEX idx_follows_followee_created ON follow_models (followee_id, created_at DESC, follower_id DESC);
+CREATE INDEX idx_follows_follower_created ON follow_models (follower_id, created_at DESC, followee_id DESC);
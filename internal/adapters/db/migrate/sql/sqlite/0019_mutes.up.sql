@@ -0,0 +1,11 @@
+-- Mutes por usuario: cuentas, palabras y regex, con expiración opcional
+-- (expires_at = 0 no vence). Un mute por (usuario, tipo, valor).
+CREATE TABLE mutes (
+    id         TEXT PRIMARY KEY,
+    user_id    TEXT NOT NULL,
+    kind       TEXT NOT NULL,
+    value      TEXT NOT NULL,
+    expires_at INTEGER NOT NULL DEFAULT 0,
+    created_at INTEGER NOT NULL
+);
+CREATE UNIQUE INDEX idx_mutes_user_value ON mutes (user_id, kind, value);
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"gorm.io/gorm"
+
+	"tweetschallenge/internal/ports"
+)
+
+// keyset aplica paginación por (createdCol, idCol) y pide un item extra para
+// saber si hay más. Cursores "newer" se recorren en ASC y finishPage los invierte.
+func keyset(tx *gorm.DB, q ports.PageQuery, createdCol, idCol string) *gorm.DB {
+	tx = tx.Limit(ports.PageLimit(q.Limit) + 1)
+	c := q.Cursor
+	switch {
+	case c == nil:
+		return tx.Order(createdCol + " DESC").Order(idCol + " DESC")
+	case c.Newer:
+		return tx.Where("("+createdCol+" > ? OR ("+createdCol+" = ? AND "+idCol+" > ?))", c.CreatedAt, c.CreatedAt, c.ID).
+			Order(createdCol + " ASC").Order(idCol + " ASC")
+	default:
+		return tx.Where("("+createdCol+" < ? OR ("+createdCol+" = ? AND "+idCol+" < ?))", c.CreatedAt, c.CreatedAt, c.ID).
+			Order(createdCol + " DESC").Order(idCol + " DESC")
+	}
+}
+
+func finishPage[T any](items []T, q ports.PageQuery) ports.Page[T] {
+	limit := ports.PageLimit(q.Limit)
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if q.Cursor != nil && q.Cursor.Newer {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	return ports.Page[T]{Items: items, HasMore: hasMore}
+}
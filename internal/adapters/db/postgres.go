@@ -0,0 +1,54 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type PostgresConfig struct {
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+func PostgresConfigFromEnv() PostgresConfig {
+	dsn := os.Getenv("POSTGRES_DSN")
+	if dsn == "" {
+		dsn = os.Getenv("DATABASE_URL")
+	}
+	return PostgresConfig{
+		DSN:             dsn,
+		MaxOpenConns:    envInt("POSTGRES_MAX_OPEN_CONNS", 10),
+		MaxIdleConns:    envInt("POSTGRES_MAX_IDLE_CONNS", 5),
+		ConnMaxLifetime: time.Duration(envInt("POSTGRES_CONN_MAX_LIFETIME_SEC", 300)) * time.Second,
+	}
+}
+
+func OpenPostgres(cfg PostgresConfig) (*gorm.DB, error) {
+	if cfg.DSN == "" {
+		return nil, fmt.Errorf("postgres: dsn required (POSTGRES_DSN)")
+	}
+	db, err := gorm.Open(postgres.Open(cfg.DSN), &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	return db, nil
+}
@@ -0,0 +1,1161 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"tweetschallenge/internal/adapters/db/migrate"
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// forEachDB corre el mismo contrato contra SQLite en memoria y, si
+// POSTGRES_TEST_DSN está definido, contra un Postgres local en un schema
+// descartable.
+func forEachDB(t *testing.T, fn func(t *testing.T, db *gorm.DB)) {
+	t.Helper()
+	t.Run("sqlite", func(t *testing.T) {
+		db, err := OpenSQLite(SQLiteConfig{Mode: SQLiteModeMemory})
+		if err != nil {
+			t.Fatalf("open sqlite: %v", err)
+		}
+		defer Close(db)
+		migrateForTest(t, db)
+		fn(t, db)
+	})
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv("POSTGRES_TEST_DSN")
+		if dsn == "" {
+			t.Skip("POSTGRES_TEST_DSN not set")
+		}
+		db := openPostgresSchema(t, dsn)
+		migrateForTest(t, db)
+		fn(t, db)
+	})
+}
+
+func openPostgresSchema(t *testing.T, dsn string) *gorm.DB {
+	t.Helper()
+	admin, err := OpenPostgres(PostgresConfig{DSN: dsn})
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	schema := fmt.Sprintf("t_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		Close(admin)
+	})
+
+	scoped := dsn + " search_path=" + schema
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		scoped = dsn + sep + "search_path=" + schema
+	}
+	db, err := OpenPostgres(PostgresConfig{DSN: scoped})
+	if err != nil {
+		t.Fatalf("open scoped postgres: %v", err)
+	}
+	t.Cleanup(func() { Close(db) })
+	return db
+}
+
+func migrateForTest(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	m, err := migrate.New(db)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	if _, err := m.Up(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+}
+
+func TestTweetRepo_TimelineForUsers(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewTweetRepoGorm(db)
+		for i, u := range []string{"u1", "u2", "u3", "u2"} {
+			tw := domain.Tweet{ID: fmt.Sprintf("T%d", i), UserID: u, Text: "x", CreatedAt: int64(i + 1)}
+			if err := repo.Create(ctx, &tw); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+
+		got, err := repo.TimelineForUsers(ctx, []string{"u2", "u3"}, 2, 0)
+		if err != nil {
+			t.Fatalf("timeline: %v", err)
+		}
+		if len(got) != 2 || got[0].ID != "T3" || got[1].ID != "T2" {
+			t.Fatalf("want newest first [T3 T2], got %#v", got)
+		}
+		got, err = repo.TimelineForUsers(ctx, []string{"u2", "u3"}, 2, 2)
+		if err != nil {
+			t.Fatalf("timeline offset: %v", err)
+		}
+		if len(got) != 1 || got[0].ID != "T1" {
+			t.Fatalf("want [T1] on second page, got %#v", got)
+		}
+	})
+}
+
+func TestFollowRepo_IdempotentAndListing(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewFollowRepoGorm(db)
+		for _, f := range []domain.Follow{
+			{ID: "F1", FollowerID: "u1", FolloweeID: "u2", CreatedAt: 1},
+			{ID: "F2", FollowerID: "u1", FolloweeID: "u2", CreatedAt: 2},
+			{ID: "F3", FollowerID: "u3", FolloweeID: "u2", CreatedAt: 3},
+		} {
+			if err := repo.Create(ctx, &f); err != nil {
+				t.Fatalf("create %s: %v", f.ID, err)
+			}
+		}
+
+		followers, err := repo.FollowersIDs(ctx, "u2")
+		if err != nil {
+			t.Fatalf("followers: %v", err)
+		}
+		sort.Strings(followers)
+		if len(followers) != 2 || followers[0] != "u1" || followers[1] != "u3" {
+			t.Fatalf("unexpected followers: %v", followers)
+		}
+		if n, err := repo.CountFollowers(ctx, "u2"); err != nil || n != 2 {
+			t.Fatalf("count followers = %d err=%v", n, err)
+		}
+		if ok, err := repo.Exists(ctx, "u1", "u2"); err != nil || !ok {
+			t.Fatalf("exists = %v err=%v", ok, err)
+		}
+		if ok, _ := repo.Exists(ctx, "u2", "u1"); ok {
+			t.Fatal("u2 does not follow u1")
+		}
+
+		if err := repo.Unfollow(ctx, "u1", "u2"); err != nil {
+			t.Fatalf("unfollow: %v", err)
+		}
+		if err := repo.Unfollow(ctx, "u1", "u2"); err != nil {
+			t.Fatalf("unfollow twice: %v", err)
+		}
+		following, err := repo.FollowingIDs(ctx, "u1")
+		if err != nil {
+			t.Fatalf("following: %v", err)
+		}
+		if len(following) != 0 {
+			t.Fatalf("expected no following after unfollow, got %v", following)
+		}
+	})
+}
+
+func TestFollowRepo_Pages(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewFollowRepoGorm(db)
+		// a, b, c siguen a "star" (b y c en el mismo segundo); star sigue a a y b.
+		for _, f := range []domain.Follow{
+			{ID: "F1", FollowerID: "a", FolloweeID: "star", CreatedAt: 1},
+			{ID: "F2", FollowerID: "b", FolloweeID: "star", CreatedAt: 2},
+			{ID: "F3", FollowerID: "c", FolloweeID: "star", CreatedAt: 2},
+			{ID: "F4", FollowerID: "star", FolloweeID: "a", CreatedAt: 3},
+			{ID: "F5", FollowerID: "star", FolloweeID: "b", CreatedAt: 4},
+		} {
+			if err := repo.Create(ctx, &f); err != nil {
+				t.Fatalf("create %s: %v", f.ID, err)
+			}
+		}
+		ids := func(p ports.Page[domain.Follow], follower bool) []string {
+			var out []string
+			for _, f := range p.Items {
+				if follower {
+					out = append(out, f.FollowerID)
+				} else {
+					out = append(out, f.FolloweeID)
+				}
+			}
+			return out
+		}
+
+		p, err := repo.FollowersPage(ctx, "star", ports.PageQuery{Limit: 2})
+		if err != nil || !reflect.DeepEqual(ids(p, true), []string{"c", "b"}) || !p.HasMore {
+			t.Fatalf("followers page 1 = %v more=%v err=%v", ids(p, true), p.HasMore, err)
+		}
+		p, _ = repo.FollowersPage(ctx, "star", ports.PageQuery{Limit: 2, Cursor: &domain.Cursor{CreatedAt: 2, ID: "b"}})
+		if !reflect.DeepEqual(ids(p, true), []string{"a"}) || p.HasMore {
+			t.Fatalf("followers page 2 = %v more=%v", ids(p, true), p.HasMore)
+		}
+		p, _ = repo.FollowersPage(ctx, "star", ports.PageQuery{Limit: 2, Cursor: &domain.Cursor{CreatedAt: 1, ID: "a", Newer: true}})
+		if !reflect.DeepEqual(ids(p, true), []string{"c", "b"}) {
+			t.Fatalf("followers newer = %v", ids(p, true))
+		}
+
+		p, err = repo.FollowingPage(ctx, "star", ports.PageQuery{})
+		if err != nil || !reflect.DeepEqual(ids(p, false), []string{"b", "a"}) || p.HasMore {
+			t.Fatalf("following = %v err=%v", ids(p, false), err)
+		}
+		if p, _ := repo.FollowingPage(ctx, "nadie", ports.PageQuery{}); len(p.Items) != 0 {
+			t.Fatalf("expected empty page, got %v", p.Items)
+		}
+	})
+}
+
+func TestBlockRepo(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewBlockRepoGorm(db)
+		for _, b := range []domain.Block{
+			{ID: "B1", BlockerID: "a", BlockedID: "troll", CreatedAt: 1},
+			{ID: "B2", BlockerID: "a", BlockedID: "spam", CreatedAt: 2},
+			{ID: "B3", BlockerID: "c", BlockedID: "troll", CreatedAt: 2},
+			{ID: "B4", BlockerID: "a", BlockedID: "troll", CreatedAt: 9}, // repetido: no-op
+		} {
+			if err := repo.Create(ctx, &b); err != nil {
+				t.Fatalf("create %s: %v", b.ID, err)
+			}
+		}
+
+		for _, pair := range [][2]string{{"a", "troll"}, {"troll", "a"}} {
+			if ok, err := repo.Between(ctx, pair[0], pair[1]); err != nil || !ok {
+				t.Fatalf("between %v = %v err=%v", pair, ok, err)
+			}
+		}
+		if ok, _ := repo.Between(ctx, "a", "c"); ok {
+			t.Fatal("a and c are not blocked")
+		}
+		blockers, err := repo.BlockerIDs(ctx, "troll")
+		sort.Strings(blockers)
+		if err != nil || !reflect.DeepEqual(blockers, []string{"a", "c"}) {
+			t.Fatalf("blockers = %v err=%v", blockers, err)
+		}
+
+		p, err := repo.Page(ctx, "a", ports.PageQuery{Limit: 1})
+		if err != nil || len(p.Items) != 1 || p.Items[0].BlockedID != "spam" || !p.HasMore {
+			t.Fatalf("page 1 = %+v err=%v", p, err)
+		}
+		p, _ = repo.Page(ctx, "a", ports.PageQuery{Limit: 1, Cursor: &domain.Cursor{CreatedAt: 2, ID: "spam"}})
+		if len(p.Items) != 1 || p.Items[0].ID != "B1" || p.Items[0].CreatedAt != 1 || p.HasMore {
+			t.Fatalf("page 2 = %+v", p)
+		}
+
+		if err := repo.Delete(ctx, "a", "troll"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if err := repo.Delete(ctx, "a", "troll"); err != nil {
+			t.Fatalf("delete is not idempotent: %v", err)
+		}
+		if ok, _ := repo.Between(ctx, "troll", "a"); ok {
+			t.Fatal("expected block removed")
+		}
+	})
+}
+
+func TestMuteRepo(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewMuteRepoGorm(db)
+		for _, m := range []domain.Mute{
+			{ID: "M1", UserID: "ana", Kind: domain.MuteKeyword, Value: "spoiler", ExpiresAt: 100, CreatedAt: 1},
+			{ID: "M2", UserID: "ana", Kind: domain.MuteAccount, Value: "bob", CreatedAt: 2},
+			{ID: "M3", UserID: "ana", Kind: domain.MuteRegex, Value: "^rt", ExpiresAt: 50, CreatedAt: 3},
+			{ID: "M4", UserID: "caro", Kind: domain.MuteKeyword, Value: "spoiler", CreatedAt: 4},
+		} {
+			if err := repo.Create(ctx, &m); err != nil {
+				t.Fatalf("create %s: %v", m.ID, err)
+			}
+		}
+		ids := func(ms []domain.Mute) []string {
+			var out []string
+			for _, m := range ms {
+				out = append(out, m.ID)
+			}
+			return out
+		}
+
+		active, err := repo.Active(ctx, "ana", 50)
+		if err != nil || !reflect.DeepEqual(ids(active), []string{"M2", "M1"}) {
+			t.Fatalf("active = %v err=%v", ids(active), err)
+		}
+
+		// Repetir el mute conserva id y fecha y pisa la expiración.
+		again := domain.Mute{ID: "M5", UserID: "ana", Kind: domain.MuteKeyword, Value: "spoiler", CreatedAt: 9}
+		if err := repo.Create(ctx, &again); err != nil {
+			t.Fatalf("re-create: %v", err)
+		}
+		if again.ID != "M1" || again.CreatedAt != 1 || again.ExpiresAt != 0 {
+			t.Fatalf("re-create = %+v", again)
+		}
+		if active, _ := repo.Active(ctx, "ana", 1000); !reflect.DeepEqual(ids(active), []string{"M2", "M1"}) {
+			t.Fatalf("active after re-create = %v", ids(active))
+		}
+
+		if err := repo.Delete(ctx, "caro", "M1"); err != nil {
+			t.Fatalf("delete other's mute: %v", err)
+		}
+		if err := repo.Delete(ctx, "ana", "M2"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if active, _ := repo.Active(ctx, "ana", 0); !reflect.DeepEqual(ids(active), []string{"M3", "M1"}) {
+			t.Fatalf("active after delete = %v", ids(active))
+		}
+	})
+}
+
+func TestTweetRepo_TimelineForUsersPage_Keyset(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewTweetRepoGorm(db)
+		// created_at repetidos para ejercitar el desempate por id
+		// (no usar 0: GORM lo completa con autoCreateTime)
+		for i, id := range []string{"A", "B", "C", "D", "E"} {
+			tw := domain.Tweet{ID: id, UserID: "u2", Text: id, CreatedAt: int64(i/2 + 1)}
+			if err := repo.Create(ctx, &tw); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+		ids := func(p ports.Page[domain.Tweet]) (out []string) {
+			for _, tw := range p.Items {
+				out = append(out, tw.ID)
+			}
+			return out
+		}
+
+		p, err := repo.TimelineForUsersPage(ctx, []string{"u2"}, ports.PageQuery{Limit: 2})
+		if err != nil {
+			t.Fatalf("page: %v", err)
+		}
+		if got := ids(p); strings.Join(got, "") != "ED" || !p.HasMore {
+			t.Fatalf("first page = %v hasMore=%v", got, p.HasMore)
+		}
+		p, _ = repo.TimelineForUsersPage(ctx, []string{"u2"}, ports.PageQuery{Limit: 2, Cursor: &domain.Cursor{CreatedAt: 2, ID: "D"}})
+		if got := ids(p); strings.Join(got, "") != "CB" || !p.HasMore {
+			t.Fatalf("older page = %v hasMore=%v", got, p.HasMore)
+		}
+		p, _ = repo.TimelineForUsersPage(ctx, []string{"u2"}, ports.PageQuery{Limit: 2, Cursor: &domain.Cursor{CreatedAt: 1, ID: "A", Newer: true}})
+		if got := ids(p); strings.Join(got, "") != "CB" || !p.HasMore {
+			t.Fatalf("newer page = %v hasMore=%v", got, p.HasMore)
+		}
+		p, _ = repo.TimelineForUsersPage(ctx, []string{"u2"}, ports.PageQuery{Limit: 2, Cursor: &domain.Cursor{CreatedAt: 2, ID: "C", Newer: true}})
+		if got := ids(p); strings.Join(got, "") != "ED" || p.HasMore {
+			t.Fatalf("newest page = %v hasMore=%v", got, p.HasMore)
+		}
+	})
+}
+
+func TestHomeTimelineRepo(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		follows := NewFollowRepoGorm(db)
+		home := NewHomeTimelineRepoGorm(db)
+
+		a := domain.Tweet{ID: "A", UserID: "u2", Text: "a", CreatedAt: 1}
+		b := domain.Tweet{ID: "B", UserID: "u3", Text: "b", CreatedAt: 2}
+		for _, tw := range []*domain.Tweet{&a, &b} {
+			if err := tweets.Create(ctx, tw); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+		if err := home.Push(ctx, []string{"u1"}, a); err != nil {
+			t.Fatalf("push: %v", err)
+		}
+		if err := home.Push(ctx, []string{"u1"}, a); err != nil {
+			t.Fatalf("push twice should be idempotent: %v", err)
+		}
+		if err := home.Backfill(ctx, "u1", []domain.Tweet{b}); err != nil {
+			t.Fatalf("backfill: %v", err)
+		}
+		p, err := home.Page(ctx, "u1", ports.PageQuery{Limit: 10})
+		if err != nil {
+			t.Fatalf("page: %v", err)
+		}
+		if len(p.Items) != 2 || p.Items[0].ID != "B" || p.Items[0].Text != "b" || p.Items[1].ID != "A" {
+			t.Fatalf("unexpected page: %#v", p.Items)
+		}
+
+		if err := home.PurgeAuthor(ctx, "u1", "u3"); err != nil {
+			t.Fatalf("purge: %v", err)
+		}
+		p, _ = home.Page(ctx, "u1", ports.PageQuery{Limit: 10})
+		if len(p.Items) != 1 || p.Items[0].ID != "A" {
+			t.Fatalf("unexpected page after purge: %#v", p.Items)
+		}
+
+		if err := follows.Create(ctx, &domain.Follow{ID: "F1", FollowerID: "u1", FolloweeID: "u3", CreatedAt: 1}); err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+		if err := home.MarkHeavy(ctx, "u3", 1); err != nil {
+			t.Fatalf("mark heavy: %v", err)
+		}
+		if err := home.MarkHeavy(ctx, "u3", 2); err != nil {
+			t.Fatalf("mark heavy twice: %v", err)
+		}
+		heavy, err := home.HeavyFollowees(ctx, "u1")
+		if err != nil || len(heavy) != 1 || heavy[0] != "u3" {
+			t.Fatalf("heavy followees = %v err=%v", heavy, err)
+		}
+		if ok, _ := home.IsHeavy(ctx, "u2"); ok {
+			t.Fatal("u2 should not be heavy")
+		}
+	})
+}
+
+func TestTweetRepo_SoftDelete(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		home := NewHomeTimelineRepoGorm(db)
+		tw := domain.Tweet{ID: "A", UserID: "u2", Text: "a", CreatedAt: 1}
+		if err := tweets.Create(ctx, &tw); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		if err := home.Push(ctx, []string{"u1"}, tw); err != nil {
+			t.Fatalf("push: %v", err)
+		}
+
+		if err := tweets.SoftDelete(ctx, "A", 5); err != nil {
+			t.Fatalf("soft delete: %v", err)
+		}
+		if err := tweets.SoftDelete(ctx, "A", 6); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("second delete: want ErrTweetNotFound, got %v", err)
+		}
+		if _, err := tweets.Get(ctx, "A"); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("get: want ErrTweetNotFound, got %v", err)
+		}
+		p, _ := tweets.TimelineForUsersPage(ctx, []string{"u2"}, ports.PageQuery{})
+		h, _ := home.Page(ctx, "u1", ports.PageQuery{})
+		if len(p.Items) != 0 || len(h.Items) != 0 {
+			t.Fatalf("tombstone leaked: tweets=%v home=%v", p.Items, h.Items)
+		}
+
+		var m TweetModel
+		if err := db.First(&m, "id = ?", "A").Error; err != nil {
+			t.Fatalf("tombstone row: %v", err)
+		}
+		if m.DeletedAt == nil || *m.DeletedAt != 5 || m.Text != "" {
+			t.Fatalf("unexpected tombstone: %+v", m)
+		}
+	})
+}
+
+func TestTweetRepo_EditKeepsRevisions(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		tw := domain.Tweet{ID: "A", UserID: "u1", Text: "v1", CreatedAt: 1}
+		if err := tweets.Create(ctx, &tw); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		for i, text := range []string{"v2", "v3"} {
+			updated, rev, err := tw.Edit(fmt.Sprintf("R%d", i), text, int64(10+i), 0)
+			if err != nil {
+				t.Fatalf("edit: %v", err)
+			}
+			if err := tweets.Edit(ctx, &updated, &rev); err != nil {
+				t.Fatalf("repo edit: %v", err)
+			}
+			if rev.Revision != i+1 {
+				t.Fatalf("revision = %d, want %d", rev.Revision, i+1)
+			}
+			tw = updated
+		}
+
+		got, err := tweets.Get(ctx, "A")
+		if err != nil || got.Text != "v3" || got.EditedAt != 11 {
+			t.Fatalf("get = %#v err=%v", got, err)
+		}
+		revs, err := tweets.Revisions(ctx, "A")
+		if err != nil {
+			t.Fatalf("revisions: %v", err)
+		}
+		if len(revs) != 2 || revs[0].Text != "v1" || revs[1].Text != "v2" || revs[1].CreatedAt != 10 {
+			t.Fatalf("unexpected revisions: %#v", revs)
+		}
+
+		missing := domain.Tweet{ID: "nope", Text: "x"}
+		if err := tweets.Edit(ctx, &missing, &domain.TweetRevision{ID: "R9", TweetID: "nope"}); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("want ErrTweetNotFound, got %v", err)
+		}
+	})
+}
+
+func TestTweetRepo_ConversationTree(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		root := domain.Tweet{ID: "A", UserID: "u1", Text: "root", CreatedAt: 1, ConversationID: "A"}
+		if err := tweets.Create(ctx, &root); err != nil {
+			t.Fatalf("create root: %v", err)
+		}
+		// B y D responden a A, C responde a B: orden de árbol A, B, C, D.
+		for i, r := range [][2]string{{"B", "A"}, {"C", "B"}, {"D", "A"}} {
+			tw := domain.Tweet{ID: r[0], UserID: "u2", Text: "re", CreatedAt: int64(2 + i), InReplyToID: r[1], ConversationID: "A"}
+			if err := tweets.Create(ctx, &tw); err != nil {
+				t.Fatalf("create %s: %v", r[0], err)
+			}
+		}
+		orphan := domain.Tweet{ID: "X", UserID: "u2", Text: "re", CreatedAt: 9, InReplyToID: "nope", ConversationID: "nope"}
+		if err := tweets.Create(ctx, &orphan); !errors.Is(err, domain.ErrParentNotFound) {
+			t.Fatalf("want ErrParentNotFound, got %v", err)
+		}
+		if err := tweets.SoftDelete(ctx, "B", 10); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+
+		first, err := tweets.Conversation(ctx, "A", ports.PageQuery{Limit: 2})
+		if err != nil {
+			t.Fatalf("conversation: %v", err)
+		}
+		if !first.HasMore || len(first.Items) != 2 || first.Items[0].ID != "A" || first.Items[1].ID != "B" {
+			t.Fatalf("unexpected first page: %+v", first)
+		}
+		if first.Items[1].Depth != 1 || first.Items[1].DeletedAt == 0 {
+			t.Fatalf("expected B as tombstone at depth 1: %+v", first.Items[1])
+		}
+		next := &domain.Cursor{ID: first.Items[1].Path}
+		second, err := tweets.Conversation(ctx, "A", ports.PageQuery{Limit: 2, Cursor: next})
+		if err != nil {
+			t.Fatalf("conversation: %v", err)
+		}
+		if second.HasMore || len(second.Items) != 2 || second.Items[0].ID != "C" || second.Items[0].Depth != 2 || second.Items[1].ID != "D" {
+			t.Fatalf("unexpected second page: %+v", second)
+		}
+		back := &domain.Cursor{ID: second.Items[0].Path, Newer: true}
+		prev, err := tweets.Conversation(ctx, "A", ports.PageQuery{Limit: 1, Cursor: back})
+		if err != nil || len(prev.Items) != 1 || prev.Items[0].ID != "B" {
+			t.Fatalf("unexpected prev page: %+v err=%v", prev, err)
+		}
+	})
+}
+
+func TestTweetRepo_RetweetIdempotent(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		orig := domain.Tweet{ID: "A", UserID: "author", Text: "x", CreatedAt: 1, ConversationID: "A"}
+		if err := tweets.Create(ctx, &orig); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		rt := domain.Tweet{ID: "R1", UserID: "u1", CreatedAt: 2, RetweetOfID: "A"}
+		if created, err := tweets.Retweet(ctx, &rt); err != nil || !created {
+			t.Fatalf("retweet: created=%v err=%v", created, err)
+		}
+		dup := domain.Tweet{ID: "R2", UserID: "u1", CreatedAt: 3, RetweetOfID: "A"}
+		if created, err := tweets.Retweet(ctx, &dup); err != nil || created || dup.ID != "R1" {
+			t.Fatalf("duplicate retweet: %#v created=%v err=%v", dup, created, err)
+		}
+
+		// deshacer libera el índice parcial
+		if err := tweets.SoftDelete(ctx, "R1", 4); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if _, err := tweets.FindRetweet(ctx, "u1", "A"); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("want ErrTweetNotFound after undo, got %v", err)
+		}
+		again := domain.Tweet{ID: "R3", UserID: "u1", CreatedAt: 5, RetweetOfID: "A"}
+		if created, err := tweets.Retweet(ctx, &again); err != nil || !created {
+			t.Fatalf("retweet after undo: created=%v err=%v", created, err)
+		}
+
+		got, err := tweets.GetMany(ctx, []string{"A", "R1", "R3", "nope"})
+		if err != nil || len(got) != 2 {
+			t.Fatalf("get many = %#v err=%v", got, err)
+		}
+	})
+}
+
+func TestLikeRepo_CounterAndListings(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		likes := NewLikeRepoGorm(db)
+		for i, id := range []string{"A", "B"} {
+			tw := domain.Tweet{ID: id, UserID: "author", Text: id, CreatedAt: int64(i + 1), ConversationID: id}
+			if err := tweets.Create(ctx, &tw); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+		like := func(id, user, tweet string, at int64) {
+			t.Helper()
+			l := domain.Like{ID: id, UserID: user, TweetID: tweet, CreatedAt: at}
+			if err := likes.Create(ctx, &l); err != nil {
+				t.Fatalf("like: %v", err)
+			}
+		}
+		like("L1", "u1", "A", 10)
+		like("L2", "u1", "A", 11) // duplicado: no suma
+		like("L3", "u2", "A", 12)
+		like("L4", "u1", "B", 13)
+
+		got, _ := tweets.Get(ctx, "A")
+		if got.LikeCount != 2 {
+			t.Fatalf("like_count = %d, want 2", got.LikeCount)
+		}
+		// el contador viaja con los listados de tweets
+		page, _ := tweets.TimelineForUsersPage(ctx, []string{"author"}, ports.PageQuery{})
+		if len(page.Items) != 2 || page.Items[1].LikeCount != 2 || page.Items[0].LikeCount != 1 {
+			t.Fatalf("unexpected counters in timeline: %#v", page.Items)
+		}
+
+		likers, err := likes.LikersPage(ctx, "A", ports.PageQuery{Limit: 1})
+		if err != nil || len(likers.Items) != 1 || likers.Items[0].UserID != "u2" || !likers.HasMore {
+			t.Fatalf("likers = %#v err=%v", likers, err)
+		}
+		liked, err := likes.LikedTweetsPage(ctx, "u1", ports.PageQuery{})
+		if err != nil || len(liked.Items) != 2 || liked.Items[0].ID != "B" || liked.Items[0].LikedAt != 13 {
+			t.Fatalf("liked tweets = %#v err=%v", liked, err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := likes.Unlike(ctx, "u1", "A"); err != nil {
+				t.Fatalf("unlike: %v", err)
+			}
+		}
+		if got, _ := tweets.Get(ctx, "A"); got.LikeCount != 1 {
+			t.Fatalf("like_count after unlike = %d, want 1", got.LikeCount)
+		}
+	})
+}
+
+func TestTweetRepo_HashtagIndex(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		for i, text := range []string{"#Go es lindo", "nada", "más #go y #sql"} {
+			tw := domain.Tweet{ID: fmt.Sprintf("T%d", i), UserID: "u1", Text: text, CreatedAt: int64(i + 1)}
+			if err := tweets.Create(ctx, &tw); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+		ids := func(tag string) []string {
+			t.Helper()
+			p, err := tweets.HashtagTimelinePage(ctx, tag, ports.PageQuery{})
+			if err != nil {
+				t.Fatalf("hashtag page: %v", err)
+			}
+			var out []string
+			for _, tw := range p.Items {
+				out = append(out, tw.ID)
+			}
+			return out
+		}
+		if got := ids("go"); !reflect.DeepEqual(got, []string{"T2", "T0"}) {
+			t.Fatalf("go = %v", got)
+		}
+
+		// editar reindexa
+		tw, _ := tweets.Get(ctx, "T0")
+		updated, rev, _ := tw.Edit("R1", "ahora #sql", 10, 0)
+		if err := tweets.Edit(ctx, &updated, &rev); err != nil {
+			t.Fatalf("edit: %v", err)
+		}
+		if got := ids("go"); !reflect.DeepEqual(got, []string{"T2"}) {
+			t.Fatalf("go after edit = %v", got)
+		}
+		// los borrados no aparecen
+		if err := tweets.SoftDelete(ctx, "T2", 11); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if got := ids("sql"); !reflect.DeepEqual(got, []string{"T0"}) {
+			t.Fatalf("sql after delete = %v", got)
+		}
+
+		// reindex reconstruye el índice desde el texto
+		if err := db.Exec("DELETE FROM tweet_hashtags").Error; err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		n, err := ReindexHashtags(ctx, db)
+		if err != nil || n != 2 {
+			t.Fatalf("reindex n=%d err=%v", n, err)
+		}
+		if got := ids("sql"); !reflect.DeepEqual(got, []string{"T0"}) {
+			t.Fatalf("sql after reindex = %v", got)
+		}
+	})
+}
+
+func TestUserRepo(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		users := NewUserRepoGorm(db)
+		ana, _ := domain.NewUser("U1", "Ana", "", "", 1)
+		if err := users.Create(ctx, &ana); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		dup, _ := domain.NewUser("U2", "ANA", "", "", 2)
+		if err := users.Create(ctx, &dup); !errors.Is(err, domain.ErrHandleTaken) {
+			t.Fatalf("want ErrHandleTaken, got %v", err)
+		}
+		bob, _ := domain.NewUser("U3", "bob", "Bob", "hola", 3)
+		if err := users.Create(ctx, &bob); err != nil {
+			t.Fatalf("create bob: %v", err)
+		}
+
+		if got, err := users.Get(ctx, "U1"); err != nil || got != ana {
+			t.Fatalf("get = %#v err=%v", got, err)
+		}
+		if _, err := users.Get(ctx, "nadie"); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("want ErrUserNotFound, got %v", err)
+		}
+
+		found, err := users.ByHandles(ctx, []string{"ana", "BOB", "carla"})
+		if err != nil || len(found) != 2 {
+			t.Fatalf("by handles = %#v err=%v", found, err)
+		}
+
+		handle := "Bob"
+		updated, _ := ana.Update(domain.UserPatch{Handle: &handle}, 4)
+		if err := users.Update(ctx, &updated); !errors.Is(err, domain.ErrHandleTaken) {
+			t.Fatalf("want ErrHandleTaken on update, got %v", err)
+		}
+		handle = "ana_b"
+		updated, _ = ana.Update(domain.UserPatch{Handle: &handle}, 4)
+		if err := users.Update(ctx, &updated); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		if got, _ := users.Get(ctx, "U1"); got.Handle != "ana_b" || got.UpdatedAt != 4 || got.CreatedAt != 1 {
+			t.Fatalf("after update = %#v", got)
+		}
+		ghost := domain.User{ID: "nadie", Handle: "ghost"}
+		if err := users.Update(ctx, &ghost); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("want ErrUserNotFound on update, got %v", err)
+		}
+
+		// rol y suspensión
+		if err := users.SetRole(ctx, "U3", domain.RoleAdmin); err != nil {
+			t.Fatalf("set role: %v", err)
+		}
+		if err := users.SetSuspended(ctx, "U1", 7); err != nil {
+			t.Fatalf("suspend: %v", err)
+		}
+		if err := users.SetSuspended(ctx, "nadie", 7); !errors.Is(err, domain.ErrUserNotFound) {
+			t.Fatalf("want ErrUserNotFound on suspend, got %v", err)
+		}
+		if got, _ := users.Get(ctx, "U3"); !got.IsAdmin() || got.Suspended() {
+			t.Fatalf("bob = %#v", got)
+		}
+		if got, _ := users.Get(ctx, "U1"); got.SuspendedAt != 7 || got.IsAdmin() {
+			t.Fatalf("ana = %#v", got)
+		}
+		if ids, err := users.SuspendedIDs(ctx, []string{"U1", "U3", "nadie"}); err != nil || !reflect.DeepEqual(ids, []string{"U1"}) {
+			t.Fatalf("suspended ids = %v err=%v", ids, err)
+		}
+		if err := users.SetSuspended(ctx, "U1", 0); err != nil {
+			t.Fatalf("unsuspend: %v", err)
+		}
+		if ids, _ := users.SuspendedIDs(ctx, []string{"U1", "U3"}); len(ids) != 0 {
+			t.Fatalf("suspended ids after unsuspend = %v", ids)
+		}
+
+		// cuenta protegida (viaja con el resto del perfil en Update)
+		protected := true
+		bobP, _ := bob.Update(domain.UserPatch{Protected: &protected}, 8)
+		if err := users.Update(ctx, &bobP); err != nil {
+			t.Fatalf("protect: %v", err)
+		}
+		if got, _ := users.Get(ctx, "U3"); !got.Protected || !got.IsAdmin() {
+			t.Fatalf("protected bob = %#v", got)
+		}
+		if ids, err := users.ProtectedIDs(ctx, []string{"U1", "U3", "nadie"}); err != nil || !reflect.DeepEqual(ids, []string{"U3"}) {
+			t.Fatalf("protected ids = %v err=%v", ids, err)
+		}
+	})
+}
+
+func TestFollowRequestRepo(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewFollowRequestRepoGorm(db)
+		for _, r := range []domain.FollowRequest{
+			{ID: "R1", RequesterID: "a", TargetID: "priv", CreatedAt: 1},
+			{ID: "R2", RequesterID: "b", TargetID: "priv", CreatedAt: 2},
+			{ID: "R3", RequesterID: "a", TargetID: "otra", CreatedAt: 3},
+			{ID: "R4", RequesterID: "a", TargetID: "priv", CreatedAt: 9}, // repetida: no-op
+		} {
+			if err := repo.Create(ctx, &r); err != nil {
+				t.Fatalf("create %s: %v", r.ID, err)
+			}
+		}
+		if got, err := repo.Get(ctx, "a", "priv"); err != nil || got.ID != "R1" || got.CreatedAt != 1 {
+			t.Fatalf("get = %+v err=%v", got, err)
+		}
+		if _, err := repo.Get(ctx, "priv", "a"); !errors.Is(err, domain.ErrFollowRequestNotFound) {
+			t.Fatalf("want ErrFollowRequestNotFound, got %v", err)
+		}
+
+		p, err := repo.Page(ctx, "priv", ports.PageQuery{Limit: 1})
+		if err != nil || len(p.Items) != 1 || p.Items[0].RequesterID != "b" || !p.HasMore {
+			t.Fatalf("page 1 = %+v err=%v", p, err)
+		}
+		p, _ = repo.Page(ctx, "priv", ports.PageQuery{Limit: 1, Cursor: &domain.Cursor{CreatedAt: 2, ID: "b"}})
+		if len(p.Items) != 1 || p.Items[0].RequesterID != "a" || p.HasMore {
+			t.Fatalf("page 2 = %+v", p)
+		}
+
+		if err := repo.Delete(ctx, "a", "priv"); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if err := repo.Delete(ctx, "a", "priv"); err != nil {
+			t.Fatalf("delete is not idempotent: %v", err)
+		}
+		if _, err := repo.Get(ctx, "a", "priv"); !errors.Is(err, domain.ErrFollowRequestNotFound) {
+			t.Fatalf("expected request removed, got %v", err)
+		}
+	})
+}
+
+func TestUserCounters(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		users := NewUserRepoGorm(db)
+		follows := NewFollowRepoGorm(db)
+		tweets := NewTweetRepoGorm(db)
+		for i, h := range []string{"ana", "bob"} {
+			u, _ := domain.NewUser(h, h, "", "", int64(i))
+			if err := users.Create(ctx, &u); err != nil {
+				t.Fatalf("create user: %v", err)
+			}
+		}
+		counters := func(id string) [3]int64 {
+			t.Helper()
+			u, err := users.Get(ctx, id)
+			if err != nil {
+				t.Fatalf("get: %v", err)
+			}
+			return [3]int64{u.FollowerCount, u.FollowingCount, u.TweetCount}
+		}
+
+		// follow repetido y unfollow repetido no mueven los contadores
+		for _, id := range []string{"F1", "F2"} {
+			if err := follows.Create(ctx, &domain.Follow{ID: id, FollowerID: "bob", FolloweeID: "ana", CreatedAt: 1}); err != nil {
+				t.Fatalf("follow: %v", err)
+			}
+		}
+		if got := counters("ana"); got != [3]int64{1, 0, 0} {
+			t.Fatalf("ana after follow = %v", got)
+		}
+		if got := counters("bob"); got != [3]int64{0, 1, 0} {
+			t.Fatalf("bob after follow = %v", got)
+		}
+		for i := 0; i < 2; i++ {
+			if err := follows.Unfollow(ctx, "bob", "ana"); err != nil {
+				t.Fatalf("unfollow: %v", err)
+			}
+		}
+		if a, b := counters("ana"), counters("bob"); a != [3]int64{} || b != [3]int64{} {
+			t.Fatalf("after unfollow ana=%v bob=%v", a, b)
+		}
+
+		// tweets: cuentan los vivos propios, no los retweets
+		for _, id := range []string{"A", "B", "C"} {
+			tw := domain.Tweet{ID: id, UserID: "ana", Text: id, CreatedAt: 2}
+			if err := tweets.Create(ctx, &tw); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+		rt := domain.Tweet{ID: "R1", UserID: "bob", CreatedAt: 3, RetweetOfID: "A"}
+		if _, err := tweets.Retweet(ctx, &rt); err != nil {
+			t.Fatalf("retweet: %v", err)
+		}
+		if err := tweets.SoftDelete(ctx, "R1", 4); err != nil {
+			t.Fatalf("unretweet: %v", err)
+		}
+		if err := tweets.SoftDelete(ctx, "B", 4); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if _, err := tweets.HardDelete(ctx, "B"); err != nil {
+			t.Fatalf("hard delete tombstone: %v", err)
+		}
+		if _, err := tweets.HardDelete(ctx, "C"); err != nil {
+			t.Fatalf("hard delete: %v", err)
+		}
+		if got, b := counters("ana"), counters("bob"); got != [3]int64{0, 0, 1} || b != [3]int64{} {
+			t.Fatalf("tweet counters ana=%v bob=%v", got, b)
+		}
+
+		// reconciliación: corrige lo que se escribió por fuera
+		if err := db.Exec("UPDATE users SET tweet_count = 9, follower_count = 3 WHERE id = 'ana'").Error; err != nil {
+			t.Fatalf("corrupt: %v", err)
+		}
+		if err := db.Exec("INSERT INTO follow_models (id, follower_id, followee_id, created_at) VALUES ('F9', 'ana', 'bob', 5)").Error; err != nil {
+			t.Fatalf("raw follow: %v", err)
+		}
+		fixed, err := ReconcileUserCounters(ctx, db)
+		if err != nil || fixed != 2 {
+			t.Fatalf("reconcile fixed=%d err=%v", fixed, err)
+		}
+		if a, b := counters("ana"), counters("bob"); a != [3]int64{0, 1, 1} || b != [3]int64{1, 0, 0} {
+			t.Fatalf("after reconcile ana=%v bob=%v", a, b)
+		}
+		if fixed, _ := ReconcileUserCounters(ctx, db); fixed != 0 {
+			t.Fatalf("second reconcile fixed %d", fixed)
+		}
+	})
+}
+
+func TestTweetRepo_HardDelete(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		likes := NewLikeRepoGorm(db)
+		orig := domain.Tweet{ID: "A", UserID: "u2", Text: "#spam para @u1", CreatedAt: 1,
+			Mentions: []domain.Mention{{UserID: "u1", Handle: "u1", ByteStart: 11, ByteEnd: 14, CharStart: 11, CharEnd: 14}}}
+		if err := tweets.Create(ctx, &orig); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		other := domain.Tweet{ID: "B", UserID: "u2", Text: "#spam también", CreatedAt: 2}
+		if err := tweets.Create(ctx, &other); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		rt := domain.Tweet{ID: "R1", UserID: "u3", CreatedAt: 3, RetweetOfID: "A"}
+		if _, err := tweets.Retweet(ctx, &rt); err != nil {
+			t.Fatalf("retweet: %v", err)
+		}
+		l := domain.Like{ID: "L1", UserID: "u1", TweetID: "A", CreatedAt: 4}
+		if err := likes.Create(ctx, &l); err != nil {
+			t.Fatalf("like: %v", err)
+		}
+		updated, rev, _ := orig.Edit("V1", "#spam editado", 5, 0)
+		updated.Mentions = orig.Mentions
+		if err := tweets.Edit(ctx, &updated, &rev); err != nil {
+			t.Fatalf("edit: %v", err)
+		}
+
+		ids, err := tweets.HardDelete(ctx, "A")
+		if err != nil || !reflect.DeepEqual(ids, []string{"A", "R1"}) {
+			t.Fatalf("hard delete ids=%v err=%v", ids, err)
+		}
+		if _, err := tweets.HardDelete(ctx, "A"); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("second hard delete: want ErrTweetNotFound, got %v", err)
+		}
+		for table, want := range map[string]int64{
+			"tweet_models": 1, "tweet_revisions": 0, "tweet_hashtags": 1, "tweet_mentions": 0, "likes": 0,
+		} {
+			var n int64
+			if err := db.Table(table).Count(&n).Error; err != nil || n != want {
+				t.Fatalf("%s: %d rows (want %d) err=%v", table, n, want, err)
+			}
+		}
+
+		// también sobre tombstones
+		if err := tweets.SoftDelete(ctx, "B", 6); err != nil {
+			t.Fatalf("soft delete: %v", err)
+		}
+		if ids, err := tweets.HardDelete(ctx, "B"); err != nil || !reflect.DeepEqual(ids, []string{"B"}) {
+			t.Fatalf("hard delete tombstone ids=%v err=%v", ids, err)
+		}
+	})
+}
+
+func TestAPIKeyRepo(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		keys := NewAPIKeyRepoGorm(db)
+		k1, tok1, _ := domain.NewAPIKey("K1", "u1", "laptop", "s1", 1)
+		k2, _, _ := domain.NewAPIKey("K2", "u1", "", "s2", 2)
+		other, _, _ := domain.NewAPIKey("K9", "u2", "", "s9", 3)
+		for _, k := range []*domain.APIKey{&k1, &k2, &other} {
+			if err := keys.Create(ctx, k); err != nil {
+				t.Fatalf("create %s: %v", k.ID, err)
+			}
+		}
+		if got, err := keys.ByHash(ctx, domain.HashAPIKey(tok1)); err != nil || got != k1 {
+			t.Fatalf("by hash = %#v err=%v", got, err)
+		}
+		if _, err := keys.Get(ctx, "nope"); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+			t.Fatalf("want ErrAPIKeyNotFound, got %v", err)
+		}
+
+		if err := keys.Revoke(ctx, "K1", 10); err != nil {
+			t.Fatalf("revoke: %v", err)
+		}
+		if err := keys.Revoke(ctx, "K1", 20); err != nil {
+			t.Fatalf("revoke twice: %v", err)
+		}
+		if got, _ := keys.Get(ctx, "K1"); got.RevokedAt != 10 || got.Active() {
+			t.Fatalf("revoked_at must keep the first revocation: %#v", got)
+		}
+
+		k3, _, _ := domain.NewAPIKey("K3", "u1", "", "s3", 30)
+		if err := keys.Rotate(ctx, "K2", &k3, 30); err != nil {
+			t.Fatalf("rotate: %v", err)
+		}
+		list, err := keys.ListByUser(ctx, "u1")
+		if err != nil || len(list) != 3 || list[0].ID != "K3" || list[1].RevokedAt != 30 || list[2].ID != "K1" {
+			t.Fatalf("list = %#v err=%v", list, err)
+		}
+	})
+}
+
+func TestRefreshTokenRepo(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		repo := NewRefreshTokenRepoGorm(db)
+		r1, tok1, _ := domain.NewRefreshToken("R1", "u1", "K1", "s1", []string{domain.ScopeTweetsWrite, domain.ScopeTimelineRead}, 1, 100)
+		if err := repo.Create(ctx, &r1); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		got, err := repo.ByHash(ctx, domain.HashAPIKey(tok1))
+		if err != nil || got.ID != "R1" || got.APIKeyID != "K1" || len(got.Scopes) != 2 || got.ExpiresAt != 101 || got.RevokedAt != 0 {
+			t.Fatalf("by hash = %#v err=%v", got, err)
+		}
+		if _, err := repo.ByHash(ctx, "nope"); !errors.Is(err, domain.ErrUnauthenticated) {
+			t.Fatalf("want ErrUnauthenticated, got %v", err)
+		}
+
+		r2, _, _ := domain.NewRefreshToken("R2", "u1", "K1", "s2", got.Scopes, 5, 100)
+		if err := repo.Rotate(ctx, "R1", &r2, 5); err != nil {
+			t.Fatalf("rotate: %v", err)
+		}
+		r3, _, _ := domain.NewRefreshToken("R3", "u1", "K1", "s3", got.Scopes, 6, 100)
+		if err := repo.Rotate(ctx, "R1", &r3, 6); !errors.Is(err, domain.ErrUnauthenticated) {
+			t.Fatalf("second rotate of R1: want ErrUnauthenticated, got %v", err)
+		}
+		var n int64
+		db.Model(&RefreshTokenModel{}).Where("id = ?", "R3").Count(&n)
+		if n != 0 {
+			t.Fatal("failed rotation must not create the new token")
+		}
+
+		if err := repo.RevokeUser(ctx, "u1", 7); err != nil {
+			t.Fatalf("revoke user: %v", err)
+		}
+		var rows []RefreshTokenModel
+		db.Order("id").Find(&rows)
+		if len(rows) != 2 || *rows[0].RevokedAt != 5 || *rows[1].RevokedAt != 7 {
+			t.Fatalf("unexpected rows: %+v", rows)
+		}
+	})
+}
+
+func TestTweetRepo_Mentions(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		tw, _ := domain.NewTweet("T1", "U1", "hola @bob y @Carla", 2)
+		tw.ResolveMentions(map[string]string{"bob": "U2", "carla": "U3"})
+		if err := tweets.Create(ctx, &tw); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+
+		got, _ := tweets.Get(ctx, "T1")
+		if !reflect.DeepEqual(got.Mentions, tw.Mentions) || got.Mentions[1].Handle != "Carla" {
+			t.Fatalf("mentions not persisted: %#v", got.Mentions)
+		}
+		page, err := tweets.MentionTimelinePage(ctx, "U3", ports.PageQuery{})
+		if err != nil || len(page.Items) != 1 {
+			t.Fatalf("mention page = %#v err=%v", page, err)
+		}
+
+		updated, rev, _ := got.Edit("R1", "sólo @bob", 3, 0)
+		updated.ResolveMentions(map[string]string{"bob": "U2"})
+		if err := tweets.Edit(ctx, &updated, &rev); err != nil {
+			t.Fatalf("edit: %v", err)
+		}
+		if page, _ := tweets.MentionTimelinePage(ctx, "U3", ports.PageQuery{}); len(page.Items) != 0 {
+			t.Fatalf("edit should drop carla's mention: %#v", page.Items)
+		}
+		if got, _ := tweets.Get(ctx, "T1"); len(got.Mentions) != 1 || got.Mentions[0].ByteStart != 6 || got.Mentions[0].UserID != "U2" {
+			t.Fatalf("mentions after edit: %#v", got.Mentions)
+		}
+	})
+}
+
+func TestTweetSearch(t *testing.T) {
+	forEachDB(t, func(t *testing.T, db *gorm.DB) {
+		ctx := context.Background()
+		tweets := NewTweetRepoGorm(db)
+		for i, tw := range []domain.Tweet{
+			{ID: "T0", UserID: "ana", Text: "el café de la esquina", CreatedAt: 86400},
+			{ID: "T1", UserID: "bob", Text: "café café café con leche", CreatedAt: 2 * 86400},
+			{ID: "T2", UserID: "ana", Text: "la esquina del café", CreatedAt: 3 * 86400},
+			{ID: "T3", UserID: "bob", Text: "té verde", CreatedAt: 4 * 86400},
+		} {
+			if err := tweets.Create(ctx, &tw); err != nil {
+				t.Fatalf("create %d: %v", i, err)
+			}
+		}
+		search, err := NewTweetSearchGorm(ctx, db)
+		if err != nil {
+			t.Fatalf("searcher: %v", err)
+		}
+		ids := func(raw, order string, pq ports.PageQuery) []string {
+			t.Helper()
+			q, err := domain.ParseSearchQuery(raw, order)
+			if err != nil {
+				t.Fatalf("parse %q: %v", raw, err)
+			}
+			p, err := search.Search(ctx, q, pq)
+			if err != nil {
+				t.Fatalf("search %q: %v", raw, err)
+			}
+			out := []string{}
+			for _, h := range p.Items {
+				out = append(out, h.ID)
+			}
+			return out
+		}
+
+		if got := ids("café", "", ports.PageQuery{}); !reflect.DeepEqual(got, []string{"T2", "T1", "T0"}) {
+			t.Fatalf("recent = %v", got)
+		}
+		if got := ids("café from:ana", "", ports.PageQuery{}); !reflect.DeepEqual(got, []string{"T2", "T0"}) {
+			t.Fatalf("from = %v", got)
+		}
+		if got := ids("café since:1970-01-03 until:1970-01-04", "", ports.PageQuery{}); !reflect.DeepEqual(got, []string{"T1"}) {
+			t.Fatalf("since/until = %v", got)
+		}
+		if got := ids(`"la esquina"`, "", ports.PageQuery{}); !reflect.DeepEqual(got, []string{"T2", "T0"}) {
+			t.Fatalf("phrase = %v", got)
+		}
+		if got := ids("café", "", ports.PageQuery{Limit: 2}); !reflect.DeepEqual(got, []string{"T2", "T1"}) {
+			t.Fatalf("page = %v", got)
+		}
+
+		// el índice sigue a ediciones y borrados
+		tw, _ := tweets.Get(ctx, "T3")
+		updated, rev, _ := tw.Edit("R1", "té con café", 5*86400, 0)
+		if err := tweets.Edit(ctx, &updated, &rev); err != nil {
+			t.Fatalf("edit: %v", err)
+		}
+		if err := tweets.SoftDelete(ctx, "T2", 5*86400); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if got := ids("café", "", ports.PageQuery{}); !reflect.DeepEqual(got, []string{"T3", "T1", "T0"}) {
+			t.Fatalf("after edit/delete = %v", got)
+		}
+
+		if !search.FTS() {
+			t.Skip("sin FTS5: relevancia, frases por token y acentos usan el fallback LIKE")
+		}
+		if got := ids(`"esquina de"`, "", ports.PageQuery{}); !reflect.DeepEqual(got, []string{}) {
+			t.Fatalf("phrase order matters = %v", got)
+		}
+		if got := ids("cafe", "", ports.PageQuery{}); len(got) != 3 {
+			t.Fatalf("diacritics folded = %v", got)
+		}
+		// T0 y T1 tienen el mismo largo: T1 repite el término y rankea antes
+		if got := ids("café", "relevance", ports.PageQuery{}); len(got) != 3 || !reflect.DeepEqual(got[1:], []string{"T1", "T0"}) {
+			t.Fatalf("relevance = %v", got)
+		}
+		if _, err := RebuildSearchIndex(ctx, db); err != nil {
+			t.Fatalf("rebuild: %v", err)
+		}
+		if got := ids("verde", "", ports.PageQuery{}); len(got) != 0 {
+			t.Fatalf("rebuild kept stale text = %v", got)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	SQLiteModeMemory = "memory"
+	SQLiteModeFile   = "file"
+)
+
+// SQLiteConfig describe cómo abrir la base SQLite: en memoria (tests) o en
+// archivo con WAL para que los datos sobrevivan a un reinicio.
+type SQLiteConfig struct {
+	Mode            string
+	Path            string
+	DSN             string // override completo; ignora el resto de los campos de conexión
+	BusyTimeout     time.Duration
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+func SQLiteConfigFromEnv() SQLiteConfig {
+	cfg := SQLiteConfig{
+		Mode:        SQLiteModeMemory,
+		Path:        "data/tweets.db",
+		DSN:         os.Getenv("SQLITE_DSN"),
+		BusyTimeout: time.Duration(envInt("SQLITE_BUSY_TIMEOUT_MS", 5000)) * time.Millisecond,
+	}
+	if v := os.Getenv("SQLITE_MODE"); v != "" {
+		cfg.Mode = v
+	}
+	if v := os.Getenv("SQLITE_PATH"); v != "" {
+		cfg.Path = v
+	}
+	cfg.MaxOpenConns = envInt("SQLITE_MAX_OPEN_CONNS", 0)
+	cfg.MaxIdleConns = envInt("SQLITE_MAX_IDLE_CONNS", 0)
+	cfg.ConnMaxLifetime = time.Duration(envInt("SQLITE_CONN_MAX_LIFETIME_SEC", 0)) * time.Second
+	return cfg
+}
+
+func (c SQLiteConfig) dsn() (string, error) {
+	if c.DSN != "" {
+		return c.DSN, nil
+	}
+	switch c.Mode {
+	case "", SQLiteModeMemory:
+		// nombre único; cache=shared permite que el pool interno comparta la misma DB
+		name := fmt.Sprintf("mem_%d", time.Now().UnixNano())
+		return fmt.Sprintf("file:%s?mode=memory&cache=shared&_fk=1", name), nil
+	case SQLiteModeFile:
+		if c.Path == "" {
+			return "", fmt.Errorf("sqlite: path required in file mode")
+		}
+		busy := c.BusyTimeout
+		if busy <= 0 {
+			busy = 5 * time.Second
+		}
+		// WAL: lectores concurrentes con un escritor; txlock=immediate evita
+		// deadlocks al promover una tx de lectura a escritura.
+		return fmt.Sprintf("file:%s?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=%d&_txlock=immediate&_fk=1",
+			c.Path, busy.Milliseconds()), nil
+	default:
+		return "", fmt.Errorf("sqlite: unknown mode %q", c.Mode)
+	}
+}
+
+func OpenSQLite(cfg SQLiteConfig) (*gorm.DB, error) {
+	dsn, err := cfg.dsn()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Mode == SQLiteModeFile && cfg.DSN == "" {
+		if dir := filepath.Dir(cfg.Path); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("sqlite: create dir: %w", err)
+			}
+		}
+	}
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	maxOpen, maxIdle := cfg.MaxOpenConns, cfg.MaxIdleConns
+	if cfg.Mode == SQLiteModeFile {
+		if maxOpen <= 0 {
+			maxOpen = 4
+		}
+		if maxIdle <= 0 {
+			maxIdle = maxOpen
+		}
+	}
+	if maxOpen > 0 {
+		sqlDB.SetMaxOpenConns(maxOpen)
+	}
+	if maxIdle > 0 {
+		sqlDB.SetMaxIdleConns(maxIdle)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	return db, nil
+}
+
+func NewInMemoryGorm() (*gorm.DB, error) {
+	return OpenSQLite(SQLiteConfig{Mode: SQLiteModeMemory, DSN: os.Getenv("SQLITE_DSN")})
+}
+
+// Close libera el pool subyacente de un *gorm.DB.
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+func envInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
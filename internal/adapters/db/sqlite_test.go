@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"tweetschallenge/internal/domain"
+)
+
+func TestOpenSQLite_FilePersistsAcrossReopen(t *testing.T) {
+	cfg := SQLiteConfig{Mode: SQLiteModeFile, Path: filepath.Join(t.TempDir(), "nested", "tweets.db")}
+
+	db, err := OpenSQLite(cfg)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	migrateForTest(t, db)
+	var mode string
+	if err := db.Raw("PRAGMA journal_mode").Scan(&mode).Error; err != nil {
+		t.Fatalf("pragma: %v", err)
+	}
+	if mode != "wal" {
+		t.Fatalf("journal_mode = %q, want wal", mode)
+	}
+	tw := domain.Tweet{ID: "T1", UserID: "u1", Text: "hola", CreatedAt: 1}
+	if err := NewTweetRepoGorm(db).Create(context.Background(), &tw); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := Close(db); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	db, err = OpenSQLite(cfg)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer Close(db)
+	got, err := NewTweetRepoGorm(db).Timeline(context.Background(), "u1", 10, 0)
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "T1" {
+		t.Fatalf("unexpected tweets after reopen: %#v", got)
+	}
+}
+
+func TestOpenSQLite_MemoryIsolated(t *testing.T) {
+	a, err := OpenSQLite(SQLiteConfig{Mode: SQLiteModeMemory})
+	if err != nil {
+		t.Fatalf("open a: %v", err)
+	}
+	defer Close(a)
+	b, err := OpenSQLite(SQLiteConfig{Mode: SQLiteModeMemory})
+	if err != nil {
+		t.Fatalf("open b: %v", err)
+	}
+	defer Close(b)
+
+	migrateForTest(t, a)
+	if b.Migrator().HasTable(&TweetModel{}) {
+		t.Fatal("memory databases should not share state")
+	}
+}
+
+func TestOpenSQLite_UnknownMode(t *testing.T) {
+	if _, err := OpenSQLite(SQLiteConfig{Mode: "tape"}); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
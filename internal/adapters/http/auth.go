@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const callerKey = "caller"
+
+// RequireAuth acepta una API key o un access token JWT como
+// `Authorization: Bearer <token>` (la API key también en `X-API-Key`) y deja
+// el domain.Principal en el contexto (ver caller/callerID).
+func RequireAuth(auth usecase.Authenticate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if authenticate(c, auth, credential(c)) {
+			c.Next()
+		}
+	}
+}
+
+// OptionalAuth es RequireAuth para lecturas públicas: sin credencial sigue
+// como anónimo (callerID vacío), pero una credencial inválida es 401.
+func OptionalAuth(auth usecase.Authenticate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := credential(c); token == "" || authenticate(c, auth, token) {
+			c.Next()
+		}
+	}
+}
+
+func credential(c *gin.Context) string {
+	token := c.GetHeader("X-API-Key")
+	if h := c.GetHeader("Authorization"); token == "" && len(h) > 7 && strings.EqualFold(h[:7], "Bearer ") {
+		token = strings.TrimSpace(h[7:])
+	}
+	return token
+}
+
+// authenticate deja el principal en el contexto o aborta (401/500).
+func authenticate(c *gin.Context, auth usecase.Authenticate, token string) bool {
+	p, err := auth.Exec(c, token)
+	if err != nil {
+		if !errors.Is(err, domain.ErrUnauthenticated) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return false
+		}
+		c.Header("WWW-Authenticate", `Bearer realm="tweetschallenge"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return false
+	}
+	c.Set(callerKey, p)
+	return true
+}
+
+// RequireScope rechaza con 403 los access tokens sin scope (las API keys
+// tienen todos). Va después de RequireAuth.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !caller(c).HasScope(scope) {
+			c.Header("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+scope+`"`)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": domain.ErrInsufficientScope.Error(), "scope": scope})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAPIKey restringe a API keys lo que administra credenciales: un
+// access token con scopes acotados no puede emitirse otra key ni otro token.
+func RequireAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if caller(c).Method != domain.AuthAPIKey {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "this endpoint requires an API key"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin deja pasar sólo a usuarios con rol admin. El rol se lee de la
+// base en cada request (no va en el token), así revocarlo es inmediato.
+// Va después de RequireAuth.
+func RequireAdmin(users usecase.GetUser) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		u, err := users.Exec(c, callerID(c))
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !u.IsAdmin() {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "this endpoint requires the admin role"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// caller es el usuario autenticado; cero fuera de las rutas con RequireAuth
+// (o anónimo con OptionalAuth).
+func caller(c *gin.Context) domain.Principal {
+	p, _ := c.Get(callerKey)
+	principal, _ := p.(domain.Principal)
+	return principal
+}
+
+func callerID(c *gin.Context) string { return caller(c).UserID }
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"tweetschallenge/internal/domain"
+)
+
+// errorStatus traduce errores de dominio conocidos a códigos HTTP; el resto
+// son violaciones de reglas (422).
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrInvalidHashtag), errors.Is(err, domain.ErrInvalidSearchQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrNotAuthor), errors.Is(err, domain.ErrForbidden), errors.Is(err, domain.ErrInsufficientScope),
+		errors.Is(err, domain.ErrSuspended), errors.Is(err, domain.ErrBlocked):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrTweetNotFound), errors.Is(err, domain.ErrUserNotFound), errors.Is(err, domain.ErrAPIKeyNotFound),
+		errors.Is(err, domain.ErrFollowRequestNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrHandleTaken):
+		return http.StatusConflict
+	default:
+		return 422
+	}
+}
+
+func writeError(c *gin.Context, err error) {
+	c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler agrupa los endpoints de moderación (/v1/admin, rol admin).
+type AdminHandler struct {
+	GetUser     usecase.GetUser
+	UserTweets  usecase.AdminUserTweets
+	UserFollows usecase.GetUserFollows
+	DeleteTweet usecase.HardDeleteTweet
+	Suspend     usecase.SuspendUser
+	Limiter     ports.RateLimiter
+}
+
+// @Summary Admin: user tweets
+// @Description Tweets de cualquier usuario, aunque esté suspendido; misma paginación que el perfil.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "user id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/users/{userID}/tweets [get]
+func (h AdminHandler) Tweets(c *gin.Context) {
+	if _, err := h.GetUser.Exec(c, c.Param("userID")); err != nil {
+		writeError(c, err)
+		return
+	}
+	limit, cursor, _ := pageParams(c)
+	page, err := h.UserTweets.Exec(c, usecase.GetUserTweetsInput{UserID: c.Param("userID"), Limit: limit, Cursor: cursor})
+	writePage(c, page, err)
+}
+
+// @Summary Admin: user follows
+// @Description Follows del usuario (`direction=following`, default) o hacia él (`direction=followers`), más recientes primero; paginado por cursor como `/v1/users/{userID}/followers`.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "user id"
+// @Param direction query string false "following | followers"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/users/{userID}/follows [get]
+func (h AdminHandler) Follows(c *gin.Context) {
+	in := usecase.GetUserFollowsInput{}
+	switch c.Query("direction") {
+	case "", "following":
+	case "followers":
+		in.Followers = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "direction must be following or followers"})
+		return
+	}
+	limit, cursor, _ := pageParams(c)
+	in.FollowListInput = usecase.FollowListInput{UserID: c.Param("userID"), Limit: limit, Cursor: cursor}
+	page, err := h.UserFollows.Exec(c, in)
+	writePage(c, page, err)
+}
+
+// @Summary Admin: hard-delete tweet
+// @Description Borra el tweet sin dejar tombstone, junto con sus retweets, likes y revisiones. Funciona también sobre tweets ya borrados por su autor.
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param id path string true "tweet id"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/tweets/{id} [delete]
+func (h AdminHandler) HardDelete(c *gin.Context) {
+	if err := h.DeleteTweet.Exec(c, c.Param("id")); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Admin: suspend user
+// @Description La cuenta suspendida no puede publicar y sus tweets (y retweets) desaparecen de los timelines. Idempotente.
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "user id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/users/{userID}/suspension [post]
+func (h AdminHandler) SuspendUser(c *gin.Context) { h.setSuspended(c, true) }
+
+// @Summary Admin: unsuspend user
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "user id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/users/{userID}/suspension [delete]
+func (h AdminHandler) UnsuspendUser(c *gin.Context) { h.setSuspended(c, false) }
+
+func (h AdminHandler) setSuspended(c *gin.Context, suspended bool) {
+	u, err := h.Suspend.Exec(c, usecase.SuspendUserInput{UserID: c.Param("userID"), Suspended: suspended})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": u})
+}
+
+// @Summary Admin: reset rate limit
+// @Description Vacía el bucket de rate limit del usuario (tweets y retweets).
+// @Tags admin
+// @Security ApiKeyAuth
+// @Param userID path string true "user id"
+// @Success 204
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/admin/users/{userID}/rate-limit [delete]
+func (h AdminHandler) ResetRateLimit(c *gin.Context) {
+	if _, err := h.GetUser.Exec(c, c.Param("userID")); err != nil {
+		writeError(c, err)
+		return
+	}
+	if h.Limiter != nil {
+		if err := h.Limiter.Reset(c, c.Param("userID")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyHandler struct {
+	Issue  usecase.IssueAPIKey
+	List   usecase.ListAPIKeys
+	Rotate usecase.RotateAPIKey
+	Revoke usecase.RevokeAPIKey
+}
+
+type CreateAPIKeyReq struct {
+	Name string `json:"name"` // opcional, ≤ 50 caracteres
+}
+
+// @Summary Issue API key
+// @Description Emite una key para el usuario autenticado. El `token` sólo se devuelve en esta respuesta.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param payload body CreateAPIKeyReq false "payload"
+// @Success 201 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/api-keys [post]
+func (h APIKeyHandler) Create(c *gin.Context) {
+	var req CreateAPIKeyReq
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+			return
+		}
+	}
+	k, err := h.Issue.Exec(c, usecase.IssueAPIKeyInput{UserID: callerID(c), Name: req.Name})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": k})
+}
+
+// @Summary List API keys
+// @Description Keys del usuario autenticado (incluye revocadas), sin el token.
+// @Tags api-keys
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Router /v1/api-keys [get]
+func (h APIKeyHandler) ListKeys(c *gin.Context) {
+	list, err := h.List.Exec(c, callerID(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// @Summary Rotate API key
+// @Description Emite una key nueva con el mismo nombre y revoca la anterior en la misma transacción.
+// @Tags api-keys
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "api key id"
+// @Success 201 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/api-keys/{id}/rotate [post]
+func (h APIKeyHandler) RotateKey(c *gin.Context) {
+	k, err := h.Rotate.Exec(c, usecase.APIKeyInput{UserID: callerID(c), KeyID: c.Param("id")})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": k})
+}
+
+// @Summary Revoke API key
+// @Description Idempotente. Revocar la key con la que se hace el request también vale.
+// @Tags api-keys
+// @Security ApiKeyAuth
+// @Param id path string true "api key id"
+// @Success 204 {string} string ""
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/api-keys/{id} [delete]
+func (h APIKeyHandler) RevokeKey(c *gin.Context) {
+	if err := h.Revoke.Exec(c, usecase.APIKeyInput{UserID: callerID(c), KeyID: c.Param("id")}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BlockHandler struct {
+	BlockUser   usecase.BlockUser
+	UnblockUser usecase.UnblockUser
+	Blocks      usecase.GetBlocks
+}
+
+// BlockReq: el que bloquea es el usuario autenticado.
+type BlockReq struct {
+	BlockedID string `json:"blocked_id" binding:"required"`
+}
+
+// @Summary Block user
+// @Description Corta los follows en ambos sentidos, impide volver a seguirse y oculta los tweets del que bloquea en el timeline del bloqueado. Idempotente.
+// @Tags blocks
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param payload body BlockReq true "payload"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/blocks [post]
+func (h BlockHandler) Create(c *gin.Context) {
+	var req BlockReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	b, err := h.BlockUser.Exec(c, usecase.BlockUserInput{BlockerID: callerID(c), BlockedID: req.BlockedID})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": b})
+}
+
+// @Summary Unblock user
+// @Description Los follows cortados por el bloqueo no se restauran.
+// @Tags blocks
+// @Accept json
+// @Security ApiKeyAuth
+// @Param payload body BlockReq true "payload"
+// @Success 204 {string} string ""
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /v1/blocks [delete]
+func (h BlockHandler) Delete(c *gin.Context) {
+	var req BlockReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := h.UnblockUser.Exec(c, usecase.BlockUserInput{BlockerID: callerID(c), BlockedID: req.BlockedID}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Blocks
+// @Description Usuarios bloqueados por el usuario, más recientes primero; paginado por cursor. Sólo el propio usuario.
+// @Tags blocks
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "user id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/users/{userID}/blocks [get]
+func (h BlockHandler) List(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.Blocks.Exec(c, usecase.GetBlocksInput{
+		UserID: c.Param("userID"), ActorID: callerID(c), Limit: limit, Cursor: cursor,
+	})
+	writePage(c, page, err)
+}
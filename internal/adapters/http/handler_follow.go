@@ -11,10 +11,12 @@ import (
 type FollowHandler struct {
 	FollowUser   usecase.FollowUser
 	UnfollowUser usecase.UnfollowUser
+	Followers    usecase.GetFollowers
+	Following    usecase.GetFollowing
 }
 
+// FollowReq: el seguidor es el usuario autenticado.
 type FollowReq struct {
-	FollowerID string `json:"follower_id" binding:"required"`
 	FolloweeID string `json:"followee_id" binding:"required"`
 }
 
@@ -22,9 +24,13 @@ type FollowReq struct {
 // @Tags follows
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param payload body FollowReq true "payload"
 // @Success 201 {object} map[string]interface{}
+// @Success 202 {object} map[string]interface{} "cuenta protegida: solicitud pendiente"
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 422 {object} map[string]string
 // @Router /v1/follows [post]
 func (h FollowHandler) Create(c *gin.Context) {
@@ -33,23 +39,30 @@ func (h FollowHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
 		return
 	}
-	f, err := h.FollowUser.Exec(c, usecase.FollowUserInput{
-		FollowerID: req.FollowerID, FolloweeID: req.FolloweeID,
+	res, err := h.FollowUser.Exec(c, usecase.FollowUserInput{
+		FollowerID: callerID(c), FolloweeID: req.FolloweeID,
 	})
 	if err != nil {
-		c.JSON(422, gin.H{"error": err.Error()})
+		writeError(c, err)
+		return
+	}
+	if res.Request != nil {
+		c.JSON(http.StatusAccepted, gin.H{"data": res.Request})
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"data": f})
+	c.JSON(http.StatusCreated, gin.H{"data": res.Follow})
 }
 
 // @Summary Unfollow user
+// @Description También cancela una solicitud pendiente.
 // @Tags follows
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param payload body FollowReq true "payload"
 // @Success 204 {string} string ""
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
 // @Router /v1/follows [delete]
 func (h FollowHandler) Delete(c *gin.Context) {
 	var req FollowReq
@@ -58,10 +71,44 @@ func (h FollowHandler) Delete(c *gin.Context) {
 		return
 	}
 	if err := h.UnfollowUser.Exec(c, usecase.UnfollowUserInput{
-		FollowerID: req.FollowerID, FolloweeID: req.FolloweeID,
+		FollowerID: callerID(c), FolloweeID: req.FolloweeID,
 	}); err != nil {
 		c.JSON(422, gin.H{"error": err.Error()})
 		return
 	}
 	c.Status(http.StatusNoContent)
 }
+
+// @Summary Followers
+// @Description Follows hacia el usuario (`follower_id` es quien sigue), más recientes primero; paginado por cursor.
+// @Tags follows
+// @Produce json
+// @Param userID path string true "user id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/users/{userID}/followers [get]
+func (h FollowHandler) ListFollowers(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.Followers.Exec(c, usecase.FollowListInput{UserID: c.Param("userID"), Limit: limit, Cursor: cursor})
+	writePage(c, page, err)
+}
+
+// @Summary Following
+// @Description Follows del usuario (`followee_id` es a quien sigue), más recientes primero; paginado por cursor.
+// @Tags follows
+// @Produce json
+// @Param userID path string true "user id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/users/{userID}/following [get]
+func (h FollowHandler) ListFollowing(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.Following.Exec(c, usecase.FollowListInput{UserID: c.Param("userID"), Limit: limit, Cursor: cursor})
+	writePage(c, page, err)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FollowRequestHandler resuelve las solicitudes hacia la cuenta protegida del
+// usuario autenticado.
+type FollowRequestHandler struct {
+	List    usecase.ListFollowRequests
+	Approve usecase.ApproveFollowRequest
+	Reject  usecase.RejectFollowRequest
+}
+
+// @Summary Follow requests
+// @Description Solicitudes pendientes hacia el usuario autenticado (`requester_id` es quien pidió seguirlo), más recientes primero; paginado por cursor.
+// @Tags follows
+// @Produce json
+// @Security ApiKeyAuth
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /v1/follow-requests [get]
+func (h FollowRequestHandler) ListRequests(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.List.Exec(c, usecase.ListFollowRequestsInput{UserID: callerID(c), Limit: limit, Cursor: cursor})
+	writePage(c, page, err)
+}
+
+// @Summary Approve follow request
+// @Description Crea el follow de userID hacia el usuario autenticado.
+// @Tags follows
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "requester id"
+// @Success 201 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/follow-requests/{userID}/approve [post]
+func (h FollowRequestHandler) ApproveRequest(c *gin.Context) {
+	f, err := h.Approve.Exec(c, usecase.FollowRequestInput{TargetID: callerID(c), RequesterID: c.Param("userID")})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": f})
+}
+
+// @Summary Reject follow request
+// @Description Descarta la solicitud; userID puede volver a pedirla.
+// @Tags follows
+// @Security ApiKeyAuth
+// @Param userID path string true "requester id"
+// @Success 204 {string} string ""
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/follow-requests/{userID}/reject [post]
+func (h FollowRequestHandler) RejectRequest(c *gin.Context) {
+	if err := h.Reject.Exec(c, usecase.FollowRequestInput{TargetID: callerID(c), RequesterID: c.Param("userID")}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LikeHandler struct {
+	LikeTweet   usecase.LikeTweet
+	UnlikeTweet usecase.UnlikeTweet
+	TweetLikes  usecase.GetTweetLikes
+	UserLikes   usecase.GetUserLikes
+}
+
+// @Summary Like tweet
+// @Description Idempotente, como follow. `403` si el autor y el usuario se bloquearon.
+// @Tags likes
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "tweet id"
+// @Success 201 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/tweets/{id}/likes [post]
+func (h LikeHandler) Create(c *gin.Context) {
+	l, err := h.LikeTweet.Exec(c, usecase.LikeTweetInput{TweetID: c.Param("id"), UserID: callerID(c)})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": l})
+}
+
+// @Summary Unlike tweet
+// @Description Idempotente.
+// @Tags likes
+// @Security ApiKeyAuth
+// @Param id path string true "tweet id"
+// @Success 204 {string} string ""
+// @Failure 401 {object} map[string]string
+// @Router /v1/tweets/{id}/likes [delete]
+func (h LikeHandler) Delete(c *gin.Context) {
+	if err := h.UnlikeTweet.Exec(c, usecase.LikeTweetInput{TweetID: c.Param("id"), UserID: callerID(c)}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Liked by
+// @Description Usuarios que dieron like, los más recientes primero; paginado por cursor. `404` si el tweet es de una cuenta protegida que no se sigue.
+// @Tags likes
+// @Produce json
+// @Param id path string true "tweet id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/tweets/{id}/likes [get]
+func (h LikeHandler) ListByTweet(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.TweetLikes.Exec(c, usecase.GetTweetLikesInput{
+		TweetID: c.Param("id"), Limit: limit, Cursor: cursor, ViewerID: callerID(c),
+	})
+	writePage(c, page, err)
+}
+
+// @Summary User likes
+// @Description Tweets que le gustaron al usuario, ordenados por `liked_at`; paginado por cursor.
+// @Tags likes
+// @Produce json
+// @Param userID path string true "user id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/users/{userID}/likes [get]
+func (h LikeHandler) ListByUser(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.UserLikes.Exec(c, usecase.GetUserLikesInput{
+		UserID: c.Param("userID"), Limit: limit, Cursor: cursor, ViewerID: callerID(c),
+	})
+	writePage(c, page, err)
+}
@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MuteHandler maneja los mutes del usuario autenticado; sólo afectan su timeline.
+type MuteHandler struct {
+	Mute   usecase.MuteUser
+	Unmute usecase.UnmuteUser
+	List   usecase.ListMutes
+}
+
+// MuteReq: kind es account (value = user id), keyword o regex.
+type MuteReq struct {
+	Kind      string `json:"kind" binding:"required"`
+	Value     string `json:"value" binding:"required"`
+	ExpiresIn int64  `json:"expires_in"` // segundos; 0 = no vence
+}
+
+// @Summary Mute
+// @Description Oculta del timeline una cuenta (también sus retweets y los retweets de sus tweets), una palabra o frase completa o una regex RE2, sin dejar de seguir. Sin distinguir mayúsculas; los tweets propios no se silencian. Repetir un mute sólo cambia su vencimiento.
+// @Tags mutes
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param payload body MuteReq true "payload"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/mutes [post]
+func (h MuteHandler) Create(c *gin.Context) {
+	var req MuteReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	m, err := h.Mute.Exec(c, usecase.MuteUserInput{
+		UserID: callerID(c), Kind: req.Kind, Value: req.Value, ExpiresIn: req.ExpiresIn,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": m})
+}
+
+// @Summary List mutes
+// @Description Mutes vigentes del usuario autenticado, más recientes primero.
+// @Tags mutes
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /v1/mutes [get]
+func (h MuteHandler) ListMutes(c *gin.Context) {
+	list, err := h.List.Exec(c, callerID(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// @Summary Unmute
+// @Description Idempotente.
+// @Tags mutes
+// @Security ApiKeyAuth
+// @Param id path string true "mute id"
+// @Success 204 {string} string ""
+// @Failure 401 {object} map[string]string
+// @Router /v1/mutes/{id} [delete]
+func (h MuteHandler) Delete(c *gin.Context) {
+	if err := h.Unmute.Exec(c, usecase.UnmuteUserInput{UserID: callerID(c), MuteID: c.Param("id")}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,30 @@
+package http
+
+import (
+	"tweetschallenge/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	SearchTweets usecase.SearchTweets
+}
+
+// @Summary Search tweets
+// @Description Palabras (AND), "frases exactas" y operadores `from:handle`, `since:AAAA-MM-DD`, `until:AAAA-MM-DD` (UTC, until exclusivo). Excluye borrados y retweets; paginado por cursor.
+// @Tags search
+// @Produce json
+// @Param q query string true "query"
+// @Param order query string false "recent (default) | relevance"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/search [get]
+func (h SearchHandler) Search(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.SearchTweets.Exec(c, usecase.SearchTweetsInput{
+		Query: c.Query("q"), Order: c.Query("order"), Limit: limit, Cursor: cursor, ViewerID: callerID(c),
+	})
+	writePage(c, page, err)
+}
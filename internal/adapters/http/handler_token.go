@@ -0,0 +1,94 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/ports"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TokenHandler struct {
+	Issue   usecase.IssueTokens
+	Refresh usecase.RefreshTokens
+	Keys    ports.AccessTokens // JWKS
+}
+
+type TokenReq struct {
+	// Scopes: tweets:write, follows:write, timeline:read, follows:read,
+	// profile:write (default: todos).
+	Scopes []string `json:"scopes"`
+}
+
+type RefreshReq struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// @Summary Issue access token
+// @Description Canjea la API key por un access token JWT de vida corta y un refresh token de un solo uso.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param payload body TokenReq false "payload"
+// @Success 201 {object} usecase.TokenPair
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/auth/token [post]
+func (h TokenHandler) Create(c *gin.Context) {
+	var req TokenReq
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+			return
+		}
+	}
+	p := caller(c)
+	pair, err := h.Issue.Exec(c, usecase.IssueTokensInput{UserID: p.UserID, APIKeyID: p.KeyID, Scopes: req.Scopes})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, pair)
+}
+
+// @Summary Refresh access token
+// @Description Rota el refresh token: el enviado queda revocado y se devuelve un par nuevo con los mismos scopes. Reusar uno ya rotado revoca todos los refresh tokens del usuario; si la API key que emitió el primero fue revocada o rotada, `401`.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param payload body RefreshReq true "payload"
+// @Success 201 {object} usecase.TokenPair
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /v1/auth/refresh [post]
+func (h TokenHandler) RefreshPair(c *gin.Context) {
+	var req RefreshReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	pair, err := h.Refresh.Exec(c, req.RefreshToken)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, pair)
+}
+
+// @Summary JWKS
+// @Description Claves públicas (Ed25519) para verificar los access tokens, incluidas las retiradas que todavía verifican.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /.well-known/jwks.json [get]
+func (h TokenHandler) JWKS(c *gin.Context) {
+	keys := []ports.JWK{}
+	if h.Keys != nil {
+		keys = h.Keys.PublicKeys()
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
@@ -5,28 +5,46 @@ import (
 	"strconv"
 
 	"tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/ports"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TweetHandler struct {
-	PostTweet   usecase.PostTweet
-	GetTimeline usecase.GetTimeline
-	Limiter     *RateLimiter // rate limit por user_id
+	PostTweet     usecase.PostTweet
+	EditTweet     usecase.EditTweet
+	DeleteTweet   usecase.DeleteTweet
+	Revisions     usecase.GetTweetRevisions
+	GetThread     usecase.GetThread
+	Retweet       usecase.Retweet
+	Unretweet     usecase.Unretweet
+	GetTimeline   usecase.GetTimeline
+	GetUserTweets usecase.GetUserTweets
+	HashtagTweets usecase.GetHashtagTweets
+	UserMentions  usecase.GetUserMentions
+	Limiter       ports.RateLimiter // rate limit por usuario autenticado (nil = sin límite)
 }
 
+// CreateTweetReq: el autor es el usuario autenticado.
 type CreateTweetReq struct {
-	UserID string `json:"user_id" binding:"required"`
-	Text   string `json:"text"   binding:"required"`
+	Text string `json:"text" binding:"required"`
+	// InReplyToID convierte el tweet en respuesta; el padre debe existir.
+	InReplyToID string `json:"in_reply_to_id"`
+	// QuotedTweetID convierte el tweet en una cita del tweet referenciado.
+	QuotedTweetID string `json:"quoted_tweet_id"`
 }
 
 // @Summary Create tweet
 // @Tags tweets
 // @Accept json
 // @Produce json
+// @Security ApiKeyAuth
 // @Param payload body CreateTweetReq true "payload"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 429 {object} map[string]string
 // @Router /v1/tweets [post]
 func (h TweetHandler) Create(c *gin.Context) {
@@ -36,38 +54,228 @@ func (h TweetHandler) Create(c *gin.Context) {
 		return
 	}
 	// Rate limit por usuario
-	if h.Limiter != nil && !h.Limiter.Allow(req.UserID) {
-		c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+	if rateLimited(c, h.Limiter) {
 		return
 	}
 
-	tw, err := h.PostTweet.Exec(c, usecase.PostTweetInput{UserID: req.UserID, Text: req.Text})
+	tw, err := h.PostTweet.Exec(c, usecase.PostTweetInput{
+		UserID: callerID(c), Text: req.Text, InReplyToID: req.InReplyToID, QuotedTweetID: req.QuotedTweetID,
+	})
 	if err != nil {
-		c.JSON(422, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 	c.JSON(http.StatusCreated, gin.H{"data": tw})
 }
 
+type EditTweetReq struct {
+	Text string `json:"text" binding:"required"`
+}
+
+// @Summary Edit tweet
+// @Description Sólo el autor y dentro de la ventana de edición; el texto anterior queda como revisión.
+// @Tags tweets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "tweet id"
+// @Param payload body EditTweetReq true "payload"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/tweets/{id} [patch]
+func (h TweetHandler) Edit(c *gin.Context) {
+	var req EditTweetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	tw, err := h.EditTweet.Exec(c, usecase.EditTweetInput{TweetID: c.Param("id"), UserID: callerID(c), Text: req.Text})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tw})
+}
+
+// @Summary Tweet revisions
+// @Description Textos anteriores de un tweet editado, de la más vieja a la más nueva. `404` si es de una cuenta protegida que no se sigue.
+// @Tags tweets
+// @Produce json
+// @Param id path string true "tweet id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/tweets/{id}/revisions [get]
+func (h TweetHandler) ListRevisions(c *gin.Context) {
+	list, err := h.Revisions.Exec(c, usecase.GetTweetRevisionsInput{TweetID: c.Param("id"), ViewerID: callerID(c)})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": list})
+}
+
+// @Summary Tweet thread
+// @Description Conversación completa desde la raíz en orden de árbol (cada respuesta después de su padre) con `depth`. Los tweets borrados aparecen como tombstones.
+// @Tags tweets
+// @Produce json
+// @Param id path string true "tweet id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/tweets/{id}/thread [get]
+func (h TweetHandler) Thread(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.GetThread.Exec(c, usecase.GetThreadInput{
+		TweetID: c.Param("id"), Limit: limit, Cursor: cursor, ViewerID: callerID(c),
+	})
+	writePage(c, page, err)
+}
+
+// @Summary Retweet
+// @Description Idempotente por usuario/tweet: `201` al crearlo, `200` si ya existía. `403` si el autor del original y el usuario se bloquearon; `404` si es de una cuenta protegida que no se sigue.
+// @Tags tweets
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "tweet id"
+// @Success 201 {object} map[string]interface{}
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /v1/tweets/{id}/retweets [post]
+func (h TweetHandler) CreateRetweet(c *gin.Context) {
+	if rateLimited(c, h.Limiter) {
+		return
+	}
+	rt, created, err := h.Retweet.Exec(c, usecase.RetweetInput{TweetID: c.Param("id"), UserID: callerID(c)})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{"data": rt})
+}
+
+// @Summary Undo retweet
+// @Description Idempotente: deshacer un retweet inexistente también responde 204.
+// @Tags tweets
+// @Security ApiKeyAuth
+// @Param id path string true "tweet id"
+// @Success 204 {string} string ""
+// @Failure 401 {object} map[string]string
+// @Router /v1/tweets/{id}/retweets [delete]
+func (h TweetHandler) DeleteRetweet(c *gin.Context) {
+	if err := h.Unretweet.Exec(c, usecase.RetweetInput{TweetID: c.Param("id"), UserID: callerID(c)}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Delete tweet
+// @Description Soft delete (tombstone); sólo el autor puede borrar.
+// @Tags tweets
+// @Security ApiKeyAuth
+// @Param id path string true "tweet id"
+// @Success 204 {string} string ""
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /v1/tweets/{id} [delete]
+func (h TweetHandler) Delete(c *gin.Context) {
+	if err := h.DeleteTweet.Exec(c, usecase.DeleteTweetInput{TweetID: c.Param("id"), UserID: callerID(c)}); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
 // @Summary Timeline
+// @Description Paginado por cursor: usar `paging.next_cursor` (más viejos) o `paging.prev_cursor` (más nuevos) como `cursor`. `offset` está deprecado.
 // @Tags tweets
 // @Produce json
+// @Security ApiKeyAuth
 // @Param userID path string true "user id"
 // @Param limit query int false "limit"
-// @Param offset query int false "offset"
+// @Param cursor query string false "opaque cursor"
+// @Param offset query int false "offset (deprecated)"
+// @Param include_self query bool false "incluir tweets propios"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Router /v1/timeline/{userID} [get]
 func (h TweetHandler) Timeline(c *gin.Context) {
-	userID := c.Param("userID")
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
-	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	limit, cursor, offset := pageParams(c)
+	includeSelf, _ := strconv.ParseBool(c.DefaultQuery("include_self", "false"))
 
-	list, err := h.GetTimeline.Exec(c, usecase.GetTimelineInput{
-		UserID: userID, Limit: limit, Offset: offset,
+	page, err := h.GetTimeline.Exec(c, usecase.GetTimelineInput{
+		UserID: c.Param("userID"), ActorID: callerID(c), Limit: limit, Cursor: cursor, Offset: offset, IncludeSelf: includeSelf,
 	})
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
-		return
-	}
-	c.JSON(200, gin.H{"data": list})
+	writePage(c, page, err)
+}
+
+// @Summary User tweets
+// @Description Tweets propios de un usuario, con la misma paginación que el timeline.
+// @Tags tweets
+// @Produce json
+// @Param userID path string true "user id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Param offset query int false "offset (deprecated)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/users/{userID}/tweets [get]
+func (h TweetHandler) UserTweets(c *gin.Context) {
+	limit, cursor, offset := pageParams(c)
+	page, err := h.GetUserTweets.Exec(c, usecase.GetUserTweetsInput{
+		UserID: c.Param("userID"), Limit: limit, Cursor: cursor, Offset: offset, ViewerID: callerID(c),
+	})
+	writePage(c, page, err)
+}
+
+// @Summary Hashtag tweets
+// @Description Tweets con el hashtag (sin distinguir mayúsculas; `#` opcional), más nuevos primero; paginado por cursor.
+// @Tags tweets
+// @Produce json
+// @Param tag path string true "hashtag"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/hashtags/{tag}/tweets [get]
+func (h TweetHandler) ByHashtag(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.HashtagTweets.Exec(c, usecase.GetHashtagTweetsInput{
+		Tag: c.Param("tag"), Limit: limit, Cursor: cursor, ViewerID: callerID(c),
+	})
+	writePage(c, page, err)
+}
+
+// @Summary User mentions
+// @Description Tweets que mencionan al usuario, más nuevos primero; paginado por cursor.
+// @Tags tweets
+// @Produce json
+// @Param userID path string true "user id"
+// @Param limit query int false "limit"
+// @Param cursor query string false "opaque cursor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /v1/users/{userID}/mentions [get]
+func (h TweetHandler) Mentions(c *gin.Context) {
+	limit, cursor, _ := pageParams(c)
+	page, err := h.UserMentions.Exec(c, usecase.GetUserMentionsInput{
+		UserID: c.Param("userID"), Limit: limit, Cursor: cursor, ViewerID: callerID(c),
+	})
+	writePage(c, page, err)
 }
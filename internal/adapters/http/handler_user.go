@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+
+	"tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserHandler struct {
+	CreateUser usecase.CreateUser
+	GetUser    usecase.GetUser
+	UpdateUser usecase.UpdateUser
+}
+
+type CreateUserReq struct {
+	Handle      string `json:"handle" binding:"required"`
+	DisplayName string `json:"display_name"` // default: el handle
+	Bio         string `json:"bio"`
+}
+
+// UpdateUserReq: los campos ausentes no se modifican.
+type UpdateUserReq struct {
+	Handle      *string `json:"handle"`
+	DisplayName *string `json:"display_name"`
+	Bio         *string `json:"bio"`
+	Protected   *bool   `json:"protected"`
+}
+
+// @Summary Create user
+// @Description El handle (1-15 letras, dígitos o `_`) es único sin distinguir mayúsculas y es el que se usa en las menciones. La respuesta incluye en `api_key.token` la primera API key del usuario, que no vuelve a mostrarse.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param payload body CreateUserReq true "payload"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/users [post]
+func (h UserHandler) Create(c *gin.Context) {
+	var req CreateUserReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	u, key, err := h.CreateUser.Exec(c, usecase.CreateUserInput{Handle: req.Handle, DisplayName: req.DisplayName, Bio: req.Bio})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"data": u, "api_key": key})
+}
+
+// @Summary Get user
+// @Tags users
+// @Produce json
+// @Param userID path string true "user id"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Router /v1/users/{userID} [get]
+func (h UserHandler) Get(c *gin.Context) {
+	u, err := h.GetUser.Exec(c, c.Param("userID"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": u})
+}
+
+// @Summary Update user
+// @Description Cambia handle, display name, bio y/o `protected`; los campos ausentes quedan igual. Con `protected` los follows nuevos quedan pendientes de aprobación y los tweets sólo los ven los seguidores. Sólo el propio usuario.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param userID path string true "user id"
+// @Param payload body UpdateUserReq true "payload"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /v1/users/{userID} [patch]
+func (h UserHandler) Update(c *gin.Context) {
+	var req UpdateUserReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	u, err := h.UpdateUser.Exec(c, usecase.UpdateUserInput{
+		UserID:  c.Param("userID"),
+		ActorID: callerID(c),
+		Patch: domain.UserPatch{
+			Handle: req.Handle, DisplayName: req.DisplayName, Bio: req.Bio, Protected: req.Protected,
+		},
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": u})
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type Paging struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// pageJSON es el envelope común de listados paginados: {"data": [...], "paging": {...}}.
+func pageJSON[T any](p usecase.Page[T]) gin.H {
+	return gin.H{"data": p.Items, "paging": Paging{NextCursor: p.NextCursor, PrevCursor: p.PrevCursor}}
+}
+
+// pageParams lee limit/cursor/offset; offset está deprecado y se avisa por header.
+func pageParams(c *gin.Context) (limit int, cursor string, offset int) {
+	limit, _ = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(ports.DefaultPageLimit)))
+	offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if _, ok := c.GetQuery("offset"); ok {
+		c.Header("Deprecation", "true")
+	}
+	return limit, c.Query("cursor"), offset
+}
+
+func writePage[T any](c *gin.Context, p usecase.Page[T], err error) {
+	if errors.Is(err, domain.ErrInvalidCursor) || errors.Is(err, domain.ErrInvalidHashtag) ||
+		errors.Is(err, domain.ErrInvalidSearchQuery) || errors.Is(err, domain.ErrTweetNotFound) ||
+		errors.Is(err, domain.ErrUserNotFound) || errors.Is(err, domain.ErrForbidden) {
+		writeError(c, err)
+		return
+	}
+	if err != nil {
+		c.JSON(500, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(200, pageJSON(p))
+}
@@ -1,69 +1,27 @@
 package http
 
 import (
-	"os"
-	"strconv"
-	"sync"
-	"time"
-)
-
-type RateLimiter struct {
-	enabled bool
-	window  time.Duration
-	max     int
-	mu      sync.Mutex
-	buckets map[string]*bucket
-}
+	"net/http"
 
-type bucket struct {
-	count int
-	reset time.Time
-}
+	"github.com/gin-gonic/gin"
 
-func NewRateLimiterFromEnv() *RateLimiter {
-	enabled := true
-	if v := os.Getenv("RATE_LIMIT_ENABLED"); v != "" {
-		if v == "0" || v == "false" || v == "False" {
-			enabled = false
-		}
-	}
-	win := 60
-	if v := os.Getenv("RATE_LIMIT_WINDOW_SEC"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			win = n
-		}
-	}
-	max := 20
-	if v := os.Getenv("RATE_LIMIT_MAX_TWEETS"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			max = n
-		}
-	}
-	return &RateLimiter{
-		enabled: enabled,
-		window:  time.Duration(win) * time.Second,
-		max:     max,
-		buckets: make(map[string]*bucket),
-	}
-}
+	"tweetschallenge/internal/ports"
+)
 
-func (rl *RateLimiter) Allow(key string) bool {
-	if rl == nil || !rl.enabled {
-		return true
+// rateLimited aplica el rate limit por usuario autenticado y, si se excede,
+// responde 429. Sin limiter (deshabilitado) deja pasar todo; si el limiter
+// falla (p. ej. Redis caído) también deja pasar y el error queda en el log.
+func rateLimited(c *gin.Context, limiter ports.RateLimiter) bool {
+	if limiter == nil {
+		return false
 	}
-	now := time.Now()
-
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	b, ok := rl.buckets[key]
-	if !ok || now.After(b.reset) {
-		rl.buckets[key] = &bucket{count: 1, reset: now.Add(rl.window)}
-		return true
+	ok, err := limiter.Allow(c, callerID(c))
+	if err != nil {
+		_ = c.Error(err)
+		return false
 	}
-	if b.count < rl.max {
-		b.count++
-		return true
+	if !ok {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
 	}
-	return false
+	return !ok
 }
@@ -6,11 +6,23 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"tweetschallenge/internal/application/usecase"
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
 )
 
 type Handlers struct {
-	Tweet  TweetHandler
-	Follow FollowHandler
+	Tweet         TweetHandler
+	User          UserHandler
+	Follow        FollowHandler
+	Block         BlockHandler
+	Mute          MuteHandler
+	FollowRequest FollowRequestHandler
+	Like          LikeHandler
+	Search        SearchHandler
+	APIKey        APIKeyHandler
+	Token         TokenHandler
+	Admin         AdminHandler
+	Auth          usecase.Authenticate
 }
 
 func NewRouter(h Handlers) *gin.Engine {
@@ -18,29 +30,182 @@ func NewRouter(h Handlers) *gin.Engine {
 
 	r.GET("/healthz", func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) })
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/.well-known/jwks.json", h.Token.JWKS)
 
 	api := r.Group("/v1")
 	{
+		// Lecturas y alta de usuario: públicas. Los listados de tweets aceptan
+		// credencial (opcional) para mostrar las cuentas protegidas que se siguen.
+		viewer := OptionalAuth(h.Auth)
+		api.GET("/tweets/:id/revisions", viewer, h.Tweet.ListRevisions)
+		api.GET("/tweets/:id/thread", viewer, h.Tweet.Thread)
+		api.GET("/users/:userID/tweets", viewer, h.Tweet.UserTweets)
+		api.GET("/users/:userID/mentions", viewer, h.Tweet.Mentions)
+		api.GET("/hashtags/:tag/tweets", viewer, h.Tweet.ByHashtag)
+		api.GET("/search", viewer, h.Search.Search)
+		api.POST("/users", h.User.Create)
+		api.GET("/users/:userID", h.User.Get)
+		api.GET("/tweets/:id/likes", viewer, h.Like.ListByTweet)
+		api.GET("/users/:userID/likes", viewer, h.Like.ListByUser)
+		api.GET("/users/:userID/followers", h.Follow.ListFollowers)
+		api.GET("/users/:userID/following", h.Follow.ListFollowing)
+		api.POST("/auth/refresh", h.Token.RefreshPair)
+	}
+
+	// Escrituras y timeline: el usuario que actúa sale de la credencial (API
+	// key o JWT), nunca del payload. Los JWT además necesitan el scope.
+	authed := api.Group("", RequireAuth(h.Auth))
+	{
+		tweets := RequireScope(domain.ScopeTweetsWrite)
+		follows := RequireScope(domain.ScopeFollowsWrite)
+		graph := RequireScope(domain.ScopeFollowsRead)
+
 		// Tweets
-		api.POST("/tweets", h.Tweet.Create)
-		api.GET("/timeline/:userID", h.Tweet.Timeline)
+		authed.POST("/tweets", tweets, h.Tweet.Create)
+		authed.PATCH("/tweets/:id", tweets, h.Tweet.Edit)
+		authed.DELETE("/tweets/:id", tweets, h.Tweet.Delete)
+		authed.POST("/tweets/:id/retweets", tweets, h.Tweet.CreateRetweet)
+		authed.DELETE("/tweets/:id/retweets", tweets, h.Tweet.DeleteRetweet)
+		authed.GET("/timeline/:userID", RequireScope(domain.ScopeTimelineRead), h.Tweet.Timeline)
+
+		// Users
+		authed.PATCH("/users/:userID", RequireScope(domain.ScopeProfileWrite), h.User.Update)
+
+		// Likes
+		authed.POST("/tweets/:id/likes", tweets, h.Like.Create)
+		authed.DELETE("/tweets/:id/likes", tweets, h.Like.Delete)
 
 		// Follows (solo follow/unfollow)
-		api.POST("/follows", h.Follow.Create)
-		api.DELETE("/follows", h.Follow.Delete)
+		authed.POST("/follows", follows, h.Follow.Create)
+		authed.DELETE("/follows", follows, h.Follow.Delete)
+
+		// Solicitudes hacia la cuenta protegida del propio usuario
+		authed.GET("/follow-requests", graph, h.FollowRequest.ListRequests)
+		authed.POST("/follow-requests/:userID/approve", follows, h.FollowRequest.ApproveRequest)
+		authed.POST("/follow-requests/:userID/reject", follows, h.FollowRequest.RejectRequest)
+
+		// Blocks: el listado sólo lo ve el propio usuario
+		authed.POST("/blocks", follows, h.Block.Create)
+		authed.DELETE("/blocks", follows, h.Block.Delete)
+		authed.GET("/users/:userID/blocks", graph, h.Block.List)
+
+		// Mutes: sólo filtran el timeline del propio usuario
+		authed.POST("/mutes", follows, h.Mute.Create)
+		authed.GET("/mutes", graph, h.Mute.ListMutes)
+		authed.DELETE("/mutes/:id", follows, h.Mute.Delete)
+	}
+
+	// Credenciales: sólo con API key
+	creds := api.Group("", RequireAuth(h.Auth), RequireAPIKey())
+	{
+		creds.POST("/auth/token", h.Token.Create)
+		creds.POST("/api-keys", h.APIKey.Create)
+		creds.GET("/api-keys", h.APIKey.ListKeys)
+		creds.POST("/api-keys/:id/rotate", h.APIKey.RotateKey)
+		creds.DELETE("/api-keys/:id", h.APIKey.RevokeKey)
+	}
+
+	// Moderación: API key de un usuario con rol admin. Los JWT no llevan un
+	// scope de moderación, así que uno acotado no alcanza.
+	admin := api.Group("/admin", RequireAuth(h.Auth), RequireAPIKey(), RequireAdmin(h.Admin.GetUser))
+	{
+		admin.GET("/users/:userID/tweets", h.Admin.Tweets)
+		admin.GET("/users/:userID/follows", h.Admin.Follows)
+		admin.POST("/users/:userID/suspension", h.Admin.SuspendUser)
+		admin.DELETE("/users/:userID/suspension", h.Admin.UnsuspendUser)
+		admin.DELETE("/users/:userID/rate-limit", h.Admin.ResetRateLimit)
+		admin.DELETE("/tweets/:id", h.Admin.HardDelete)
 	}
 	return r
 }
 
-func BuildHandlers(
-	postTweet usecase.PostTweet,
-	getTimeline usecase.GetTimeline,
-	followUser usecase.FollowUser,
-	unfollowUser usecase.UnfollowUser,
-	limiter *RateLimiter,
-) Handlers {
+// Deps son los casos de uso y puertos que arman los handlers. Los campos
+// tienen nombre para que el wiring no dependa del orden.
+type Deps struct {
+	// Tweets
+	PostTweet     usecase.PostTweet
+	EditTweet     usecase.EditTweet
+	DeleteTweet   usecase.DeleteTweet
+	Revisions     usecase.GetTweetRevisions
+	GetThread     usecase.GetThread
+	Retweet       usecase.Retweet
+	Unretweet     usecase.Unretweet
+	GetTimeline   usecase.GetTimeline
+	GetUserTweets usecase.GetUserTweets
+	HashtagTweets usecase.GetHashtagTweets
+	UserMentions  usecase.GetUserMentions
+	SearchTweets  usecase.SearchTweets
+
+	// Usuarios y grafo social
+	CreateUser           usecase.CreateUser
+	GetUser              usecase.GetUser
+	UpdateUser           usecase.UpdateUser
+	FollowUser           usecase.FollowUser
+	UnfollowUser         usecase.UnfollowUser
+	Followers            usecase.GetFollowers
+	Following            usecase.GetFollowing
+	BlockUser            usecase.BlockUser
+	UnblockUser          usecase.UnblockUser
+	GetBlocks            usecase.GetBlocks
+	MuteUser             usecase.MuteUser
+	UnmuteUser           usecase.UnmuteUser
+	ListMutes            usecase.ListMutes
+	ListFollowRequests   usecase.ListFollowRequests
+	ApproveFollowRequest usecase.ApproveFollowRequest
+	RejectFollowRequest  usecase.RejectFollowRequest
+
+	// Likes
+	LikeTweet   usecase.LikeTweet
+	UnlikeTweet usecase.UnlikeTweet
+	TweetLikes  usecase.GetTweetLikes
+	UserLikes   usecase.GetUserLikes
+
+	// Credenciales
+	IssueKey      usecase.IssueAPIKey
+	ListKeys      usecase.ListAPIKeys
+	RotateKey     usecase.RotateAPIKey
+	RevokeKey     usecase.RevokeAPIKey
+	IssueTokens   usecase.IssueTokens
+	RefreshTokens usecase.RefreshTokens
+	AccessTokens  ports.AccessTokens // JWKS
+	Auth          usecase.Authenticate
+
+	// Moderación
+	AdminUserTweets usecase.AdminUserTweets
+	HardDelete      usecase.HardDeleteTweet
+	SuspendUser     usecase.SuspendUser
+	UserFollows     usecase.GetUserFollows
+
+	Limiter ports.RateLimiter
+}
+
+func BuildHandlers(d Deps) Handlers {
 	return Handlers{
-		Tweet:  TweetHandler{PostTweet: postTweet, GetTimeline: getTimeline, Limiter: limiter},
-		Follow: FollowHandler{FollowUser: followUser, UnfollowUser: unfollowUser},
+		Tweet: TweetHandler{
+			PostTweet: d.PostTweet, EditTweet: d.EditTweet, DeleteTweet: d.DeleteTweet, Revisions: d.Revisions, GetThread: d.GetThread,
+			Retweet: d.Retweet, Unretweet: d.Unretweet,
+			GetTimeline: d.GetTimeline, GetUserTweets: d.GetUserTweets, HashtagTweets: d.HashtagTweets,
+			UserMentions: d.UserMentions, Limiter: d.Limiter,
+		},
+		User: UserHandler{CreateUser: d.CreateUser, GetUser: d.GetUser, UpdateUser: d.UpdateUser},
+		Follow: FollowHandler{
+			FollowUser: d.FollowUser, UnfollowUser: d.UnfollowUser, Followers: d.Followers, Following: d.Following,
+		},
+		Block: BlockHandler{BlockUser: d.BlockUser, UnblockUser: d.UnblockUser, Blocks: d.GetBlocks},
+		Mute:  MuteHandler{Mute: d.MuteUser, Unmute: d.UnmuteUser, List: d.ListMutes},
+		FollowRequest: FollowRequestHandler{
+			List: d.ListFollowRequests, Approve: d.ApproveFollowRequest, Reject: d.RejectFollowRequest,
+		},
+		Like: LikeHandler{
+			LikeTweet: d.LikeTweet, UnlikeTweet: d.UnlikeTweet, TweetLikes: d.TweetLikes, UserLikes: d.UserLikes,
+		},
+		Search: SearchHandler{SearchTweets: d.SearchTweets},
+		APIKey: APIKeyHandler{Issue: d.IssueKey, List: d.ListKeys, Rotate: d.RotateKey, Revoke: d.RevokeKey},
+		Token:  TokenHandler{Issue: d.IssueTokens, Refresh: d.RefreshTokens, Keys: d.AccessTokens},
+		Admin: AdminHandler{
+			GetUser: d.GetUser, UserTweets: d.AdminUserTweets, UserFollows: d.UserFollows,
+			DeleteTweet: d.HardDelete, Suspend: d.SuspendUser, Limiter: d.Limiter,
+		},
+		Auth: d.Auth,
 	}
 }
@@ -0,0 +1,17 @@
+package id
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+)
+
+type Secret struct{}
+
+// NewSecret devuelve 32 bytes de crypto/rand en base64 URL-safe (43 caracteres).
+func (Secret) NewSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic("crypto/rand: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
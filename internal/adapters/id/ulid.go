@@ -1,15 +1,14 @@
 package id
 
 import (
-	"crypto/rand"
-	"time"
-
 	"github.com/oklog/ulid/v2"
 )
 
 type ULID struct{}
 
+// NewID usa la entropía monotónica global de ulid.Make: dentro del mismo
+// milisegundo los ids siguen ordenados, y el keyset (created_at, id) respeta
+// el orden de creación.
 func (ULID) NewID() string {
-	entropy := ulid.Monotonic(rand.Reader, 0)
-	return ulid.MustNew(ulid.Timestamp(time.Now()), entropy).String()
+	return ulid.Make().String()
 }
@@ -0,0 +1,15 @@
+package id
+
+import "testing"
+
+func TestULID_StrictlyIncreasing(t *testing.T) {
+	var gen ULID
+	prev := gen.NewID()
+	for i := 0; i < 10000; i++ {
+		next := gen.NewID()
+		if next <= prev {
+			t.Fatalf("id %d not increasing: %s <= %s", i, next, prev)
+		}
+		prev = next
+	}
+}
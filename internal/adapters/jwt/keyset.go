@@ -0,0 +1,256 @@
+// Package jwt firma y verifica access tokens con claves locales: HS256
+// (secreto compartido) y EdDSA (Ed25519, publicada en el JWKS).
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgEdDSA = "EdDSA"
+
+	minHMACSecret = 32
+)
+
+// Key es una clave de firma identificada por kid (el nombre del archivo).
+type Key struct {
+	ID     string
+	Alg    string
+	secret []byte             // HS256
+	priv   ed25519.PrivateKey // EdDSA
+}
+
+func NewHS256Key(kid string, secret []byte) (Key, error) {
+	if len(secret) < minHMACSecret {
+		return Key{}, fmt.Errorf("jwt: key %s: HS256 secret must be at least %d bytes", kid, minHMACSecret)
+	}
+	return Key{ID: kid, Alg: AlgHS256, secret: secret}, nil
+}
+
+func NewEdDSAKey(kid string, priv ed25519.PrivateKey) Key {
+	return Key{ID: kid, Alg: AlgEdDSA, priv: priv}
+}
+
+func (k Key) signingKey() any {
+	if k.Alg == AlgHS256 {
+		return k.secret
+	}
+	return k.priv
+}
+
+func (k Key) verifyKey() any {
+	if k.Alg == AlgHS256 {
+		return k.secret
+	}
+	return k.priv.Public()
+}
+
+// KeySet implementa ports.AccessTokens. Firma con la clave activa y verifica
+// con cualquiera del set: para rotar se agrega la clave nueva, se la activa y
+// la anterior se retira recién cuando vencieron los tokens que firmó.
+type KeySet struct {
+	keys   map[string]Key
+	active string
+	issuer string
+	clock  ports.Clock
+}
+
+func NewKeySet(issuer, active string, clock ports.Clock, keys ...Key) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]Key{}, active: active, issuer: issuer, clock: clock}
+	for _, k := range keys {
+		if _, dup := ks.keys[k.ID]; dup {
+			return nil, fmt.Errorf("jwt: duplicate kid %q", k.ID)
+		}
+		ks.keys[k.ID] = k
+	}
+	if _, ok := ks.keys[active]; !ok {
+		return nil, fmt.Errorf("jwt: active kid %q not found", active)
+	}
+	return ks, nil
+}
+
+// Config: JWT_KEYS_DIR, JWT_ACTIVE_KID, JWT_ISSUER.
+type Config struct {
+	KeysDir   string
+	ActiveKID string
+	Issuer    string
+}
+
+func ConfigFromEnv() Config {
+	cfg := Config{
+		KeysDir:   os.Getenv("JWT_KEYS_DIR"),
+		ActiveKID: os.Getenv("JWT_ACTIVE_KID"),
+		Issuer:    os.Getenv("JWT_ISSUER"),
+	}
+	if cfg.Issuer == "" {
+		cfg.Issuer = "tweetschallenge"
+	}
+	return cfg
+}
+
+// Open carga las claves de KeysDir. Sin KeysDir genera una Ed25519 efímera
+// (los tokens dejan de valer al reiniciar, igual que la DB en memoria).
+func Open(cfg Config, clock ports.Clock) (*KeySet, error) {
+	if cfg.KeysDir == "" {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return NewKeySet(cfg.Issuer, "ephemeral", clock, NewEdDSAKey("ephemeral", priv))
+	}
+	keys, err := LoadDir(cfg.KeysDir)
+	if err != nil {
+		return nil, err
+	}
+	active := cfg.ActiveKID
+	if active == "" {
+		// por defecto la de kid mayor: con kids fechados (2025-08, 2025-11…) es la más nueva
+		active = keys[len(keys)-1].ID
+	}
+	return NewKeySet(cfg.Issuer, active, clock, keys...)
+}
+
+// LoadDir lee `<kid>.pem` (clave privada Ed25519 PKCS#8) y `<kid>.hs256`
+// (secreto de al menos 32 bytes) ordenadas por kid.
+func LoadDir(dir string) ([]Key, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: %w", err)
+	}
+	var keys []Key
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		kid := strings.TrimSuffix(e.Name(), ext)
+		if e.IsDir() || (ext != ".pem" && ext != ".hs256") {
+			continue
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("jwt: %w", err)
+		}
+		var k Key
+		if ext == ".hs256" {
+			k, err = NewHS256Key(kid, []byte(strings.TrimSpace(string(raw))))
+		} else {
+			k, err = parseEdDSAPEM(kid, raw)
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("jwt: no keys (*.pem, *.hs256) in %s", dir)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+func parseEdDSAPEM(kid string, raw []byte) (Key, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return Key{}, fmt.Errorf("jwt: key %s: not PEM", kid)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("jwt: key %s: %w", kid, err)
+	}
+	priv, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return Key{}, fmt.Errorf("jwt: key %s: not an Ed25519 key", kid)
+	}
+	return NewEdDSAKey(kid, priv), nil
+}
+
+// MarshalEdDSAPEM es el formato que espera LoadDir (ver `server jwt genkey`).
+func MarshalEdDSAPEM(priv ed25519.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+type claims struct {
+	Scope string `json:"scope,omitempty"`
+	gojwt.RegisteredClaims
+}
+
+func (ks *KeySet) Sign(c domain.AccessClaims) (string, error) {
+	k := ks.keys[ks.active]
+	tok := gojwt.NewWithClaims(gojwt.GetSigningMethod(k.Alg), claims{
+		Scope: domain.JoinScopes(c.Scopes),
+		RegisteredClaims: gojwt.RegisteredClaims{
+			Issuer:    ks.issuer,
+			Subject:   c.UserID,
+			IssuedAt:  gojwt.NewNumericDate(time.Unix(c.IssuedAt, 0)),
+			ExpiresAt: gojwt.NewNumericDate(time.Unix(c.ExpiresAt, 0)),
+		},
+	})
+	tok.Header["kid"] = k.ID
+	return tok.SignedString(k.signingKey())
+}
+
+func (ks *KeySet) Verify(token string) (domain.AccessClaims, error) {
+	var cl claims
+	_, err := gojwt.ParseWithClaims(token, &cl, ks.keyFunc,
+		gojwt.WithValidMethods([]string{AlgHS256, AlgEdDSA}),
+		gojwt.WithIssuer(ks.issuer),
+		gojwt.WithExpirationRequired(),
+		gojwt.WithTimeFunc(func() time.Time { return time.Unix(ks.clock.NowUnix(), 0) }),
+	)
+	if err != nil || cl.Subject == "" {
+		return domain.AccessClaims{}, domain.ErrUnauthenticated
+	}
+	out := domain.AccessClaims{UserID: cl.Subject, Scopes: domain.SplitScopes(cl.Scope), ExpiresAt: cl.ExpiresAt.Unix()}
+	if cl.IssuedAt != nil {
+		out.IssuedAt = cl.IssuedAt.Unix()
+	}
+	return out, nil
+}
+
+// keyFunc elige la clave por kid y exige que el alg del header sea el de esa
+// clave: un token HS256 firmado con la clave pública Ed25519 no pasa.
+func (ks *KeySet) keyFunc(t *gojwt.Token) (any, error) {
+	kid, _ := t.Header["kid"].(string)
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, errors.New("unknown kid")
+	}
+	if t.Method.Alg() != k.Alg {
+		return nil, errors.New("alg does not match key")
+	}
+	return k.verifyKey(), nil
+}
+
+func (ks *KeySet) PublicKeys() []ports.JWK {
+	out := []ports.JWK{}
+	for _, k := range ks.keys {
+		if k.Alg != AlgEdDSA {
+			continue
+		}
+		out = append(out, ports.JWK{
+			Kty: "OKP", Crv: "Ed25519", Kid: k.ID, Alg: AlgEdDSA, Use: "sig",
+			X: base64.RawURLEncoding.EncodeToString(k.priv.Public().(ed25519.PublicKey)),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kid < out[j].Kid })
+	return out
+}
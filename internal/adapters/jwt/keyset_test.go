@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+
+	"tweetschallenge/internal/domain"
+)
+
+type fixedClock struct{ now int64 }
+
+func (c fixedClock) NowUnix() int64 { return c.now }
+
+func edKey(t *testing.T, kid string) Key {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewEdDSAKey(kid, priv)
+}
+
+func TestKeySet_SignVerifyAndRotation(t *testing.T) {
+	hs, err := NewHS256Key("hs-1", []byte(strings.Repeat("s", 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, cur := edKey(t, "ed-1"), edKey(t, "ed-2")
+	clock := &fixedClock{now: 100}
+	claims := domain.AccessClaims{UserID: "u1", Scopes: []string{domain.ScopeTweetsWrite}, IssuedAt: 100, ExpiresAt: 160}
+
+	for _, active := range []string{"hs-1", "ed-1"} {
+		ks, err := NewKeySet("test", active, clock, hs, old)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tok, err := ks.Sign(claims)
+		if err != nil {
+			t.Fatalf("%s: sign: %v", active, err)
+		}
+		got, err := ks.Verify(tok)
+		if err != nil || got.UserID != "u1" || len(got.Scopes) != 1 || got.ExpiresAt != 160 {
+			t.Fatalf("%s: verify = %+v err=%v", active, got, err)
+		}
+	}
+
+	// rotación: ed-2 firma, ed-1 sigue verificando hasta que se retire
+	before, _ := NewKeySet("test", "ed-1", clock, old)
+	tokOld, _ := before.Sign(claims)
+	after, _ := NewKeySet("test", "ed-2", clock, old, cur)
+	if _, err := after.Verify(tokOld); err != nil {
+		t.Fatalf("token of retired-but-present key must verify: %v", err)
+	}
+	retired, _ := NewKeySet("test", "ed-2", clock, cur)
+	if _, err := retired.Verify(tokOld); err != domain.ErrUnauthenticated {
+		t.Fatalf("token of removed key: want ErrUnauthenticated, got %v", err)
+	}
+	if jwks := after.PublicKeys(); len(jwks) != 2 || jwks[0].Kid != "ed-1" || jwks[1].Crv != "Ed25519" {
+		t.Fatalf("jwks = %+v", jwks)
+	}
+
+	clock.now = 160
+	if _, err := after.Verify(tokOld); err != domain.ErrUnauthenticated {
+		t.Fatalf("expired: want ErrUnauthenticated, got %v", err)
+	}
+	clock.now = 100
+	other, _ := NewKeySet("otro", "ed-1", clock, old)
+	if _, err := other.Verify(tokOld); err != domain.ErrUnauthenticated {
+		t.Fatalf("wrong issuer: want ErrUnauthenticated, got %v", err)
+	}
+}
+
+func TestKeySet_RejectsAlgConfusion(t *testing.T) {
+	ed := edKey(t, "ed-1")
+	ks, _ := NewKeySet("test", "ed-1", fixedClock{now: 100}, ed)
+	// HS256 usando la clave pública Ed25519 como secreto, con el kid de la Ed25519
+	forged := gojwt.NewWithClaims(gojwt.SigningMethodHS256, gojwt.RegisteredClaims{
+		Issuer: "test", Subject: "admin", ExpiresAt: gojwt.NewNumericDate(time.Unix(200, 0)),
+	})
+	forged.Header["kid"] = "ed-1"
+	tok, err := forged.SignedString([]byte(ed.priv.Public().(ed25519.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Verify(tok); err != domain.ErrUnauthenticated {
+		t.Fatalf("want ErrUnauthenticated, got %v", err)
+	}
+	none := gojwt.NewWithClaims(gojwt.SigningMethodNone, gojwt.RegisteredClaims{Issuer: "test", Subject: "admin"})
+	tok, _ = none.SignedString(gojwt.UnsafeAllowNoneSignatureType)
+	if _, err := ks.Verify(tok); err != domain.ErrUnauthenticated {
+		t.Fatalf("alg none: want ErrUnauthenticated, got %v", err)
+	}
+}
+
+func TestOpen_LoadsKeyDir(t *testing.T) {
+	dir := t.TempDir()
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	pemBytes, err := MarshalEdDSAPEM(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "2025-01.pem"), pemBytes, 0o600)
+	os.WriteFile(filepath.Join(dir, "2025-02.hs256"), []byte(strings.Repeat("k", 40)+"\n"), 0o600)
+	os.WriteFile(filepath.Join(dir, "README"), []byte("ignorado"), 0o600)
+
+	ks, err := Open(Config{KeysDir: dir, Issuer: "test"}, fixedClock{now: 1})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if ks.active != "2025-02" || len(ks.keys) != 2 || len(ks.PublicKeys()) != 1 {
+		t.Fatalf("unexpected key set: active=%s keys=%d", ks.active, len(ks.keys))
+	}
+	if _, err := Open(Config{KeysDir: dir, ActiveKID: "nope"}, fixedClock{}); err == nil {
+		t.Fatal("unknown active kid must fail")
+	}
+	os.WriteFile(filepath.Join(dir, "short.hs256"), []byte("corto"), 0o600)
+	if _, err := Open(Config{KeysDir: dir}, fixedClock{}); err == nil {
+		t.Fatal("short HS256 secret must fail")
+	}
+}
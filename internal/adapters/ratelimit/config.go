@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"tweetschallenge/internal/ports"
+)
+
+type Config struct {
+	Enabled  bool
+	Window   time.Duration
+	Max      int
+	RedisURL string // vacío: buckets en memoria del proceso
+}
+
+// ConfigFromEnv lee RATE_LIMIT_ENABLED, RATE_LIMIT_WINDOW_SEC,
+// RATE_LIMIT_MAX_TWEETS y REDIS_URL (redis://[:password@]host:port/db).
+func ConfigFromEnv() Config {
+	cfg := Config{Enabled: true, Window: 60 * time.Second, Max: 20, RedisURL: os.Getenv("REDIS_URL")}
+	if v := os.Getenv("RATE_LIMIT_ENABLED"); v == "0" || v == "false" || v == "False" {
+		cfg.Enabled = false
+	}
+	if n, err := strconv.Atoi(os.Getenv("RATE_LIMIT_WINDOW_SEC")); err == nil && n > 0 {
+		cfg.Window = time.Duration(n) * time.Second
+	}
+	if n, err := strconv.Atoi(os.Getenv("RATE_LIMIT_MAX_TWEETS")); err == nil && n > 0 {
+		cfg.Max = n
+	}
+	return cfg
+}
+
+// Open arma el limiter configurado; deshabilitado devuelve nil (sin límite).
+// Con Redis verifica la conexión al arrancar; close la libera.
+func Open(cfg Config) (ports.RateLimiter, func() error, error) {
+	noop := func() error { return nil }
+	if !cfg.Enabled {
+		return nil, noop, nil
+	}
+	if cfg.RedisURL == "" {
+		return NewMemory(cfg.Window, cfg.Max), noop, nil
+	}
+	opts, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("rate limit: %w", err)
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, nil, fmt.Errorf("rate limit: redis: %w", err)
+	}
+	return NewRedis(client, cfg.Window, cfg.Max), client.Close, nil
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory guarda los buckets en el proceso: con varias réplicas cada una
+// tiene su propio cupo (ver Redis).
+type Memory struct {
+	window  time.Duration
+	max     int
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	count int
+	reset time.Time
+}
+
+func NewMemory(window time.Duration, max int) *Memory {
+	return &Memory{window: window, max: max, buckets: make(map[string]*bucket)}
+}
+
+func (rl *Memory) Allow(ctx context.Context, key string) (bool, error) {
+	now := time.Now()
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	b, ok := rl.buckets[key]
+	if !ok || now.After(b.reset) {
+		rl.buckets[key] = &bucket{count: 1, reset: now.Add(rl.window)}
+		return true, nil
+	}
+	if b.count < rl.max {
+		b.count++
+		return true, nil
+	}
+	return false, nil
+}
+
+func (rl *Memory) Reset(ctx context.Context, key string) error {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	delete(rl.buckets, key)
+	return nil
+}
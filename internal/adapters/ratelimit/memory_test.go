@@ -1,38 +1,35 @@
-package http
+package ratelimit
 
 import (
+	"context"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 )
 
-func TestRateLimiter_WindowAndMax(t *testing.T) {
-	rl := &RateLimiter{
-		enabled: true,
-		window:  50 * time.Millisecond,
-		max:     2,
-		buckets: make(map[string]*bucket),
-	}
+func TestMemory_WindowAndMax(t *testing.T) {
+	rl := NewMemory(50*time.Millisecond, 2)
+	ctx := context.Background()
 	key := "u1"
-	if !rl.Allow(key) {
+	if ok, _ := rl.Allow(ctx, key); !ok {
 		t.Fatal("first should allow")
 	}
-	if !rl.Allow(key) {
+	if ok, _ := rl.Allow(ctx, key); !ok {
 		t.Fatal("second should allow")
 	}
-	if rl.Allow(key) {
+	if ok, _ := rl.Allow(ctx, key); ok {
 		t.Fatal("third should block")
 	}
 
 	// Esperar reinicio de ventana
 	time.Sleep(60 * time.Millisecond)
-	if !rl.Allow(key) {
+	if ok, _ := rl.Allow(ctx, key); !ok {
 		t.Fatal("should allow after window reset")
 	}
 }
-func TestRateLimiter_Concurrent(t *testing.T) {
-	rl := &RateLimiter{enabled: true, window: 50 * time.Millisecond, max: 100, buckets: make(map[string]*bucket)}
+func TestMemory_Concurrent(t *testing.T) {
+	rl := NewMemory(50*time.Millisecond, 100)
 	key := "u1"
 	const N = 200
 	var allowed int64
@@ -40,7 +37,7 @@ func TestRateLimiter_Concurrent(t *testing.T) {
 	wg.Add(N)
 	for i := 0; i < N; i++ {
 		go func() {
-			if rl.Allow(key) {
+			if ok, _ := rl.Allow(context.Background(), key); ok {
 				atomic.AddInt64(&allowed, 1)
 			}
 			wg.Done()
@@ -51,3 +48,7 @@ func TestRateLimiter_Concurrent(t *testing.T) {
 		t.Fatalf("allowed=%d exceeds max=%d", allowed, rl.max)
 	}
 }
+
+func TestMemory_Reset(t *testing.T) {
+	testReset(t, NewMemory(time.Minute, 1))
+}
@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// allowScript incrementa el contador de la ventana y, si es la primera
+// request (o la clave quedó sin TTL), le pone el vencimiento. Al correr como
+// script es atómico: dos réplicas no pueden leer el mismo valor.
+var allowScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 or redis.call('PTTL', KEYS[1]) < 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// Redis comparte los buckets entre réplicas: una clave `<prefix><key>` por
+// usuario que vence con la ventana.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+	window time.Duration
+	max    int
+}
+
+func NewRedis(client redis.UniversalClient, window time.Duration, max int) *Redis {
+	return &Redis{client: client, prefix: "ratelimit:", window: window, max: max}
+}
+
+func (rl *Redis) Allow(ctx context.Context, key string) (bool, error) {
+	n, err := allowScript.Run(ctx, rl.client, []string{rl.prefix + key}, rl.window.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return n <= int64(rl.max), nil
+}
+
+func (rl *Redis) Reset(ctx context.Context, key string) error {
+	return rl.client.Del(ctx, rl.prefix+key).Err()
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"tweetschallenge/internal/ports"
+)
+
+func newMiniredis(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, client
+}
+
+func TestRedis_WindowSharedAcrossReplicas(t *testing.T) {
+	mr, client := newMiniredis(t)
+	ctx := context.Background()
+	// dos réplicas del server contra el mismo Redis comparten el cupo
+	a, b := NewRedis(client, time.Minute, 2), NewRedis(client, time.Minute, 2)
+	for i, rl := range []*Redis{a, b} {
+		if ok, err := rl.Allow(ctx, "u1"); err != nil || !ok {
+			t.Fatalf("request %d: ok=%v err=%v", i, ok, err)
+		}
+	}
+	if ok, _ := a.Allow(ctx, "u1"); ok {
+		t.Fatal("third request across replicas should block")
+	}
+	if ttl := mr.TTL("ratelimit:u1"); ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("ttl = %v", ttl)
+	}
+
+	// seguir pidiendo no estira la ventana
+	mr.FastForward(30 * time.Second)
+	_, _ = b.Allow(ctx, "u1")
+	mr.FastForward(31 * time.Second)
+	if ok, err := b.Allow(ctx, "u1"); err != nil || !ok {
+		t.Fatalf("after window: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestRedis_Concurrent(t *testing.T) {
+	_, client := newMiniredis(t)
+	rl := NewRedis(client, time.Minute, 100)
+	const N = 200
+	var allowed int64
+	var wg sync.WaitGroup
+	wg.Add(N)
+	for i := 0; i < N; i++ {
+		go func() {
+			if ok, _ := rl.Allow(context.Background(), "u1"); ok {
+				atomic.AddInt64(&allowed, 1)
+			}
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	if allowed != 100 {
+		t.Fatalf("allowed=%d, want max=100", allowed)
+	}
+}
+
+func TestRedis_Reset(t *testing.T) {
+	_, client := newMiniredis(t)
+	testReset(t, NewRedis(client, time.Minute, 1))
+}
+
+func TestRedis_Unavailable(t *testing.T) {
+	mr, client := newMiniredis(t)
+	rl := NewRedis(client, time.Minute, 1)
+	mr.Close()
+	if _, err := rl.Allow(context.Background(), "u1"); err == nil {
+		t.Fatal("expected error with redis down")
+	}
+}
+
+// testReset corre el mismo contrato de Reset sobre ambas implementaciones (max = 1).
+func testReset(t *testing.T, rl ports.RateLimiter) {
+	t.Helper()
+	ctx := context.Background()
+	for _, key := range []string{"u1", "u2"} {
+		if ok, err := rl.Allow(ctx, key); err != nil || !ok {
+			t.Fatalf("first %s: ok=%v err=%v", key, ok, err)
+		}
+	}
+	if ok, _ := rl.Allow(ctx, "u1"); ok {
+		t.Fatal("second should block")
+	}
+	if err := rl.Reset(ctx, "u1"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if ok, _ := rl.Allow(ctx, "u1"); !ok {
+		t.Fatal("should allow after reset")
+	}
+	if ok, _ := rl.Allow(ctx, "u2"); ok {
+		t.Fatal("reset must not touch other keys")
+	}
+}
+
+var (
+	_ ports.RateLimiter = (*Memory)(nil)
+	_ ports.RateLimiter = (*Redis)(nil)
+)
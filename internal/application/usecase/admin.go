@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// Casos de uso de moderación. La autorización (rol admin) la resuelve el
+// adapter HTTP; acá no se recibe actor.
+
+// HardDeleteTweet borra un tweet sin dejar tombstone, junto con sus retweets.
+type HardDeleteTweet struct {
+	Tweets ports.TweetRepo
+	Home   ports.HomeTimelineRepo // opcional: purga timelines materializados
+}
+
+func (uc HardDeleteTweet) Exec(ctx context.Context, tweetID string) error {
+	ids, err := uc.Tweets.HardDelete(ctx, tweetID)
+	if err != nil || uc.Home == nil {
+		return err
+	}
+	for _, id := range ids {
+		if err := uc.Home.RemoveTweet(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AdminUserTweets lista los tweets de cualquier usuario, aunque esté
+// suspendido o su cuenta sea protegida: GetUserTweets sin filtro de visibilidad.
+type AdminUserTweets struct{ Tweets ports.TweetRepo }
+
+func (uc AdminUserTweets) Exec(ctx context.Context, in GetUserTweetsInput) (Page[domain.Tweet], error) {
+	return GetUserTweets{Tweets: uc.Tweets}.Exec(ctx, in)
+}
+
+// SuspendUser suspende (o reactiva) una cuenta. Suspender dos veces conserva
+// la fecha original.
+type SuspendUser struct {
+	Users ports.UserRepo
+	Clock ports.Clock
+}
+
+type SuspendUserInput struct {
+	UserID    string
+	Suspended bool
+}
+
+func (uc SuspendUser) Exec(ctx context.Context, in SuspendUserInput) (domain.User, error) {
+	u, err := uc.Users.Get(ctx, in.UserID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	switch {
+	case in.Suspended && !u.Suspended():
+		u.SuspendedAt = uc.Clock.NowUnix()
+	case !in.Suspended:
+		u.SuspendedAt = 0
+	default:
+		return u, nil
+	}
+	if err := uc.Users.SetSuspended(ctx, u.ID, u.SuspendedAt); err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+// SetUserRole asigna el rol por handle; lo usa el CLI para dar de alta admins.
+type SetUserRole struct{ Users ports.UserRepo }
+
+type SetUserRoleInput struct{ Handle, Role string }
+
+func (uc SetUserRole) Exec(ctx context.Context, in SetUserRoleInput) (domain.User, error) {
+	if !domain.ValidRole(in.Role) {
+		return domain.User{}, domain.ErrInvalidRole
+	}
+	found, err := uc.Users.ByHandles(ctx, []string{in.Handle})
+	if err != nil {
+		return domain.User{}, err
+	}
+	if len(found) == 0 {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	u := found[0]
+	if err := uc.Users.SetRole(ctx, u.ID, in.Role); err != nil {
+		return domain.User{}, err
+	}
+	u.Role = in.Role
+	return u, nil
+}
+
+// GetUserFollows pagina los follows de cualquier usuario con el mismo
+// contrato (cursor y límite) que los listados públicos de seguidores y
+// seguidos.
+type GetUserFollows struct {
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetUserFollowsInput struct {
+	FollowListInput
+	Followers bool // true: quién lo sigue; false: a quién sigue
+}
+
+func (uc GetUserFollows) Exec(ctx context.Context, in GetUserFollowsInput) (Page[domain.Follow], error) {
+	if in.Followers {
+		return GetFollowers{Users: uc.Users, Follows: uc.Follows}.Exec(ctx, in.FollowListInput)
+	}
+	return GetFollowing{Users: uc.Users, Follows: uc.Follows}.Exec(ctx, in.FollowListInput)
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// IssuedAPIKey es la única respuesta que incluye el token en claro.
+type IssuedAPIKey struct {
+	domain.APIKey
+	Token string `json:"token"`
+}
+
+type IssueAPIKey struct {
+	Keys    ports.APIKeyRepo
+	Users   ports.UserRepo
+	Clock   ports.Clock
+	IDGen   ports.IDGen
+	Secrets ports.SecretGen
+}
+
+type IssueAPIKeyInput struct{ UserID, Name string }
+
+func (uc IssueAPIKey) Exec(ctx context.Context, in IssueAPIKeyInput) (IssuedAPIKey, error) {
+	if err := requireUsers(ctx, uc.Users, in.UserID); err != nil {
+		return IssuedAPIKey{}, err
+	}
+	k, token, err := domain.NewAPIKey(uc.IDGen.NewID(), in.UserID, in.Name, uc.Secrets.NewSecret(), uc.Clock.NowUnix())
+	if err != nil {
+		return IssuedAPIKey{}, err
+	}
+	if err := uc.Keys.Create(ctx, &k); err != nil {
+		return IssuedAPIKey{}, err
+	}
+	return IssuedAPIKey{APIKey: k, Token: token}, nil
+}
+
+type ListAPIKeys struct{ Keys ports.APIKeyRepo }
+
+func (uc ListAPIKeys) Exec(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	return uc.Keys.ListByUser(ctx, userID)
+}
+
+// ownedKey trata las keys de otro usuario como inexistentes para no revelar ids.
+func ownedKey(ctx context.Context, keys ports.APIKeyRepo, userID, keyID string) (domain.APIKey, error) {
+	k, err := keys.Get(ctx, keyID)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	if k.UserID != userID {
+		return domain.APIKey{}, domain.ErrAPIKeyNotFound
+	}
+	return k, nil
+}
+
+type RevokeAPIKey struct {
+	Keys  ports.APIKeyRepo
+	Clock ports.Clock
+}
+
+type APIKeyInput struct{ UserID, KeyID string }
+
+// Exec es idempotente: revocar una key ya revocada no cambia revoked_at.
+func (uc RevokeAPIKey) Exec(ctx context.Context, in APIKeyInput) error {
+	k, err := ownedKey(ctx, uc.Keys, in.UserID, in.KeyID)
+	if err != nil || !k.Active() {
+		return err
+	}
+	return uc.Keys.Revoke(ctx, k.ID, uc.Clock.NowUnix())
+}
+
+// RotateAPIKey emite una key nueva (mismo nombre) y revoca la anterior.
+type RotateAPIKey struct {
+	Keys    ports.APIKeyRepo
+	Clock   ports.Clock
+	IDGen   ports.IDGen
+	Secrets ports.SecretGen
+}
+
+func (uc RotateAPIKey) Exec(ctx context.Context, in APIKeyInput) (IssuedAPIKey, error) {
+	old, err := ownedKey(ctx, uc.Keys, in.UserID, in.KeyID)
+	if err != nil {
+		return IssuedAPIKey{}, err
+	}
+	if !old.Active() {
+		return IssuedAPIKey{}, domain.ErrAPIKeyRevoked
+	}
+	now := uc.Clock.NowUnix()
+	k, token, err := domain.NewAPIKey(uc.IDGen.NewID(), old.UserID, old.Name, uc.Secrets.NewSecret(), now)
+	if err != nil {
+		return IssuedAPIKey{}, err
+	}
+	if err := uc.Keys.Rotate(ctx, old.ID, &k, now); err != nil {
+		return IssuedAPIKey{}, err
+	}
+	return IssuedAPIKey{APIKey: k, Token: token}, nil
+}
+
+// Authenticate resuelve una credencial (API key o access token JWT) al
+// caller. Sin Tokens sólo acepta API keys.
+type Authenticate struct {
+	Keys   ports.APIKeyRepo
+	Tokens ports.AccessTokens
+}
+
+func (uc Authenticate) Exec(ctx context.Context, token string) (domain.Principal, error) {
+	if !strings.HasPrefix(token, domain.APIKeyPrefix) {
+		if uc.Tokens == nil || token == "" {
+			return domain.Principal{}, domain.ErrUnauthenticated
+		}
+		c, err := uc.Tokens.Verify(token)
+		if err != nil {
+			return domain.Principal{}, err
+		}
+		return domain.Principal{UserID: c.UserID, Method: domain.AuthJWT, Scopes: c.Scopes}, nil
+	}
+	k, err := uc.Keys.ByHash(ctx, domain.HashAPIKey(token))
+	if errors.Is(err, domain.ErrAPIKeyNotFound) || (err == nil && !k.Active()) {
+		return domain.Principal{}, domain.ErrUnauthenticated
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+	return domain.Principal{UserID: k.UserID, Method: domain.AuthAPIKey, KeyID: k.ID}, nil
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// BlockUser bloquea a otro usuario: corta los follows en ambos sentidos (con
+// sus timelines materializados) y desde ahí FollowUser rechaza el par.
+type BlockUser struct {
+	Blocks  ports.BlockRepo
+	Follows ports.FollowRepo
+	Clock   ports.Clock
+	IDGen   ports.IDGen
+	Users   ports.UserRepo         // valida que ambos existan (opcional)
+	Home    ports.HomeTimelineRepo // opcional: purga el timeline materializado
+	// Requests (opcional) descarta también las solicitudes pendientes.
+	Requests ports.FollowRequestRepo
+}
+
+type BlockUserInput struct{ BlockerID, BlockedID string }
+
+func (uc BlockUser) Exec(ctx context.Context, in BlockUserInput) (domain.Block, error) {
+	b, err := domain.NewBlock(uc.IDGen.NewID(), in.BlockerID, in.BlockedID, uc.Clock.NowUnix())
+	if err != nil {
+		return domain.Block{}, err
+	}
+	if err := requireUsers(ctx, uc.Users, b.BlockerID, b.BlockedID); err != nil {
+		return domain.Block{}, err
+	}
+	if err := uc.Blocks.Create(ctx, &b); err != nil {
+		return domain.Block{}, err
+	}
+	// Unfollow es idempotente, así que reintentar un bloqueo no rompe nada.
+	unfollow := UnfollowUser{Follows: uc.Follows, Home: uc.Home, Requests: uc.Requests}
+	for _, pair := range [][2]string{{b.BlockerID, b.BlockedID}, {b.BlockedID, b.BlockerID}} {
+		if err := unfollow.Exec(ctx, UnfollowUserInput{FollowerID: pair[0], FolloweeID: pair[1]}); err != nil {
+			return domain.Block{}, err
+		}
+	}
+	return b, nil
+}
+
+// rejectBlocked devuelve ErrBlocked si alguno de los dos bloqueó al otro. Sin
+// repo no rechaza nada.
+func rejectBlocked(ctx context.Context, blocks ports.BlockRepo, a, b string) error {
+	if blocks == nil {
+		return nil
+	}
+	blocked, err := blocks.Between(ctx, a, b)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return domain.ErrBlocked
+	}
+	return nil
+}
+
+// UnblockUser levanta el bloqueo; los follows cortados no se restauran.
+type UnblockUser struct{ Blocks ports.BlockRepo }
+
+func (uc UnblockUser) Exec(ctx context.Context, in BlockUserInput) error {
+	return uc.Blocks.Delete(ctx, in.BlockerID, in.BlockedID)
+}
+
+// GetBlocks lista los bloqueos de un usuario, más recientes primero. Sólo el
+// propio usuario puede verlos.
+type GetBlocks struct {
+	Users  ports.UserRepo
+	Blocks ports.BlockRepo
+}
+
+type GetBlocksInput struct {
+	UserID  string
+	ActorID string
+	Limit   int
+	Cursor  string
+}
+
+func (uc GetBlocks) Exec(ctx context.Context, in GetBlocksInput) (Page[domain.Block], error) {
+	if in.ActorID != in.UserID {
+		return Page[domain.Block]{}, domain.ErrForbidden
+	}
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.Block]{}, err
+	}
+	if uc.Users != nil {
+		if _, err := uc.Users.Get(ctx, in.UserID); err != nil {
+			return Page[domain.Block]{}, err
+		}
+	}
+	p, err := uc.Blocks.Page(ctx, in.UserID, q)
+	if err != nil {
+		return Page[domain.Block]{}, err
+	}
+	return newPage(p, q, func(b domain.Block) (int64, string) { return b.CreatedAt, b.BlockedID }), nil
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type DeleteTweet struct {
+	Tweets ports.TweetRepo
+	Home   ports.HomeTimelineRepo // opcional: purga timelines materializados
+	Clock  ports.Clock
+}
+
+type DeleteTweetInput struct{ TweetID, UserID string }
+
+func (uc DeleteTweet) Exec(ctx context.Context, in DeleteTweetInput) error {
+	tw, err := uc.Tweets.Get(ctx, in.TweetID)
+	if err != nil {
+		return err
+	}
+	if tw.UserID != in.UserID {
+		return domain.ErrNotAuthor
+	}
+	if err := uc.Tweets.SoftDelete(ctx, tw.ID, uc.Clock.NowUnix()); err != nil {
+		return err
+	}
+	if uc.Home == nil {
+		return nil
+	}
+	return uc.Home.RemoveTweet(ctx, tw.ID)
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type EditTweet struct {
+	Tweets        ports.TweetRepo
+	Clock         ports.Clock
+	IDGen         ports.IDGen
+	Users         ports.UserRepo // valida menciones (opcional)
+	EditWindowSec int64          // 0 = sin límite
+}
+
+type EditTweetInput struct{ TweetID, UserID, Text string }
+
+func (uc EditTweet) Exec(ctx context.Context, in EditTweetInput) (domain.Tweet, error) {
+	tw, err := uc.Tweets.Get(ctx, in.TweetID)
+	if err != nil {
+		return domain.Tweet{}, err
+	}
+	if tw.UserID != in.UserID {
+		return domain.Tweet{}, domain.ErrNotAuthor
+	}
+	if tw.Text == domain.NormalizeText(in.Text) {
+		return tw, nil
+	}
+	updated, rev, err := tw.Edit(uc.IDGen.NewID(), in.Text, uc.Clock.NowUnix(), uc.EditWindowSec)
+	if err != nil {
+		return domain.Tweet{}, err
+	}
+	if err := resolveMentions(ctx, uc.Users, &updated); err != nil {
+		return domain.Tweet{}, err
+	}
+	if err := uc.Tweets.Edit(ctx, &updated, &rev); err != nil {
+		return domain.Tweet{}, err
+	}
+	return updated, nil
+}
+
+type GetTweetRevisions struct {
+	Tweets ports.TweetRepo
+	// Users y Follows (opcionales) ocultan las cuentas protegidas a quien no
+	// las sigue.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetTweetRevisionsInput struct {
+	TweetID  string
+	ViewerID string // "" = anónimo
+}
+
+func (uc GetTweetRevisions) Exec(ctx context.Context, in GetTweetRevisionsInput) ([]domain.TweetRevision, error) {
+	tw, err := uc.Tweets.Get(ctx, in.TweetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireVisible(ctx, uc.Users, uc.Follows, in.ViewerID, tw); err != nil {
+		return nil, err
+	}
+	return uc.Tweets.Revisions(ctx, in.TweetID)
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// ListFollowRequests lista las solicitudes pendientes hacia el usuario, más
+// recientes primero.
+type ListFollowRequests struct{ Requests ports.FollowRequestRepo }
+
+type ListFollowRequestsInput struct {
+	UserID string
+	Limit  int
+	Cursor string
+}
+
+func (uc ListFollowRequests) Exec(ctx context.Context, in ListFollowRequestsInput) (Page[domain.FollowRequest], error) {
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.FollowRequest]{}, err
+	}
+	p, err := uc.Requests.Page(ctx, in.UserID, q)
+	if err != nil {
+		return Page[domain.FollowRequest]{}, err
+	}
+	return newPage(p, q, func(r domain.FollowRequest) (int64, string) { return r.CreatedAt, r.RequesterID }), nil
+}
+
+// FollowRequestInput identifica la solicitud de RequesterID hacia TargetID;
+// sólo TargetID (el usuario autenticado) puede resolverla.
+type FollowRequestInput struct{ TargetID, RequesterID string }
+
+// ApproveFollowRequest convierte la solicitud en un follow (con su backfill)
+// y la descarta.
+type ApproveFollowRequest struct {
+	Requests ports.FollowRequestRepo
+	Follow   FollowUser
+}
+
+func (uc ApproveFollowRequest) Exec(ctx context.Context, in FollowRequestInput) (domain.Follow, error) {
+	r, err := uc.Requests.Get(ctx, in.RequesterID, in.TargetID)
+	if err != nil {
+		return domain.Follow{}, err
+	}
+	f, err := domain.NewFollow(uc.Follow.IDGen.NewID(), r.RequesterID, r.TargetID, uc.Follow.Clock.NowUnix())
+	if err != nil {
+		return domain.Follow{}, err
+	}
+	if err := uc.Follow.create(ctx, &f); err != nil {
+		return domain.Follow{}, err
+	}
+	if err := uc.Requests.Delete(ctx, r.RequesterID, r.TargetID); err != nil {
+		return domain.Follow{}, err
+	}
+	return f, nil
+}
+
+// RejectFollowRequest descarta la solicitud; el solicitante puede volver a
+// pedirla.
+type RejectFollowRequest struct{ Requests ports.FollowRequestRepo }
+
+func (uc RejectFollowRequest) Exec(ctx context.Context, in FollowRequestInput) error {
+	if _, err := uc.Requests.Get(ctx, in.RequesterID, in.TargetID); err != nil {
+		return err
+	}
+	return uc.Requests.Delete(ctx, in.RequesterID, in.TargetID)
+}
@@ -10,17 +10,91 @@ type FollowUser struct {
 	Follows ports.FollowRepo
 	Clock   ports.Clock
 	IDGen   ports.IDGen
+	Users   ports.UserRepo  // valida que ambos existan (opcional)
+	Blocks  ports.BlockRepo // opcional: rechaza pares bloqueados con ErrBlocked
+	// Requests (opcional, junto con Users) deja pendiente el follow hacia una
+	// cuenta protegida hasta que el seguido lo apruebe.
+	Requests ports.FollowRequestRepo
+
+	// Backfill del timeline materializado (opcional).
+	Tweets        ports.TweetRepo
+	Home          ports.HomeTimelineRepo
+	BackfillLimit int
 }
 
 type FollowUserInput struct{ FollowerID, FolloweeID string }
 
-func (uc FollowUser) Exec(ctx context.Context, in FollowUserInput) (domain.Follow, error) {
+// FollowResult es el follow creado o, si el seguido es protegido, la
+// solicitud que quedó esperando aprobación (Request != nil).
+type FollowResult struct {
+	domain.Follow
+	Request *domain.FollowRequest
+}
+
+func (uc FollowUser) Exec(ctx context.Context, in FollowUserInput) (FollowResult, error) {
 	f, err := domain.NewFollow(uc.IDGen.NewID(), in.FollowerID, in.FolloweeID, uc.Clock.NowUnix())
 	if err != nil {
-		return domain.Follow{}, err
+		return FollowResult{}, err
 	}
-	if err := uc.Follows.Create(ctx, &f); err != nil {
-		return domain.Follow{}, err
+	if err := requireUsers(ctx, uc.Users, f.FollowerID, f.FolloweeID); err != nil {
+		return FollowResult{}, err
+	}
+	if err := rejectBlocked(ctx, uc.Blocks, f.FollowerID, f.FolloweeID); err != nil {
+		return FollowResult{}, err
+	}
+	pending, err := uc.needsApproval(ctx, f)
+	if err != nil {
+		return FollowResult{}, err
+	}
+	if pending {
+		r := f.Request()
+		if err := uc.Requests.Create(ctx, &r); err != nil {
+			return FollowResult{}, err
+		}
+		return FollowResult{Request: &r}, nil
+	}
+	if err := uc.create(ctx, &f); err != nil {
+		return FollowResult{}, err
+	}
+	return FollowResult{Follow: f}, nil
+}
+
+// needsApproval: el seguido es protegido y el follow todavía no existe
+// (repetir un follow ya aprobado no genera otra solicitud).
+func (uc FollowUser) needsApproval(ctx context.Context, f domain.Follow) (bool, error) {
+	if uc.Requests == nil || uc.Users == nil {
+		return false, nil
+	}
+	target, err := uc.Users.Get(ctx, f.FolloweeID)
+	if err != nil || !target.Protected {
+		return false, err
+	}
+	exists, err := uc.Follows.Exists(ctx, f.FollowerID, f.FolloweeID)
+	return !exists, err
+}
+
+// create persiste el follow y hace el backfill; también lo usa la aprobación
+// de solicitudes.
+func (uc FollowUser) create(ctx context.Context, f *domain.Follow) error {
+	if err := uc.Follows.Create(ctx, f); err != nil {
+		return err
+	}
+	return uc.backfill(ctx, *f)
+}
+
+// backfill copia los tweets recientes del seguido al timeline del seguidor
+// (salvo autores heavy, que igual se leen con fan-out on read).
+func (uc FollowUser) backfill(ctx context.Context, f domain.Follow) error {
+	if uc.Home == nil || uc.Tweets == nil {
+		return nil
+	}
+	heavy, err := uc.Home.IsHeavy(ctx, f.FolloweeID)
+	if err != nil || heavy {
+		return err
+	}
+	recent, err := uc.Tweets.Timeline(ctx, f.FolloweeID, uc.BackfillLimit, 0)
+	if err != nil {
+		return err
 	}
-	return f, nil
+	return uc.Home.Backfill(ctx, f.FollowerID, recent)
 }
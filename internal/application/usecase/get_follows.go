@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// GetFollowers lista quién sigue a un usuario; GetFollowing, a quién sigue.
+// Ambos paginan por fecha del follow, los más recientes primero.
+type GetFollowers struct {
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetFollowing struct {
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type FollowListInput struct {
+	UserID string
+	Limit  int
+	Cursor string
+}
+
+func (uc GetFollowers) Exec(ctx context.Context, in FollowListInput) (Page[domain.Follow], error) {
+	return followPage(ctx, uc.Users, in, uc.Follows.FollowersPage,
+		func(f domain.Follow) (int64, string) { return f.CreatedAt, f.FollowerID })
+}
+
+func (uc GetFollowing) Exec(ctx context.Context, in FollowListInput) (Page[domain.Follow], error) {
+	return followPage(ctx, uc.Users, in, uc.Follows.FollowingPage,
+		func(f domain.Follow) (int64, string) { return f.CreatedAt, f.FolloweeID })
+}
+
+func followPage(
+	ctx context.Context, users ports.UserRepo, in FollowListInput,
+	list func(context.Context, string, ports.PageQuery) (ports.Page[domain.Follow], error),
+	key func(domain.Follow) (int64, string),
+) (Page[domain.Follow], error) {
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.Follow]{}, err
+	}
+	if users != nil {
+		if _, err := users.Get(ctx, in.UserID); err != nil {
+			return Page[domain.Follow]{}, err
+		}
+	}
+	p, err := list(ctx, in.UserID, q)
+	if err != nil {
+		return Page[domain.Follow]{}, err
+	}
+	return newPage(p, q, key), nil
+}
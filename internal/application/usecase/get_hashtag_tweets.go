@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// GetHashtagTweets lista los tweets con un hashtag, los más nuevos primero.
+type GetHashtagTweets struct {
+	Tweets ports.TweetRepo
+	// Users y Follows (opcionales) ocultan las cuentas protegidas a quien no
+	// las sigue.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetHashtagTweetsInput struct {
+	Tag      string // con o sin #; se normaliza igual que al indexar
+	Limit    int
+	Cursor   string
+	ViewerID string // "" = anónimo
+}
+
+func (uc GetHashtagTweets) Exec(ctx context.Context, in GetHashtagTweetsInput) (Page[domain.Tweet], error) {
+	tag, err := domain.NormalizeHashtag(in.Tag)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	p, err := uc.Tweets.HashtagTimelinePage(ctx, tag, q)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	out := newPage(p, q, tweetKey)
+	out.Items, err = visibleTweets(ctx, uc.Users, uc.Follows, in.ViewerID, out.Items)
+	return out, err
+}
@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type GetThread struct {
+	Tweets ports.TweetRepo
+	// Users y Follows (opcionales) ocultan las cuentas protegidas a quien no
+	// las sigue.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetThreadInput struct {
+	TweetID  string
+	Limit    int
+	Cursor   string
+	ViewerID string // "" = anónimo
+}
+
+// Exec devuelve la conversación completa a la que pertenece TweetID, desde la
+// raíz. Si TweetID es de una cuenta protegida que el lector no sigue, no existe.
+func (uc GetThread) Exec(ctx context.Context, in GetThreadInput) (Page[domain.ThreadEntry], error) {
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.ThreadEntry]{}, err
+	}
+	tw, err := uc.Tweets.Get(ctx, in.TweetID)
+	if err != nil {
+		return Page[domain.ThreadEntry]{}, err
+	}
+	if err := requireVisible(ctx, uc.Users, uc.Follows, in.ViewerID, tw); err != nil {
+		return Page[domain.ThreadEntry]{}, err
+	}
+	p, err := uc.Tweets.Conversation(ctx, tw.ConversationID, q)
+	if err != nil {
+		return Page[domain.ThreadEntry]{}, err
+	}
+	out := newPage(p, q, threadKey)
+	out.Items, err = withoutProtected(ctx, uc.Users, uc.Follows, in.ViewerID, out.Items,
+		func(e domain.ThreadEntry) string { return e.UserID })
+	return out, err
+}
+
+// threadKey posiciona por thread_path; created_at no participa del orden.
+func threadKey(e domain.ThreadEntry) (int64, string) { return 0, e.Path }
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"tweetschallenge/internal/domain"
 	"tweetschallenge/internal/ports"
@@ -11,24 +13,312 @@ import (
 type GetTimeline struct {
 	Tweets  ports.TweetRepo
 	Follows ports.FollowRepo
+	Home    ports.HomeTimelineRepo // nil: fan-out on read puro
+	Users   ports.UserRepo         // opcional: oculta tweets de cuentas suspendidas
+	Blocks  ports.BlockRepo        // opcional: oculta tweets de quien bloqueó al usuario
+	Mutes   ports.MuteRepo         // opcional: oculta cuentas y textos silenciados
+	Clock   ports.Clock            // vencimiento de los mutes
 }
 
 type GetTimelineInput struct {
-	UserID string
-	Limit  int
-	Offset int
+	UserID      string
+	ActorID     string // quien lee: sólo el propio usuario o un admin
+	Limit       int
+	Cursor      string
+	Offset      int  // Deprecated: sólo se usa si no hay Cursor
+	IncludeSelf bool // mezcla los tweets propios del usuario
 }
 
-func (uc GetTimeline) Exec(ctx context.Context, in GetTimelineInput) ([]domain.Tweet, error) {
+// maxTimelineReads acota el over-fetching: si el filtro descarta casi todo,
+// la página sale corta pero con cursor para seguir desde lo ya leído.
+const maxTimelineReads = 5
+
+func (uc GetTimeline) Exec(ctx context.Context, in GetTimelineInput) (Page[domain.Tweet], error) {
 	if uc.Follows == nil {
-		return nil, fmt.Errorf("timeline: follow repo not wired")
+		return Page[domain.Tweet]{}, fmt.Errorf("timeline: follow repo not wired")
 	}
-	ids, err := uc.Follows.FollowingIDs(ctx, in.UserID)
+	if err := uc.authorize(ctx, in); err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	f, err := uc.filter(ctx, in.UserID)
 	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	if in.Cursor == "" && in.Offset > 0 {
+		list, err := uc.offsetList(ctx, in)
+		if err != nil {
+			return Page[domain.Tweet]{}, err
+		}
+		items, err := f.apply(ctx, uc.Tweets, list)
+		return Page[domain.Tweet]{Items: items}, err
+	}
+	read, err := uc.source(ctx, in)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+
+	// Se sigue leyendo desde el último item leído hasta llenar la página con
+	// items visibles (hydrateRetweets también agrupa) o agotar el timeline.
+	limit := ports.PageLimit(q.Limit)
+	newer := q.Cursor != nil && q.Cursor.Newer
+	next := q
+	var raw, items []domain.Tweet
+	more := false
+	for i := 0; i < maxTimelineReads; i++ {
+		p, err := read(next)
+		if err != nil {
+			return Page[domain.Tweet]{}, err
+		}
+		if newer {
+			raw = append(p.Items, raw...)
+		} else {
+			raw = append(raw, p.Items...)
+		}
+		if items, err = f.apply(ctx, uc.Tweets, raw); err != nil {
+			return Page[domain.Tweet]{}, err
+		}
+		more = p.HasMore
+		if len(items) >= limit || !more || len(p.Items) == 0 {
+			break
+		}
+		edge := p.Items[len(p.Items)-1]
+		if newer {
+			edge = p.Items[0]
+		}
+		next.Cursor = &domain.Cursor{CreatedAt: edge.CreatedAt, ID: edge.ID, Newer: newer}
+	}
+	return timelinePage(q, raw, items, limit, more), nil
+}
+
+// authorize: el timeline filtra por lo que puede ver su dueño (bloqueos,
+// cuentas protegidas), así que leer el de otro expondría lo que a uno le
+// ocultan. Sólo un admin puede leer uno ajeno (requiere Users).
+func (uc GetTimeline) authorize(ctx context.Context, in GetTimelineInput) error {
+	if in.ActorID == in.UserID {
+		return nil
+	}
+	if in.ActorID != "" && uc.Users != nil {
+		actor, err := uc.Users.Get(ctx, in.ActorID)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return err
+		}
+		if actor.IsAdmin() {
+			return nil
+		}
+	}
+	return domain.ErrForbidden
+}
+
+// timelinePage recorta a limit (conservando lo más cercano al cursor) y arma
+// los cursores. Si la página no se llenó, los cursores salen de lo leído y no
+// de lo visible, así el próximo pedido no relee lo que el filtro descartó.
+func timelinePage(q ports.PageQuery, raw, items []domain.Tweet, limit int, more bool) Page[domain.Tweet] {
+	newer := q.Cursor != nil && q.Cursor.Newer
+	out := Page[domain.Tweet]{Items: items}
+	if out.Items == nil {
+		out.Items = []domain.Tweet{}
+	}
+	var first, last *domain.Tweet
+	if len(raw) > 0 {
+		first, last = &raw[0], &raw[len(raw)-1]
+	}
+	if len(items) > limit {
+		more = true
+		if newer {
+			out.Items = items[len(items)-limit:]
+			first = &out.Items[0]
+		} else {
+			out.Items = items[:limit]
+			last = &out.Items[limit-1]
+		}
+	}
+	switch {
+	case first != nil:
+		out.PrevCursor = domain.Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Newer: true}.Encode()
+	case newer:
+		out.PrevCursor = q.Cursor.Encode()
+	}
+	if last != nil && (newer || more) {
+		out.NextCursor = domain.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return out
+}
+
+// timelineFilter reúne lo que GetTimeline oculta: autores que bloquearon al
+// usuario, cuentas silenciadas, suspendidas o protegidas y textos silenciados.
+type timelineFilter struct {
+	viewer  string
+	users   ports.UserRepo
+	follows ports.FollowRepo
+	hidden  map[string]bool
+	mutes   domain.MuteFilter
+}
+
+func (uc GetTimeline) filter(ctx context.Context, userID string) (timelineFilter, error) {
+	f := timelineFilter{viewer: userID, users: uc.Users, follows: uc.Follows, hidden: map[string]bool{}}
+	if uc.Blocks != nil {
+		ids, err := uc.Blocks.BlockerIDs(ctx, userID)
+		if err != nil {
+			return f, err
+		}
+		for _, id := range ids {
+			f.hidden[id] = true
+		}
+	}
+	if uc.Mutes != nil {
+		var now int64
+		if uc.Clock != nil {
+			now = uc.Clock.NowUnix()
+		}
+		mutes, err := uc.Mutes.Active(ctx, userID, now)
+		if err != nil {
+			return f, err
+		}
+		f.mutes = domain.NewMuteFilter(mutes)
+		for id := range f.mutes.Accounts() {
+			f.hidden[id] = true
+		}
+	}
+	return f, nil
+}
+
+// apply filtra una copia de raw: primero autores y retweeteadores ocultos,
+// después (ya con hydrateRetweets) los retweets cuyo original es de alguno de
+// ellos, los originales protegidos que el usuario no sigue y, por último, los
+// textos silenciados. Los tweets propios no se silencian.
+func (f timelineFilter) apply(ctx context.Context, tweets ports.TweetRepo, raw []domain.Tweet) ([]domain.Tweet, error) {
+	items := slices.Clone(raw)
+	author := func(t domain.Tweet) string { return t.UserID }
+	items = withoutAuthors(items, f.hidden, author)
+	items, err := withoutSuspended(ctx, f.users, items, author)
+	if err != nil {
+		return nil, err
+	}
+	if items, err = hydrateRetweets(ctx, tweets, items); err != nil {
+		return nil, err
+	}
+	items = withoutAuthors(items, f.hidden, retweetedAuthor)
+	if items, err = withoutSuspended(ctx, f.users, items, retweetedAuthor); err != nil {
+		return nil, err
+	}
+	if items, err = visibleTweets(ctx, f.users, f.follows, f.viewer, items); err != nil {
 		return nil, err
 	}
-	if len(ids) == 0 {
-		return []domain.Tweet{}, nil
+	out := items[:0]
+	for _, t := range items {
+		muted := f.mutes.Hides(t.Text) || (t.Retweeted != nil && f.mutes.Hides(t.Retweeted.Text))
+		if !muted || t.UserID == f.viewer {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// withoutSuspended filtra los items cuyo author(t) tiene la cuenta suspendida
+// ("" = no filtrar). Sin repo de usuarios no filtra nada.
+func withoutSuspended(ctx context.Context, users ports.UserRepo, items []domain.Tweet, author func(domain.Tweet) string) ([]domain.Tweet, error) {
+	if users == nil || len(items) == 0 {
+		return items, nil
+	}
+	var ids []string
+	for _, t := range items {
+		if id := author(t); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	suspended, err := users.SuspendedIDs(ctx, ids)
+	if err != nil || len(suspended) == 0 {
+		return items, err
+	}
+	return withoutAuthors(items, idSet(suspended), author), nil
+}
+
+// withoutAuthors filtra en el lugar los items cuyo author(t) está en hidden.
+func withoutAuthors(items []domain.Tweet, hidden map[string]bool, author func(domain.Tweet) string) []domain.Tweet {
+	if len(hidden) == 0 {
+		return items
+	}
+	out := items[:0]
+	for _, t := range items {
+		if !hidden[author(t)] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func idSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// source devuelve cómo leer páginas crudas del timeline: el materializado o,
+// sin él, fan-out on read sobre los seguidos.
+func (uc GetTimeline) source(ctx context.Context, in GetTimelineInput) (func(ports.PageQuery) (ports.Page[domain.Tweet], error), error) {
+	if uc.Home != nil {
+		return func(q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+			return uc.materialized(ctx, in, q)
+		}, nil
+	}
+	ids, err := uc.authorIDs(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return func(q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+		if len(ids) == 0 {
+			return ports.Page[domain.Tweet]{}, nil
+		}
+		return uc.Tweets.TimelineForUsersPage(ctx, ids, q)
+	}, nil
+}
+
+// offsetList es la paginación por offset (deprecada), siempre fan-out on read.
+func (uc GetTimeline) offsetList(ctx context.Context, in GetTimelineInput) ([]domain.Tweet, error) {
+	ids, err := uc.authorIDs(ctx, in)
+	if err != nil || len(ids) == 0 {
+		return []domain.Tweet{}, err
 	}
 	return uc.Tweets.TimelineForUsers(ctx, ids, in.Limit, in.Offset)
 }
+
+func (uc GetTimeline) authorIDs(ctx context.Context, in GetTimelineInput) ([]string, error) {
+	ids, err := uc.Follows.FollowingIDs(ctx, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if in.IncludeSelf {
+		ids = append(ids, in.UserID)
+	}
+	return ids, nil
+}
+
+// materialized lee el timeline precomputado y lo mezcla con lo que no se
+// materializa: seguidos heavy (modelo híbrido) y, si se pide, los propios.
+func (uc GetTimeline) materialized(ctx context.Context, in GetTimelineInput, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	p, err := uc.Home.Page(ctx, in.UserID, q)
+	if err != nil {
+		return ports.Page[domain.Tweet]{}, err
+	}
+	onRead, err := uc.Home.HeavyFollowees(ctx, in.UserID)
+	if err != nil {
+		return ports.Page[domain.Tweet]{}, err
+	}
+	if in.IncludeSelf {
+		onRead = append(onRead, in.UserID)
+	}
+	if len(onRead) > 0 {
+		extra, err := uc.Tweets.TimelineForUsersPage(ctx, onRead, q)
+		if err != nil {
+			return ports.Page[domain.Tweet]{}, err
+		}
+		p = mergeTweetPages(q, p, extra)
+	}
+	return p, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// GetUserTweets lista los tweets propios de un usuario (perfil).
+type GetUserTweets struct {
+	Tweets ports.TweetRepo
+	// Users y Follows (opcionales) ocultan las cuentas protegidas a quien no
+	// las sigue.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetUserTweetsInput struct {
+	UserID   string
+	Limit    int
+	Cursor   string
+	Offset   int    // Deprecated: sólo se usa si no hay Cursor
+	ViewerID string // "" = anónimo
+}
+
+func (uc GetUserTweets) Exec(ctx context.Context, in GetUserTweetsInput) (Page[domain.Tweet], error) {
+	p, err := uc.list(ctx, in)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	if p.Items, err = hydrateRetweets(ctx, uc.Tweets, p.Items); err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	p.Items, err = visibleTweets(ctx, uc.Users, uc.Follows, in.ViewerID, p.Items)
+	return p, err
+}
+
+func (uc GetUserTweets) list(ctx context.Context, in GetUserTweetsInput) (Page[domain.Tweet], error) {
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	if in.Cursor == "" && in.Offset > 0 {
+		list, err := uc.Tweets.Timeline(ctx, in.UserID, in.Limit, in.Offset)
+		if err != nil {
+			return Page[domain.Tweet]{}, err
+		}
+		return Page[domain.Tweet]{Items: list}, nil
+	}
+	p, err := uc.Tweets.TimelineForUsersPage(ctx, []string{in.UserID}, q)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	return newPage(p, q, tweetKey), nil
+}
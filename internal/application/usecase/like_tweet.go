@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type LikeTweet struct {
+	Tweets ports.TweetRepo
+	Likes  ports.LikeRepo
+	Clock  ports.Clock
+	IDGen  ports.IDGen
+	Blocks ports.BlockRepo // opcional: rechaza con ErrBlocked el like entre bloqueados
+	// Users y Follows (opcionales): una cuenta protegida que no se sigue no
+	// existe para dar like.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type LikeTweetInput struct{ TweetID, UserID string }
+
+// likeTarget resuelve el tweet al que apunta un like: un retweet cuenta para
+// el original. Dar like y listar quién lo dio resuelven igual, pero con
+// visibleOriginal; quitarlo no exige ver el tweet.
+func likeTarget(ctx context.Context, tweets ports.TweetRepo, tweetID string) (string, error) {
+	tw, err := tweets.Get(ctx, tweetID)
+	if err != nil {
+		return "", err
+	}
+	if tw.RetweetOfID != "" {
+		return tw.RetweetOfID, nil
+	}
+	return tw.ID, nil
+}
+
+// Exec es idempotente, como seguir. Dar like a un retweet cuenta para el original.
+func (uc LikeTweet) Exec(ctx context.Context, in LikeTweetInput) (domain.Like, error) {
+	target, err := visibleOriginal(ctx, uc.Tweets, uc.Users, uc.Follows, in.UserID, in.TweetID)
+	if err != nil {
+		return domain.Like{}, err
+	}
+	if err := rejectBlocked(ctx, uc.Blocks, in.UserID, target.UserID); err != nil {
+		return domain.Like{}, err
+	}
+	l, err := domain.NewLike(uc.IDGen.NewID(), in.UserID, target.ID, uc.Clock.NowUnix())
+	if err != nil {
+		return domain.Like{}, err
+	}
+	if err := uc.Likes.Create(ctx, &l); err != nil {
+		return domain.Like{}, err
+	}
+	return l, nil
+}
+
+type UnlikeTweet struct {
+	Tweets ports.TweetRepo
+	Likes  ports.LikeRepo
+}
+
+// Exec es idempotente: sobre un tweet que ya no existe no hay like que quitar.
+func (uc UnlikeTweet) Exec(ctx context.Context, in LikeTweetInput) error {
+	target, err := likeTarget(ctx, uc.Tweets, in.TweetID)
+	if errors.Is(err, domain.ErrTweetNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return uc.Likes.Unlike(ctx, in.UserID, target)
+}
+
+// GetTweetLikes lista quiénes dieron like a un tweet, los más recientes primero.
+type GetTweetLikes struct {
+	Tweets ports.TweetRepo
+	Likes  ports.LikeRepo
+	// Users y Follows (opcionales) ocultan las cuentas protegidas a quien no
+	// las sigue.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetTweetLikesInput struct {
+	TweetID  string
+	Limit    int
+	Cursor   string
+	ViewerID string // "" = anónimo
+}
+
+func (uc GetTweetLikes) Exec(ctx context.Context, in GetTweetLikesInput) (Page[domain.Like], error) {
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.Like]{}, err
+	}
+	target, err := visibleOriginal(ctx, uc.Tweets, uc.Users, uc.Follows, in.ViewerID, in.TweetID)
+	if err != nil {
+		return Page[domain.Like]{}, err
+	}
+	p, err := uc.Likes.LikersPage(ctx, target.ID, q)
+	if err != nil {
+		return Page[domain.Like]{}, err
+	}
+	return newPage(p, q, func(l domain.Like) (int64, string) { return l.CreatedAt, l.UserID }), nil
+}
+
+// GetUserLikes lista los tweets que le gustaron a un usuario, por fecha del like.
+type GetUserLikes struct {
+	Likes ports.LikeRepo
+	// Users y Follows (opcionales) ocultan las cuentas protegidas a quien no
+	// las sigue.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetUserLikesInput struct {
+	UserID   string
+	Limit    int
+	Cursor   string
+	ViewerID string // "" = anónimo
+}
+
+func (uc GetUserLikes) Exec(ctx context.Context, in GetUserLikesInput) (Page[domain.LikedTweet], error) {
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.LikedTweet]{}, err
+	}
+	p, err := uc.Likes.LikedTweetsPage(ctx, in.UserID, q)
+	if err != nil {
+		return Page[domain.LikedTweet]{}, err
+	}
+	out := newPage(p, q, func(t domain.LikedTweet) (int64, string) { return t.LikedAt, t.ID })
+	out.Items, err = withoutProtected(ctx, uc.Users, uc.Follows, in.ViewerID, out.Items,
+		func(t domain.LikedTweet) string { return t.UserID })
+	return out, err
+}
@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// resolveMentions resuelve los @handle de tw a ids y descarta los que no son
+// usuarios; el texto no cambia. Sin repo no queda ninguna mención.
+func resolveMentions(ctx context.Context, users ports.UserRepo, tw *domain.Tweet) error {
+	if len(tw.Mentions) == 0 {
+		return nil
+	}
+	byHandle := map[string]string{}
+	if users != nil {
+		found, err := users.ByHandles(ctx, domain.MentionedHandles(tw.Mentions))
+		if err != nil {
+			return err
+		}
+		for _, u := range found {
+			byHandle[domain.HandleKey(u.Handle)] = u.ID
+		}
+	}
+	tw.ResolveMentions(byHandle)
+	return nil
+}
+
+// GetUserMentions lista los tweets que mencionan a un usuario, más nuevos primero.
+type GetUserMentions struct {
+	Tweets ports.TweetRepo
+	// Users y Follows (opcionales) ocultan las cuentas protegidas a quien no
+	// las sigue.
+	Users   ports.UserRepo
+	Follows ports.FollowRepo
+}
+
+type GetUserMentionsInput struct {
+	UserID   string
+	Limit    int
+	Cursor   string
+	ViewerID string // "" = anónimo
+}
+
+func (uc GetUserMentions) Exec(ctx context.Context, in GetUserMentionsInput) (Page[domain.Tweet], error) {
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	p, err := uc.Tweets.MentionTimelinePage(ctx, in.UserID, q)
+	if err != nil {
+		return Page[domain.Tweet]{}, err
+	}
+	out := newPage(p, q, tweetKey)
+	out.Items, err = visibleTweets(ctx, uc.Users, uc.Follows, in.ViewerID, out.Items)
+	return out, err
+}
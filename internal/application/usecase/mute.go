@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// MuteUser silencia una cuenta, palabra o regex en el timeline sin dejar de
+// seguir. Repetir un mute sólo cambia su vencimiento.
+type MuteUser struct {
+	Mutes ports.MuteRepo
+	Clock ports.Clock
+	IDGen ports.IDGen
+	Users ports.UserRepo // valida la cuenta silenciada (opcional)
+}
+
+type MuteUserInput struct {
+	UserID    string
+	Kind      string
+	Value     string
+	ExpiresIn int64 // segundos; 0 = no vence
+}
+
+func (uc MuteUser) Exec(ctx context.Context, in MuteUserInput) (domain.Mute, error) {
+	if in.ExpiresIn < 0 {
+		return domain.Mute{}, domain.ErrInvalidMute
+	}
+	now := uc.Clock.NowUnix()
+	var expiresAt int64
+	if in.ExpiresIn > 0 {
+		expiresAt = now + in.ExpiresIn
+	}
+	m, err := domain.NewMute(uc.IDGen.NewID(), in.UserID, in.Kind, in.Value, now, expiresAt)
+	if err != nil {
+		return domain.Mute{}, err
+	}
+	if m.Kind == domain.MuteAccount {
+		if err := requireUsers(ctx, uc.Users, m.Value); err != nil {
+			return domain.Mute{}, err
+		}
+	}
+	if err := uc.Mutes.Create(ctx, &m); err != nil {
+		return domain.Mute{}, err
+	}
+	return m, nil
+}
+
+// UnmuteUser borra un mute propio por id (idempotente).
+type UnmuteUser struct{ Mutes ports.MuteRepo }
+
+type UnmuteUserInput struct{ UserID, MuteID string }
+
+func (uc UnmuteUser) Exec(ctx context.Context, in UnmuteUserInput) error {
+	return uc.Mutes.Delete(ctx, in.UserID, in.MuteID)
+}
+
+// ListMutes devuelve los mutes vigentes del usuario, más recientes primero.
+type ListMutes struct {
+	Mutes ports.MuteRepo
+	Clock ports.Clock
+}
+
+func (uc ListMutes) Exec(ctx context.Context, userID string) ([]domain.Mute, error) {
+	return uc.Mutes.Active(ctx, userID, uc.Clock.NowUnix())
+}
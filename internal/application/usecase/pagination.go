@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"sort"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// Page es una página lista para exponer, con cursores opacos.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string // items más viejos
+	PrevCursor string // items más nuevos (sirve también para "polling")
+}
+
+func pageQuery(limit int, cursor string) (ports.PageQuery, error) {
+	q := ports.PageQuery{Limit: limit}
+	if cursor == "" {
+		return q, nil
+	}
+	c, err := domain.DecodeCursor(cursor)
+	if err != nil {
+		return q, err
+	}
+	q.Cursor = &c
+	return q, nil
+}
+
+// newPage arma los cursores a partir de la página del repo; key devuelve la
+// posición (created_at, id) de cada item.
+func newPage[T any](p ports.Page[T], q ports.PageQuery, key func(T) (int64, string)) Page[T] {
+	out := Page[T]{Items: p.Items}
+	if out.Items == nil {
+		out.Items = []T{}
+	}
+	newer := q.Cursor != nil && q.Cursor.Newer
+	if len(out.Items) == 0 {
+		if newer {
+			out.PrevCursor = q.Cursor.Encode()
+		}
+		return out
+	}
+	ts, id := key(out.Items[0])
+	out.PrevCursor = domain.Cursor{CreatedAt: ts, ID: id, Newer: true}.Encode()
+	if newer || p.HasMore {
+		ts, id = key(out.Items[len(out.Items)-1])
+		out.NextCursor = domain.Cursor{CreatedAt: ts, ID: id}.Encode()
+	}
+	return out
+}
+
+func tweetKey(t domain.Tweet) (int64, string) { return t.CreatedAt, t.ID }
+
+// mergeTweetPages combina páginas pedidas con el mismo PageQuery a distintas
+// fuentes, sin duplicados, conservando los items más cercanos al cursor.
+func mergeTweetPages(q ports.PageQuery, pages ...ports.Page[domain.Tweet]) ports.Page[domain.Tweet] {
+	seen := map[string]bool{}
+	var all []domain.Tweet
+	hasMore := false
+	for _, p := range pages {
+		hasMore = hasMore || p.HasMore
+		for _, t := range p.Items {
+			if !seen[t.ID] {
+				seen[t.ID] = true
+				all = append(all, t)
+			}
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].CreatedAt != all[j].CreatedAt {
+			return all[i].CreatedAt > all[j].CreatedAt
+		}
+		return all[i].ID > all[j].ID
+	})
+	if limit := ports.PageLimit(q.Limit); len(all) > limit {
+		hasMore = true
+		if q.Cursor != nil && q.Cursor.Newer {
+			all = all[len(all)-limit:]
+		} else {
+			all = all[:limit]
+		}
+	}
+	return ports.Page[domain.Tweet]{Items: all, HasMore: hasMore}
+}
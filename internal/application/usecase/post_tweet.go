@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"tweetschallenge/internal/domain"
 	"tweetschallenge/internal/ports"
@@ -11,19 +13,98 @@ type PostTweet struct {
 	Tweets ports.TweetRepo
 	Clock  ports.Clock
 	IDGen  ports.IDGen
+	Users  ports.UserRepo  // valida autor (existe y no está suspendido) y menciones (opcional)
+	Blocks ports.BlockRepo // opcional: rechaza con ErrBlocked responder o citar a quien bloqueó (o fue bloqueado)
+
+	// Fan-out on write (opcional): sin Home el timeline se arma al leer.
+	Follows            ports.FollowRepo
+	Home               ports.HomeTimelineRepo
+	FanoutMaxFollowers int // 0 = sin límite
 }
 
-type PostTweetInput struct{ UserID, Text string }
+type PostTweetInput struct {
+	UserID, Text  string
+	InReplyToID   string // opcional
+	QuotedTweetID string // opcional: cita
+}
 
 func (uc PostTweet) Exec(ctx context.Context, in PostTweetInput) (domain.Tweet, error) {
 	id := uc.IDGen.NewID()
 	now := uc.Clock.NowUnix()
 	tw, err := domain.NewTweet(id, in.UserID, in.Text, now)
+	if in.InReplyToID != "" {
+		var parent domain.Tweet
+		parent, err = uc.Tweets.Get(ctx, in.InReplyToID)
+		if err == nil {
+			// un padre que el autor no puede ver responde igual que uno inexistente
+			err = requireVisible(ctx, uc.Users, uc.Follows, in.UserID, parent)
+		}
+		if errors.Is(err, domain.ErrTweetNotFound) {
+			return domain.Tweet{}, domain.ErrParentNotFound
+		}
+		if err != nil {
+			return domain.Tweet{}, err
+		}
+		if err := rejectBlocked(ctx, uc.Blocks, in.UserID, parent.UserID); err != nil {
+			return domain.Tweet{}, err
+		}
+		tw, err = domain.NewReply(id, in.UserID, in.Text, now, parent)
+	}
 	if err != nil {
 		return domain.Tweet{}, err
 	}
+	if in.QuotedTweetID != "" {
+		quoted, err := visibleOriginal(ctx, uc.Tweets, uc.Users, uc.Follows, in.UserID, in.QuotedTweetID)
+		if errors.Is(err, domain.ErrTweetNotFound) {
+			return domain.Tweet{}, domain.ErrQuotedNotFound
+		}
+		if err != nil {
+			return domain.Tweet{}, err
+		}
+		if err := rejectBlocked(ctx, uc.Blocks, in.UserID, quoted.UserID); err != nil {
+			return domain.Tweet{}, err
+		}
+		tw.Quote(quoted)
+	}
+	if err := requireActive(ctx, uc.Users, tw.UserID); err != nil {
+		return domain.Tweet{}, err
+	}
+	if err := resolveMentions(ctx, uc.Users, &tw); err != nil {
+		return domain.Tweet{}, err
+	}
 	if err := uc.Tweets.Create(ctx, &tw); err != nil {
 		return domain.Tweet{}, err
 	}
+	if err := fanout(ctx, uc.Home, uc.Follows, uc.FanoutMaxFollowers, tw); err != nil {
+		return domain.Tweet{}, fmt.Errorf("fanout: %w", err)
+	}
 	return tw, nil
 }
+
+// fanout empuja tw al timeline materializado de cada seguidor. Si el autor
+// supera maxFollowers queda marcado como heavy (para siempre) y sus tweets
+// pasan a leerse con fan-out on read.
+func fanout(ctx context.Context, home ports.HomeTimelineRepo, follows ports.FollowRepo, maxFollowers int, tw domain.Tweet) error {
+	if home == nil || follows == nil {
+		return nil
+	}
+	heavy, err := home.IsHeavy(ctx, tw.UserID)
+	if err != nil || heavy {
+		return err
+	}
+	// se cuenta antes de cargar los ids: a un autor heavy no se le leen
+	if maxFollowers > 0 {
+		n, err := follows.CountFollowers(ctx, tw.UserID)
+		if err != nil {
+			return err
+		}
+		if n > int64(maxFollowers) {
+			return home.MarkHeavy(ctx, tw.UserID, tw.CreatedAt)
+		}
+	}
+	followers, err := follows.FollowersIDs(ctx, tw.UserID)
+	if err != nil {
+		return err
+	}
+	return home.Push(ctx, followers, tw)
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type Retweet struct {
+	Tweets ports.TweetRepo
+	Clock  ports.Clock
+	IDGen  ports.IDGen
+	Blocks ports.BlockRepo // opcional: rechaza con ErrBlocked retwittear a quien bloqueó (o fue bloqueado)
+	Users  ports.UserRepo  // opcional, con Follows: una cuenta protegida que no se sigue no existe
+
+	// Fan-out on write (opcional), igual que PostTweet.
+	Follows            ports.FollowRepo
+	Home               ports.HomeTimelineRepo
+	FanoutMaxFollowers int
+}
+
+type RetweetInput struct{ TweetID, UserID string }
+
+// Exec es idempotente: si el usuario ya lo retwitteó devuelve ese retweet con created=false.
+func (uc Retweet) Exec(ctx context.Context, in RetweetInput) (rt domain.Tweet, created bool, err error) {
+	original, err := visibleOriginal(ctx, uc.Tweets, uc.Users, uc.Follows, in.UserID, in.TweetID)
+	if err != nil {
+		return domain.Tweet{}, false, err
+	}
+	if err := rejectBlocked(ctx, uc.Blocks, in.UserID, original.UserID); err != nil {
+		return domain.Tweet{}, false, err
+	}
+	rt, err = domain.NewRetweet(uc.IDGen.NewID(), in.UserID, uc.Clock.NowUnix(), original)
+	if err != nil {
+		return domain.Tweet{}, false, err
+	}
+	created, err = uc.Tweets.Retweet(ctx, &rt)
+	if err != nil || !created {
+		return rt, false, err
+	}
+	if err := fanout(ctx, uc.Home, uc.Follows, uc.FanoutMaxFollowers, rt); err != nil {
+		return domain.Tweet{}, false, fmt.Errorf("fanout: %w", err)
+	}
+	return rt, true, nil
+}
+
+type Unretweet struct {
+	Tweets ports.TweetRepo
+	Home   ports.HomeTimelineRepo // opcional: purga timelines materializados
+	Clock  ports.Clock
+}
+
+// Exec es idempotente: deshacer un retweet inexistente no es error.
+func (uc Unretweet) Exec(ctx context.Context, in RetweetInput) error {
+	rt, err := uc.Tweets.FindRetweet(ctx, in.UserID, in.TweetID)
+	if errors.Is(err, domain.ErrTweetNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := uc.Tweets.SoftDelete(ctx, rt.ID, uc.Clock.NowUnix()); err != nil && !errors.Is(err, domain.ErrTweetNotFound) {
+		return err
+	}
+	if uc.Home == nil {
+		return nil
+	}
+	return uc.Home.RemoveTweet(ctx, rt.ID)
+}
+
+// hydrateRetweets adjunta el original (con su autor) a cada retweet y
+// colapsa en un solo item los retweets del mismo tweet dentro de la página,
+// acumulando quiénes lo retwittearon. Los retweets de originales borrados se
+// descartan. Los cursores ya se calcularon sobre la página sin colapsar.
+func hydrateRetweets(ctx context.Context, tweets ports.TweetRepo, items []domain.Tweet) ([]domain.Tweet, error) {
+	var ids []string
+	for _, t := range items {
+		if t.RetweetOfID != "" {
+			ids = append(ids, t.RetweetOfID)
+		}
+	}
+	if len(ids) == 0 {
+		return items, nil
+	}
+	originals, err := tweets.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]domain.Tweet, len(originals))
+	for _, o := range originals {
+		byID[o.ID] = o
+	}
+
+	out := make([]domain.Tweet, 0, len(items))
+	pos := map[string]int{}
+	for _, t := range items {
+		key := t.ID
+		if t.RetweetOfID != "" {
+			key = t.RetweetOfID
+		}
+		if i, ok := pos[key]; ok {
+			if t.RetweetOfID != "" {
+				out[i].RetweetedBy = append(out[i].RetweetedBy, t.UserID)
+			}
+			continue
+		}
+		if t.RetweetOfID != "" {
+			o, ok := byID[t.RetweetOfID]
+			if !ok {
+				continue
+			}
+			t.Retweeted = &o
+			t.RetweetedBy = []string{t.UserID}
+		}
+		pos[key] = len(out)
+		out = append(out, t)
+	}
+	return out, nil
+}
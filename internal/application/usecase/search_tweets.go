@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// SearchTweets busca tweets vivos (sin retweets) por texto y operadores.
+type SearchTweets struct {
+	Search ports.TweetSearcher
+	Users  ports.UserRepo // resuelve from:handle a id (opcional: sin repo se toma como id)
+	// Follows (opcional, junto con Users) oculta las cuentas protegidas a
+	// quien no las sigue.
+	Follows ports.FollowRepo
+}
+
+type SearchTweetsInput struct {
+	Query    string // ver domain.ParseSearchQuery
+	Order    string // recent (default) | relevance
+	Limit    int
+	Cursor   string
+	ViewerID string // "" = anónimo
+}
+
+func (uc SearchTweets) Exec(ctx context.Context, in SearchTweetsInput) (Page[domain.SearchHit], error) {
+	sq, err := domain.ParseSearchQuery(in.Query, in.Order)
+	if err != nil {
+		return Page[domain.SearchHit]{}, err
+	}
+	q, err := pageQuery(in.Limit, in.Cursor)
+	if err != nil {
+		return Page[domain.SearchHit]{}, err
+	}
+	if sq.From != "" && uc.Users != nil {
+		found, err := uc.Users.ByHandles(ctx, []string{sq.From})
+		if err != nil {
+			return Page[domain.SearchHit]{}, err
+		}
+		if len(found) == 0 {
+			return newPage(ports.Page[domain.SearchHit]{}, q, searchKey), nil
+		}
+		sq.From = found[0].ID
+	}
+	p, err := uc.Search.Search(ctx, sq, q)
+	if err != nil {
+		return Page[domain.SearchHit]{}, err
+	}
+	out := newPage(p, q, searchKey)
+	out.Items, err = withoutProtected(ctx, uc.Users, uc.Follows, in.ViewerID, out.Items,
+		func(h domain.SearchHit) string { return h.UserID })
+	return out, err
+}
+
+// searchKey: el cursor guarda el score en lugar de created_at (son iguales en recent).
+func searchKey(h domain.SearchHit) (int64, string) { return h.Score, h.ID }
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// TokenPair sigue la respuesta de token de OAuth 2 (RFC 6749 §5.1).
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	Scope        string `json:"scope"`
+}
+
+// TokenConfig son las dependencias comunes de IssueTokens y RefreshTokens.
+type TokenConfig struct {
+	Tokens     ports.AccessTokens
+	Refresh    ports.RefreshTokenRepo
+	Clock      ports.Clock
+	IDGen      ports.IDGen
+	Secrets    ports.SecretGen
+	AccessTTL  int64 // segundos
+	RefreshTTL int64
+}
+
+// mint firma un access token y crea su refresh token, atado a la API key que
+// inició la cadena; si rotateFrom no es vacío, el refresh nuevo reemplaza a
+// ese en la misma transacción.
+func (cfg TokenConfig) mint(ctx context.Context, userID, apiKeyID string, scopes []string, rotateFrom string) (TokenPair, error) {
+	now := cfg.Clock.NowUnix()
+	access, err := cfg.Tokens.Sign(domain.AccessClaims{UserID: userID, Scopes: scopes, IssuedAt: now, ExpiresAt: now + cfg.AccessTTL})
+	if err != nil {
+		return TokenPair{}, err
+	}
+	rt, refresh, err := domain.NewRefreshToken(cfg.IDGen.NewID(), userID, apiKeyID, cfg.Secrets.NewSecret(), scopes, now, cfg.RefreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if rotateFrom == "" {
+		err = cfg.Refresh.Create(ctx, &rt)
+	} else {
+		err = cfg.Refresh.Rotate(ctx, rotateFrom, &rt, now)
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken: access, TokenType: "Bearer", ExpiresIn: cfg.AccessTTL,
+		RefreshToken: refresh, Scope: domain.JoinScopes(scopes),
+	}, nil
+}
+
+// IssueTokens canjea una API key (el caller ya autenticado) por un par
+// access/refresh con los scopes pedidos (todos si no se piden).
+type IssueTokens struct{ TokenConfig }
+
+type IssueTokensInput struct {
+	UserID   string
+	APIKeyID string // la key con la que se autenticó el caller
+	Scopes   []string
+}
+
+func (uc IssueTokens) Exec(ctx context.Context, in IssueTokensInput) (TokenPair, error) {
+	scopes, err := domain.ParseScopes(in.Scopes)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return uc.mint(ctx, in.UserID, in.APIKeyID, scopes, "")
+}
+
+// RefreshTokens rota el refresh token: el usado queda revocado y se emite un
+// par nuevo con los mismos scopes. Presentar uno ya revocado indica que se
+// filtró, así que se revocan todos los del usuario. Si la API key que inició
+// la cadena ya no está activa (revocada o rotada) el refresh se rechaza.
+type RefreshTokens struct {
+	TokenConfig
+	Keys ports.APIKeyRepo
+}
+
+func (uc RefreshTokens) Exec(ctx context.Context, token string) (TokenPair, error) {
+	if !strings.HasPrefix(token, domain.RefreshTokenPrefix) {
+		return TokenPair{}, domain.ErrUnauthenticated
+	}
+	rt, err := uc.Refresh.ByHash(ctx, domain.HashAPIKey(token))
+	if err != nil {
+		return TokenPair{}, err
+	}
+	now := uc.Clock.NowUnix()
+	if rt.RevokedAt != 0 {
+		if err := uc.Refresh.RevokeUser(ctx, rt.UserID, now); err != nil {
+			return TokenPair{}, err
+		}
+		return TokenPair{}, domain.ErrUnauthenticated
+	}
+	if !rt.Usable(now) {
+		return TokenPair{}, domain.ErrUnauthenticated
+	}
+	if err := uc.keyActive(ctx, rt.APIKeyID); err != nil {
+		return TokenPair{}, err
+	}
+	return uc.mint(ctx, rt.UserID, rt.APIKeyID, rt.Scopes, rt.ID)
+}
+
+// keyActive: los refresh anteriores a la migración 0021 no tienen key y
+// tampoco se aceptan.
+func (uc RefreshTokens) keyActive(ctx context.Context, keyID string) error {
+	if keyID == "" {
+		return domain.ErrUnauthenticated
+	}
+	k, err := uc.Keys.Get(ctx, keyID)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) || (err == nil && !k.Active()) {
+		return domain.ErrUnauthenticated
+	}
+	return err
+}
@@ -5,10 +5,26 @@ import (
 	"tweetschallenge/internal/ports"
 )
 
-type UnfollowUser struct{ Follows ports.FollowRepo }
+type UnfollowUser struct {
+	Follows ports.FollowRepo
+	Home    ports.HomeTimelineRepo // opcional: purga el timeline materializado
+	// Requests (opcional) cancela también una solicitud pendiente.
+	Requests ports.FollowRequestRepo
+}
 
 type UnfollowUserInput struct{ FollowerID, FolloweeID string }
 
 func (uc UnfollowUser) Exec(ctx context.Context, in UnfollowUserInput) error {
-	return uc.Follows.Unfollow(ctx, in.FollowerID, in.FolloweeID)
+	if err := uc.Follows.Unfollow(ctx, in.FollowerID, in.FolloweeID); err != nil {
+		return err
+	}
+	if uc.Requests != nil {
+		if err := uc.Requests.Delete(ctx, in.FollowerID, in.FolloweeID); err != nil {
+			return err
+		}
+	}
+	if uc.Home == nil {
+		return nil
+	}
+	return uc.Home.PurgeAuthor(ctx, in.FollowerID, in.FolloweeID)
 }
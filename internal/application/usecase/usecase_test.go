@@ -3,7 +3,11 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
 	"tweetschallenge/internal/domain"
 	"tweetschallenge/internal/ports"
@@ -20,8 +24,9 @@ type fakeID struct{ id string }
 func (f fakeID) NewID() string { return f.id }
 
 type memTweetRepo struct {
-	created []domain.Tweet
-	byUser  map[string][]domain.Tweet
+	created   []domain.Tweet
+	byUser    map[string][]domain.Tweet
+	revisions []domain.TweetRevision
 }
 
 func (m *memTweetRepo) Create(ctx context.Context, t *domain.Tweet) error {
@@ -29,6 +34,85 @@ func (m *memTweetRepo) Create(ctx context.Context, t *domain.Tweet) error {
 	m.byUser[t.UserID] = append(m.byUser[t.UserID], *t)
 	return nil
 }
+func (m *memTweetRepo) Get(ctx context.Context, id string) (domain.Tweet, error) {
+	for _, list := range m.byUser {
+		for _, t := range list {
+			if t.ID == id && t.DeletedAt == 0 {
+				return t, nil
+			}
+		}
+	}
+	return domain.Tweet{}, domain.ErrTweetNotFound
+}
+func (m *memTweetRepo) GetMany(ctx context.Context, ids []string) ([]domain.Tweet, error) {
+	out := []domain.Tweet{}
+	for _, id := range ids {
+		if t, err := m.Get(ctx, id); err == nil {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+func (m *memTweetRepo) Retweet(ctx context.Context, t *domain.Tweet) (bool, error) {
+	if existing, err := m.FindRetweet(ctx, t.UserID, t.RetweetOfID); err == nil {
+		*t = existing
+		return false, nil
+	}
+	return true, m.Create(ctx, t)
+}
+func (m *memTweetRepo) FindRetweet(ctx context.Context, userID, originalID string) (domain.Tweet, error) {
+	for _, t := range m.byUser[userID] {
+		if t.RetweetOfID == originalID && t.DeletedAt == 0 {
+			return t, nil
+		}
+	}
+	return domain.Tweet{}, domain.ErrTweetNotFound
+}
+func (m *memTweetRepo) Edit(ctx context.Context, t *domain.Tweet, rev *domain.TweetRevision) error {
+	list := m.byUser[t.UserID]
+	for i := range list {
+		if list[i].ID == t.ID {
+			list[i] = *t
+			rev.Revision = len(m.revisions) + 1
+			m.revisions = append(m.revisions, *rev)
+			return nil
+		}
+	}
+	return domain.ErrTweetNotFound
+}
+func (m *memTweetRepo) Revisions(ctx context.Context, tweetID string) ([]domain.TweetRevision, error) {
+	return m.revisions, nil
+}
+func (m *memTweetRepo) SoftDelete(ctx context.Context, id string, deletedAt int64) error {
+	for u, list := range m.byUser {
+		for i, t := range list {
+			if t.ID == id && t.DeletedAt == 0 {
+				m.byUser[u][i].DeletedAt, m.byUser[u][i].Text = deletedAt, ""
+				return nil
+			}
+		}
+	}
+	return domain.ErrTweetNotFound
+}
+func (m *memTweetRepo) HardDelete(ctx context.Context, id string) ([]string, error) {
+	var removed []string
+	for u, list := range m.byUser {
+		kept := list[:0]
+		for _, t := range list {
+			if t.ID == id || t.RetweetOfID == id {
+				removed = append(removed, t.ID)
+				continue
+			}
+			kept = append(kept, t)
+		}
+		m.byUser[u] = kept
+	}
+	if !slices.Contains(removed, id) {
+		return nil, domain.ErrTweetNotFound
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
 func (m *memTweetRepo) Timeline(ctx context.Context, userID string, limit, offset int) ([]domain.Tweet, error) {
 	return m.byUser[userID], nil
 }
@@ -39,11 +123,239 @@ func (m *memTweetRepo) TimelineForUsers(ctx context.Context, userIDs []string, l
 	}
 	return res, nil
 }
+func (m *memTweetRepo) TimelineForUsersPage(ctx context.Context, userIDs []string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	all, _ := m.TimelineForUsers(ctx, userIDs, 0, 0)
+	return memPage(all, q), nil
+}
+
+func (m *memTweetRepo) Conversation(ctx context.Context, conversationID string, q ports.PageQuery) (ports.Page[domain.ThreadEntry], error) {
+	byID := map[string]domain.Tweet{}
+	for _, list := range m.byUser {
+		for _, t := range list {
+			byID[t.ID] = t
+		}
+	}
+	var out []domain.ThreadEntry
+	for _, t := range byID {
+		if t.ConversationID != conversationID {
+			continue
+		}
+		e := domain.ThreadEntry{Tweet: t, Path: t.ID}
+		for p := t.InReplyToID; p != ""; p = byID[p].InReplyToID {
+			e.Path = p + "/" + e.Path
+			e.Depth++
+		}
+		if q.Cursor == nil || e.Path > q.Cursor.ID {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return ports.Page[domain.ThreadEntry]{Items: out}, nil
+}
+
+type memLikeRepo struct{ likes []domain.Like }
+
+func (m *memLikeRepo) Create(ctx context.Context, l *domain.Like) error {
+	for _, x := range m.likes {
+		if x.UserID == l.UserID && x.TweetID == l.TweetID {
+			return nil
+		}
+	}
+	m.likes = append(m.likes, *l)
+	return nil
+}
+func (m *memLikeRepo) Unlike(ctx context.Context, userID, tweetID string) error {
+	for i, x := range m.likes {
+		if x.UserID == userID && x.TweetID == tweetID {
+			m.likes = append(m.likes[:i], m.likes[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+func (m *memLikeRepo) LikersPage(ctx context.Context, tweetID string, q ports.PageQuery) (ports.Page[domain.Like], error) {
+	out := []domain.Like{}
+	for _, x := range m.likes {
+		if x.TweetID == tweetID {
+			out = append(out, x)
+		}
+	}
+	return ports.Page[domain.Like]{Items: out}, nil
+}
+func (m *memLikeRepo) LikedTweetsPage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.LikedTweet], error) {
+	out := []domain.LikedTweet{}
+	for _, x := range m.likes {
+		if x.UserID == userID {
+			out = append(out, domain.LikedTweet{Tweet: domain.Tweet{ID: x.TweetID}, LikedAt: x.CreatedAt})
+		}
+	}
+	return ports.Page[domain.LikedTweet]{Items: out}, nil
+}
+
+func (m *memTweetRepo) HashtagTimelinePage(ctx context.Context, tag string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	var all []domain.Tweet
+	for _, list := range m.byUser {
+		for _, t := range list {
+			if t.DeletedAt == 0 && slices.Contains(domain.ExtractHashtags(t.Text), tag) {
+				all = append(all, t)
+			}
+		}
+	}
+	return memPage(all, q), nil
+}
+
+func (m *memTweetRepo) MentionTimelinePage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	var all []domain.Tweet
+	for _, list := range m.byUser {
+		for _, t := range list {
+			if t.DeletedAt == 0 && slices.Contains(domain.MentionedIDs(t.Mentions), userID) {
+				all = append(all, t)
+			}
+		}
+	}
+	return memPage(all, q), nil
+}
+
+// memUserRepo indexa por id; el handle único se chequea recorriendo.
+type memUserRepo map[string]domain.User
+
+func (r memUserRepo) Create(ctx context.Context, u *domain.User) error {
+	for _, o := range r {
+		if domain.HandleKey(o.Handle) == domain.HandleKey(u.Handle) {
+			return domain.ErrHandleTaken
+		}
+	}
+	r[u.ID] = *u
+	return nil
+}
+
+func (r memUserRepo) Get(ctx context.Context, id string) (domain.User, error) {
+	u, ok := r[id]
+	if !ok {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (r memUserRepo) Update(ctx context.Context, u *domain.User) error {
+	for id, o := range r {
+		if id != u.ID && domain.HandleKey(o.Handle) == domain.HandleKey(u.Handle) {
+			return domain.ErrHandleTaken
+		}
+	}
+	if _, ok := r[u.ID]; !ok {
+		return domain.ErrUserNotFound
+	}
+	r[u.ID] = *u
+	return nil
+}
+
+func (r memUserRepo) ByHandles(ctx context.Context, handles []string) ([]domain.User, error) {
+	var out []domain.User
+	for _, u := range r {
+		if slices.ContainsFunc(handles, func(h string) bool { return domain.HandleKey(h) == domain.HandleKey(u.Handle) }) {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+func (r memUserRepo) SetRole(ctx context.Context, id, role string) error {
+	u, ok := r[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.Role = role
+	r[id] = u
+	return nil
+}
+
+func (r memUserRepo) SetSuspended(ctx context.Context, id string, at int64) error {
+	u, ok := r[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	u.SuspendedAt = at
+	r[id] = u
+	return nil
+}
+
+func (r memUserRepo) SuspendedIDs(ctx context.Context, ids []string) ([]string, error) {
+	var out []string
+	for _, id := range ids {
+		if r[id].Suspended() && !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+func (r memUserRepo) ProtectedIDs(ctx context.Context, ids []string) ([]string, error) {
+	var out []string
+	for _, id := range ids {
+		if r[id].Protected && !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+func newMemUsers(handles ...string) memUserRepo {
+	r := memUserRepo{}
+	for _, h := range handles {
+		r[h] = domain.User{ID: h, Handle: h, DisplayName: h, Role: domain.RoleUser}
+	}
+	return r
+}
+
+// memPage replica la semántica keyset de los repos: orden (created_at, id) DESC.
+func memPage(all []domain.Tweet, q ports.PageQuery) ports.Page[domain.Tweet] {
+	sorted := append([]domain.Tweet(nil), all...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt != sorted[j].CreatedAt {
+			return sorted[i].CreatedAt > sorted[j].CreatedAt
+		}
+		return sorted[i].ID > sorted[j].ID
+	})
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	c := q.Cursor
+	older := func(t domain.Tweet) bool {
+		return t.CreatedAt < c.CreatedAt || (t.CreatedAt == c.CreatedAt && t.ID < c.ID)
+	}
+	var items []domain.Tweet
+	switch {
+	case c == nil:
+		items = sorted
+	case c.Newer:
+		for _, t := range sorted {
+			if !older(t) && !(t.CreatedAt == c.CreatedAt && t.ID == c.ID) {
+				items = append(items, t)
+			}
+		}
+		if len(items) > limit {
+			return ports.Page[domain.Tweet]{Items: items[len(items)-limit:], HasMore: true}
+		}
+		return ports.Page[domain.Tweet]{Items: items}
+	default:
+		for _, t := range sorted {
+			if older(t) {
+				items = append(items, t)
+			}
+		}
+	}
+	if len(items) > limit {
+		return ports.Page[domain.Tweet]{Items: items[:limit], HasMore: true}
+	}
+	return ports.Page[domain.Tweet]{Items: items}
+}
 
 type memFollowRepo struct {
 	following  map[string][]string // follower -> followees
 	unfollowed [][2]string
 	errCreate  error
+	idLoads    int // llamadas a FollowersIDs
 }
 
 func (m *memFollowRepo) Create(ctx context.Context, f *domain.Follow) error {
@@ -57,11 +369,156 @@ func (m *memFollowRepo) Unfollow(ctx context.Context, followerID, followeeID str
 	m.unfollowed = append(m.unfollowed, [2]string{followerID, followeeID})
 	return nil
 }
+func (m *memFollowRepo) Exists(ctx context.Context, followerID, followeeID string) (bool, error) {
+	return slices.Contains(m.following[followerID], followeeID), nil
+}
 func (m *memFollowRepo) FollowingIDs(ctx context.Context, followerID string) ([]string, error) {
 	return m.following[followerID], nil
 }
+func (m *memFollowRepo) CountFollowers(ctx context.Context, followeeID string) (int64, error) {
+	var n int64
+	for _, followees := range m.following {
+		if slices.Contains(followees, followeeID) {
+			n++
+		}
+	}
+	return n, nil
+}
 func (m *memFollowRepo) FollowersIDs(ctx context.Context, followeeID string) ([]string, error) {
-	return nil, nil
+	m.idLoads++
+	var out []string
+	for follower, followees := range m.following {
+		for _, f := range followees {
+			if f == followeeID {
+				out = append(out, follower)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func (m *memFollowRepo) FollowersPage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.Follow], error) {
+	var out []domain.Follow
+	for follower, followees := range m.following {
+		if slices.Contains(followees, userID) {
+			out = append(out, domain.Follow{FollowerID: follower, FolloweeID: userID})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].FollowerID > out[j].FollowerID })
+	return memFollowPage(out, q), nil
+}
+func (m *memFollowRepo) FollowingPage(ctx context.Context, userID string, q ports.PageQuery) (ports.Page[domain.Follow], error) {
+	var out []domain.Follow
+	for _, f := range m.following[userID] {
+		out = append(out, domain.Follow{FollowerID: userID, FolloweeID: f})
+	}
+	return memFollowPage(out, q), nil
+}
+
+// memFollowPage sólo corta por límite; el orden lo da cada listado.
+func memFollowPage(all []domain.Follow, q ports.PageQuery) ports.Page[domain.Follow] {
+	if q.Limit > 0 && len(all) > q.Limit {
+		return ports.Page[domain.Follow]{Items: all[:q.Limit], HasMore: true}
+	}
+	return ports.Page[domain.Follow]{Items: all}
+}
+
+type memFollowRequestRepo map[[2]string]domain.FollowRequest // (requester, target)
+
+func (m memFollowRequestRepo) Create(ctx context.Context, r *domain.FollowRequest) error {
+	key := [2]string{r.RequesterID, r.TargetID}
+	if old, ok := m[key]; ok {
+		*r = old
+		return nil
+	}
+	m[key] = *r
+	return nil
+}
+func (m memFollowRequestRepo) Get(ctx context.Context, requesterID, targetID string) (domain.FollowRequest, error) {
+	r, ok := m[[2]string{requesterID, targetID}]
+	if !ok {
+		return domain.FollowRequest{}, domain.ErrFollowRequestNotFound
+	}
+	return r, nil
+}
+func (m memFollowRequestRepo) Delete(ctx context.Context, requesterID, targetID string) error {
+	delete(m, [2]string{requesterID, targetID})
+	return nil
+}
+func (m memFollowRequestRepo) Page(ctx context.Context, targetID string, q ports.PageQuery) (ports.Page[domain.FollowRequest], error) {
+	var out []domain.FollowRequest
+	for _, r := range m {
+		if r.TargetID == targetID {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RequesterID > out[j].RequesterID })
+	if q.Limit > 0 && len(out) > q.Limit {
+		return ports.Page[domain.FollowRequest]{Items: out[:q.Limit], HasMore: true}, nil
+	}
+	return ports.Page[domain.FollowRequest]{Items: out}, nil
+}
+
+type memHomeRepo struct {
+	entries map[string][]domain.Tweet // owner -> tweets
+	heavy   map[string]bool
+	follows *memFollowRepo
+}
+
+func newMemHomeRepo(fr *memFollowRepo) *memHomeRepo {
+	return &memHomeRepo{entries: map[string][]domain.Tweet{}, heavy: map[string]bool{}, follows: fr}
+}
+func (m *memHomeRepo) Push(ctx context.Context, ownerIDs []string, t domain.Tweet) error {
+	for _, o := range ownerIDs {
+		m.entries[o] = append(m.entries[o], t)
+	}
+	return nil
+}
+func (m *memHomeRepo) Backfill(ctx context.Context, ownerID string, tweets []domain.Tweet) error {
+	m.entries[ownerID] = append(m.entries[ownerID], tweets...)
+	return nil
+}
+func (m *memHomeRepo) PurgeAuthor(ctx context.Context, ownerID, authorID string) error {
+	kept := m.entries[ownerID][:0]
+	for _, t := range m.entries[ownerID] {
+		if t.UserID != authorID {
+			kept = append(kept, t)
+		}
+	}
+	m.entries[ownerID] = kept
+	return nil
+}
+func (m *memHomeRepo) RemoveTweet(ctx context.Context, tweetID string) error {
+	for owner, list := range m.entries {
+		kept := list[:0]
+		for _, t := range list {
+			if t.ID != tweetID {
+				kept = append(kept, t)
+			}
+		}
+		m.entries[owner] = kept
+	}
+	return nil
+}
+func (m *memHomeRepo) Page(ctx context.Context, ownerID string, q ports.PageQuery) (ports.Page[domain.Tweet], error) {
+	return memPage(m.entries[ownerID], q), nil
+}
+func (m *memHomeRepo) MarkHeavy(ctx context.Context, authorID string, markedAt int64) error {
+	m.heavy[authorID] = true
+	return nil
+}
+func (m *memHomeRepo) IsHeavy(ctx context.Context, authorID string) (bool, error) {
+	return m.heavy[authorID], nil
+}
+func (m *memHomeRepo) HeavyFollowees(ctx context.Context, followerID string) ([]string, error) {
+	var out []string
+	for _, f := range m.follows.following[followerID] {
+		if m.heavy[f] {
+			out = append(out, f)
+		}
+	}
+	return out, nil
 }
 
 // ===== tests =====
@@ -101,16 +558,77 @@ func TestGetTimeline_OnlyFollowing(t *testing.T) {
 	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2"}}}
 	uc := GetTimeline{Tweets: tr, Follows: fr}
 
-	got, err := uc.Exec(context.Background(), GetTimelineInput{UserID: "u1", Limit: 50, Offset: 0})
+	got, err := uc.Exec(context.Background(), GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 50, Offset: 0})
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
 	want := []domain.Tweet{{ID: "B", UserID: "u2", Text: "from u2", CreatedAt: 2}}
-	if !reflect.DeepEqual(got, want) {
+	if !reflect.DeepEqual(got.Items, want) {
 		t.Fatalf("got %#v want %#v", got, want)
 	}
 }
 
+func TestGetTimeline_OnlyOwnerOrAdmin(t *testing.T) {
+	users := newMemUsers("u1", "u2", "mod")
+	mod := users["mod"]
+	mod.Role = domain.RoleAdmin
+	users["mod"] = mod
+	uc := GetTimeline{Tweets: &memTweetRepo{}, Follows: &memFollowRepo{}, Users: users}
+
+	for actor, want := range map[string]error{"u1": nil, "mod": nil, "u2": domain.ErrForbidden, "": domain.ErrForbidden} {
+		_, err := uc.Exec(context.Background(), GetTimelineInput{UserID: "u1", ActorID: actor, Limit: 10})
+		if !errors.Is(err, want) {
+			t.Fatalf("actor %q: got %v want %v", actor, err, want)
+		}
+	}
+}
+
+func TestGetTimeline_CursorPagination(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{}}
+	for i, id := range []string{"A", "B", "C", "D", "E"} {
+		tr.byUser["u2"] = append(tr.byUser["u2"], domain.Tweet{ID: id, UserID: "u2", Text: id, CreatedAt: int64(i / 2)})
+	}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2"}}}
+	uc := GetTimeline{Tweets: tr, Follows: fr}
+	ctx := context.Background()
+
+	ids := func(p Page[domain.Tweet]) (out []string) {
+		for _, t := range p.Items {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+
+	p1, err := uc.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 2})
+	if err != nil {
+		t.Fatalf("page 1: %v", err)
+	}
+	if got := ids(p1); !reflect.DeepEqual(got, []string{"E", "D"}) || p1.NextCursor == "" {
+		t.Fatalf("page 1 = %v next=%q", got, p1.NextCursor)
+	}
+	p2, err := uc.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 2, Cursor: p1.NextCursor})
+	if err != nil {
+		t.Fatalf("page 2: %v", err)
+	}
+	if got := ids(p2); !reflect.DeepEqual(got, []string{"C", "B"}) {
+		t.Fatalf("page 2 = %v", got)
+	}
+	p3, _ := uc.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 2, Cursor: p2.NextCursor})
+	if got := ids(p3); !reflect.DeepEqual(got, []string{"A"}) || p3.NextCursor != "" {
+		t.Fatalf("page 3 = %v next=%q", got, p3.NextCursor)
+	}
+
+	// volver hacia atrás desde la página 2
+	back, _ := uc.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 2, Cursor: p2.PrevCursor})
+	if got := ids(back); !reflect.DeepEqual(got, []string{"E", "D"}) {
+		t.Fatalf("prev page = %v", got)
+	}
+
+	if _, err := uc.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1", Cursor: "nope"}); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Fatalf("want ErrInvalidCursor, got %v", err)
+	}
+}
+
 func TestFollowUser_OK(t *testing.T) {
 	fr := &memFollowRepo{following: map[string][]string{}}
 	uc := FollowUser{Follows: fr, Clock: fakeClock{now: 50}, IDGen: fakeID{id: "F1"}}
@@ -150,6 +668,1228 @@ func TestUnfollowUser_OK(t *testing.T) {
 	}
 }
 
-// Interface assertions (por si cambiamos firmas sin querer)
-var _ ports.TweetRepo = (*memTweetRepo)(nil)
-var _ ports.FollowRepo = (*memFollowRepo)(nil)
+func TestPostTweet_FanoutOnWrite(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{}}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2"}, "u3": {"u2"}}}
+	home := newMemHomeRepo(fr)
+	uc := PostTweet{Tweets: tr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "T1"}, Follows: fr, Home: home}
+
+	if _, err := uc.Exec(context.Background(), PostTweetInput{UserID: "u2", Text: "hola"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(home.entries["u1"]) != 1 || len(home.entries["u3"]) != 1 || len(home.entries["u2"]) != 0 {
+		t.Fatalf("unexpected fan-out: %#v", home.entries)
+	}
+}
+
+func TestPostTweet_HeavyAuthorSkipsFanout(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{}}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"star"}, "u3": {"star"}}}
+	home := newMemHomeRepo(fr)
+	uc := PostTweet{Tweets: tr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "T1"}, Follows: fr, Home: home, FanoutMaxFollowers: 1}
+
+	if _, err := uc.Exec(context.Background(), PostTweetInput{UserID: "star", Text: "hola"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if !home.heavy["star"] || len(home.entries) != 0 || fr.idLoads != 0 {
+		t.Fatalf("expected star marked heavy without loading followers, heavy=%v entries=%v loads=%d", home.heavy, home.entries, fr.idLoads)
+	}
+
+	// el timeline híbrido igual lo muestra vía fan-out on read
+	got, err := GetTimeline{Tweets: tr, Follows: fr, Home: home}.Exec(context.Background(), GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 10})
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	if len(got.Items) != 1 || got.Items[0].UserID != "star" {
+		t.Fatalf("expected heavy author's tweet on read, got %#v", got.Items)
+	}
+}
+
+func TestGetTimeline_MaterializedMergesHeavyWithoutDuplicates(t *testing.T) {
+	a := domain.Tweet{ID: "A", UserID: "u2", Text: "a", CreatedAt: 1}
+	b := domain.Tweet{ID: "B", UserID: "star", Text: "b", CreatedAt: 2}
+	c := domain.Tweet{ID: "C", UserID: "star", Text: "c", CreatedAt: 3}
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"u2": {a}, "star": {b, c}}}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2", "star"}}}
+	home := newMemHomeRepo(fr)
+	// B se materializó antes de que star pasara a heavy
+	home.entries["u1"] = []domain.Tweet{a, b}
+	home.heavy["star"] = true
+
+	got, err := GetTimeline{Tweets: tr, Follows: fr, Home: home}.Exec(context.Background(), GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 2})
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	if len(got.Items) != 2 || got.Items[0].ID != "C" || got.Items[1].ID != "B" || got.NextCursor == "" {
+		t.Fatalf("unexpected page: %#v", got)
+	}
+	next, _ := GetTimeline{Tweets: tr, Follows: fr, Home: home}.Exec(context.Background(), GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 2, Cursor: got.NextCursor})
+	if len(next.Items) != 1 || next.Items[0].ID != "A" {
+		t.Fatalf("unexpected second page: %#v", next.Items)
+	}
+}
+
+func TestFollowUser_BackfillsAndUnfollowPurges(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"u2": {{ID: "A", UserID: "u2", Text: "a", CreatedAt: 1}}}}
+	fr := &memFollowRepo{following: map[string][]string{}}
+	home := newMemHomeRepo(fr)
+
+	follow := FollowUser{Follows: fr, Clock: fakeClock{now: 5}, IDGen: fakeID{id: "F1"}, Tweets: tr, Home: home}
+	if _, err := follow.Exec(context.Background(), FollowUserInput{FollowerID: "u1", FolloweeID: "u2"}); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+	if len(home.entries["u1"]) != 1 {
+		t.Fatalf("expected backfill, got %#v", home.entries)
+	}
+
+	unfollow := UnfollowUser{Follows: fr, Home: home}
+	if err := unfollow.Exec(context.Background(), UnfollowUserInput{FollowerID: "u1", FolloweeID: "u2"}); err != nil {
+		t.Fatalf("unfollow: %v", err)
+	}
+	if len(home.entries["u1"]) != 0 {
+		t.Fatalf("expected purge, got %#v", home.entries["u1"])
+	}
+}
+
+func TestGetUserTweets_OwnTweetsOnly(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"u1": {{ID: "A", UserID: "u1", Text: "mine", CreatedAt: 1}, {ID: "C", UserID: "u1", Text: "mine 2", CreatedAt: 3}},
+		"u2": {{ID: "B", UserID: "u2", Text: "other", CreatedAt: 2}},
+	}}
+	got, err := GetUserTweets{Tweets: tr}.Exec(context.Background(), GetUserTweetsInput{UserID: "u1", Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(got.Items) != 1 || got.Items[0].ID != "C" || got.NextCursor == "" {
+		t.Fatalf("unexpected page: %#v", got)
+	}
+	got, _ = GetUserTweets{Tweets: tr}.Exec(context.Background(), GetUserTweetsInput{UserID: "u1", Limit: 1, Cursor: got.NextCursor})
+	if len(got.Items) != 1 || got.Items[0].ID != "A" || got.NextCursor != "" {
+		t.Fatalf("unexpected second page: %#v", got)
+	}
+}
+
+func TestGetTimeline_IncludeSelf(t *testing.T) {
+	mine := domain.Tweet{ID: "A", UserID: "u1", Text: "mine", CreatedAt: 1}
+	theirs := domain.Tweet{ID: "B", UserID: "u2", Text: "from u2", CreatedAt: 2}
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"u1": {mine}, "u2": {theirs}}}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2"}}}
+	home := newMemHomeRepo(fr)
+	home.entries["u1"] = []domain.Tweet{theirs}
+
+	for name, uc := range map[string]GetTimeline{
+		"on read":      {Tweets: tr, Follows: fr},
+		"materialized": {Tweets: tr, Follows: fr, Home: home},
+	} {
+		got, err := uc.Exec(context.Background(), GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 10, IncludeSelf: true})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(got.Items) != 2 || got.Items[0].ID != "B" || got.Items[1].ID != "A" {
+			t.Fatalf("%s: unexpected items %#v", name, got.Items)
+		}
+	}
+}
+
+func TestDeleteTweet(t *testing.T) {
+	tw := domain.Tweet{ID: "A", UserID: "u2", Text: "oops", CreatedAt: 1}
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"u2": {tw}}}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2"}}}
+	home := newMemHomeRepo(fr)
+	home.entries["u1"] = []domain.Tweet{tw}
+	uc := DeleteTweet{Tweets: tr, Home: home, Clock: fakeClock{now: 9}}
+	ctx := context.Background()
+
+	if err := uc.Exec(ctx, DeleteTweetInput{TweetID: "A", UserID: "u1"}); !errors.Is(err, domain.ErrNotAuthor) {
+		t.Fatalf("want ErrNotAuthor, got %v", err)
+	}
+	if err := uc.Exec(ctx, DeleteTweetInput{TweetID: "A", UserID: "u2"}); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got := tr.byUser["u2"][0]; got.DeletedAt != 9 || got.Text != "" {
+		t.Fatalf("expected tombstone, got %#v", got)
+	}
+	if len(home.entries["u1"]) != 0 {
+		t.Fatalf("expected purge from materialized timeline, got %#v", home.entries["u1"])
+	}
+	if err := uc.Exec(ctx, DeleteTweetInput{TweetID: "A", UserID: "u2"}); !errors.Is(err, domain.ErrTweetNotFound) {
+		t.Fatalf("want ErrTweetNotFound on second delete, got %v", err)
+	}
+}
+
+func TestEditTweet(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"u1": {{ID: "A", UserID: "u1", Text: "hloa", CreatedAt: 100}}}}
+	uc := EditTweet{Tweets: tr, Clock: fakeClock{now: 150}, IDGen: fakeID{id: "R1"}, EditWindowSec: 60}
+	ctx := context.Background()
+
+	got, err := uc.Exec(ctx, EditTweetInput{TweetID: "A", UserID: "u1", Text: "hola"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if got.Text != "hola" || got.EditedAt != 150 || tr.byUser["u1"][0].Text != "hola" {
+		t.Fatalf("unexpected edit: %#v", got)
+	}
+	revs, _ := GetTweetRevisions{Tweets: tr}.Exec(ctx, GetTweetRevisionsInput{TweetID: "A"})
+	if len(revs) != 1 || revs[0].Text != "hloa" || revs[0].Revision != 1 {
+		t.Fatalf("unexpected revisions: %#v", revs)
+	}
+
+	if _, err := uc.Exec(ctx, EditTweetInput{TweetID: "A", UserID: "u2", Text: "x"}); !errors.Is(err, domain.ErrNotAuthor) {
+		t.Fatalf("want ErrNotAuthor, got %v", err)
+	}
+	late := EditTweet{Tweets: tr, Clock: fakeClock{now: 161}, IDGen: fakeID{id: "R2"}, EditWindowSec: 60}
+	if _, err := late.Exec(ctx, EditTweetInput{TweetID: "A", UserID: "u1", Text: "tarde"}); !errors.Is(err, domain.ErrEditWindowExpired) {
+		t.Fatalf("want ErrEditWindowExpired, got %v", err)
+	}
+}
+
+func TestPostTweet_ReplyAndThread(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"u1": {{ID: "A", UserID: "u1", Text: "raíz", CreatedAt: 1, ConversationID: "A"}},
+	}}
+	ctx := context.Background()
+	reply := func(id, parent string) domain.Tweet {
+		t.Helper()
+		uc := PostTweet{Tweets: tr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: id}}
+		tw, err := uc.Exec(ctx, PostTweetInput{UserID: "u2", Text: "re", InReplyToID: parent})
+		if err != nil {
+			t.Fatalf("reply %s: %v", id, err)
+		}
+		return tw
+	}
+	b := reply("B", "A")
+	reply("C", "B")
+	reply("D", "A")
+	if b.InReplyToID != "A" || b.ConversationID != "A" {
+		t.Fatalf("unexpected reply: %#v", b)
+	}
+
+	uc := PostTweet{Tweets: tr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "X"}}
+	if _, err := uc.Exec(ctx, PostTweetInput{UserID: "u2", Text: "re", InReplyToID: "nope"}); !errors.Is(err, domain.ErrParentNotFound) {
+		t.Fatalf("want ErrParentNotFound, got %v", err)
+	}
+
+	page, err := GetThread{Tweets: tr}.Exec(ctx, GetThreadInput{TweetID: "C"})
+	if err != nil {
+		t.Fatalf("thread: %v", err)
+	}
+	var got []string
+	for _, e := range page.Items {
+		got = append(got, fmt.Sprintf("%s:%d", e.ID, e.Depth))
+	}
+	if strings.Join(got, ",") != "A:0,B:1,C:2,D:1" {
+		t.Fatalf("unexpected thread order: %v", got)
+	}
+}
+
+func TestRetweet_IdempotentUndoAndTimeline(t *testing.T) {
+	orig := domain.Tweet{ID: "A", UserID: "author", Text: "original", CreatedAt: 1, ConversationID: "A"}
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"author": {orig}}}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2", "u3"}}}
+	ctx := context.Background()
+	rt := func(id string, now int64) Retweet {
+		return Retweet{Tweets: tr, Clock: fakeClock{now: now}, IDGen: fakeID{id: id}}
+	}
+
+	first, created, err := rt("R1", 5).Exec(ctx, RetweetInput{TweetID: "A", UserID: "u2"})
+	if err != nil || !created || first.RetweetOfID != "A" {
+		t.Fatalf("retweet: %#v created=%v err=%v", first, created, err)
+	}
+	again, created, err := rt("R9", 6).Exec(ctx, RetweetInput{TweetID: "A", UserID: "u2"})
+	if err != nil || created || again.ID != "R1" {
+		t.Fatalf("second retweet should return R1: %#v created=%v err=%v", again, created, err)
+	}
+	// retwittear el retweet apunta al original
+	if r, _, _ := rt("R2", 7).Exec(ctx, RetweetInput{TweetID: "R1", UserID: "u3"}); r.RetweetOfID != "A" {
+		t.Fatalf("retweet of retweet should target original: %#v", r)
+	}
+
+	page, err := GetTimeline{Tweets: tr, Follows: fr}.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1"})
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	if len(page.Items) != 1 {
+		t.Fatalf("expected a single collapsed item, got %#v", page.Items)
+	}
+	item := page.Items[0]
+	if item.Retweeted == nil || item.Retweeted.UserID != "author" || !reflect.DeepEqual(item.RetweetedBy, []string{"u3", "u2"}) {
+		t.Fatalf("unexpected rendered retweet: %#v", item)
+	}
+
+	undo := Unretweet{Tweets: tr, Clock: fakeClock{now: 8}}
+	if err := undo.Exec(ctx, RetweetInput{TweetID: "A", UserID: "u2"}); err != nil {
+		t.Fatalf("unretweet: %v", err)
+	}
+	if err := undo.Exec(ctx, RetweetInput{TweetID: "A", UserID: "u2"}); err != nil {
+		t.Fatalf("second unretweet should be a no-op: %v", err)
+	}
+	if _, created, _ := rt("R3", 9).Exec(ctx, RetweetInput{TweetID: "A", UserID: "u2"}); !created {
+		t.Fatal("expected a new retweet after undo")
+	}
+}
+
+func TestPostTweet_Quote(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"u1": {{ID: "A", UserID: "u1", Text: "x", CreatedAt: 1}}}}
+	uc := PostTweet{Tweets: tr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "Q"}}
+	got, err := uc.Exec(context.Background(), PostTweetInput{UserID: "u2", Text: "mirá esto", QuotedTweetID: "A"})
+	if err != nil || got.QuotedTweetID != "A" || got.Text != "mirá esto" {
+		t.Fatalf("quote: %#v err=%v", got, err)
+	}
+	if _, err := uc.Exec(context.Background(), PostTweetInput{UserID: "u2", Text: "x", QuotedTweetID: "nope"}); !errors.Is(err, domain.ErrQuotedNotFound) {
+		t.Fatalf("want ErrQuotedNotFound, got %v", err)
+	}
+}
+
+func TestLikeTweet_IdempotentAndRetweetTarget(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"u1": {{ID: "A", UserID: "u1", Text: "x", CreatedAt: 1}},
+		"u2": {{ID: "R", UserID: "u2", CreatedAt: 2, RetweetOfID: "A"}},
+	}}
+	lr := &memLikeRepo{}
+	uc := LikeTweet{Tweets: tr, Likes: lr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "L1"}}
+	ctx := context.Background()
+
+	for _, id := range []string{"A", "A", "R"} {
+		l, err := uc.Exec(ctx, LikeTweetInput{TweetID: id, UserID: "u3"})
+		if err != nil || l.TweetID != "A" {
+			t.Fatalf("like %s: %#v err=%v", id, l, err)
+		}
+	}
+	for _, id := range []string{"A", "R"} {
+		page, err := GetTweetLikes{Tweets: tr, Likes: lr}.Exec(ctx, GetTweetLikesInput{TweetID: id})
+		if err != nil || len(page.Items) != 1 || page.Items[0].UserID != "u3" {
+			t.Fatalf("likers of %s = %#v err=%v", id, page.Items, err)
+		}
+	}
+	if _, err := uc.Exec(ctx, LikeTweetInput{TweetID: "nope", UserID: "u3"}); !errors.Is(err, domain.ErrTweetNotFound) {
+		t.Fatalf("want ErrTweetNotFound, got %v", err)
+	}
+
+	// quitar el like por el retweet lo quita del original
+	unlike := UnlikeTweet{Tweets: tr, Likes: lr}
+	for _, id := range []string{"R", "A", "nope"} {
+		if err := unlike.Exec(ctx, LikeTweetInput{TweetID: id, UserID: "u3"}); err != nil {
+			t.Fatalf("unlike %s: %v", id, err)
+		}
+		if len(lr.likes) != 0 {
+			t.Fatalf("unlike %s: expected no likes, got %#v", id, lr.likes)
+		}
+	}
+}
+
+func TestGetHashtagTweets(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"u1": {{ID: "A", UserID: "u1", Text: "arranca #Campaña2024", CreatedAt: 1}, {ID: "B", UserID: "u1", Text: "nada", CreatedAt: 2}},
+		"u2": {{ID: "C", UserID: "u2", Text: "yo también #campaña2024", CreatedAt: 3}},
+	}}
+	uc := GetHashtagTweets{Tweets: tr}
+	page, err := uc.Exec(context.Background(), GetHashtagTweetsInput{Tag: "#CAMPAÑA2024"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != "C" || page.Items[1].ID != "A" {
+		t.Fatalf("unexpected tweets: %#v", page.Items)
+	}
+	if _, err := uc.Exec(context.Background(), GetHashtagTweetsInput{Tag: "123"}); !errors.Is(err, domain.ErrInvalidHashtag) {
+		t.Fatalf("want ErrInvalidHashtag, got %v", err)
+	}
+}
+
+func TestPostTweet_MentionsOnlyKnownUsers(t *testing.T) {
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{}}
+	users := newMemUsers("u1")
+	users["U2"] = domain.User{ID: "U2", Handle: "Ana"}
+	uc := PostTweet{Tweets: tr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "T1"}, Users: users}
+
+	got, err := uc.Exec(context.Background(), PostTweetInput{UserID: "u1", Text: "hola @ana y @anna"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	want := []domain.Mention{{UserID: "U2", Handle: "ana", ByteStart: 5, ByteEnd: 9, CharStart: 5, CharEnd: 9}}
+	if !reflect.DeepEqual(got.Mentions, want) || got.Text != "hola @ana y @anna" {
+		t.Fatalf("unexpected mentions: %#v", got.Mentions)
+	}
+
+	page, err := GetUserMentions{Tweets: tr}.Exec(context.Background(), GetUserMentionsInput{UserID: "U2"})
+	if err != nil || len(page.Items) != 1 || page.Items[0].ID != "T1" {
+		t.Fatalf("mentions of ana = %#v err=%v", page.Items, err)
+	}
+	if page, _ := (GetUserMentions{Tweets: tr}).Exec(context.Background(), GetUserMentionsInput{UserID: "anna"}); len(page.Items) != 0 {
+		t.Fatalf("typo should not create a mention: %#v", page.Items)
+	}
+}
+
+func TestPostTweetAndFollow_RejectUnknownUsers(t *testing.T) {
+	users := newMemUsers("u1", "u2")
+	post := PostTweet{Tweets: &memTweetRepo{byUser: map[string][]domain.Tweet{}}, Clock: fakeClock{now: 1}, IDGen: fakeID{id: "T1"}, Users: users}
+	if _, err := post.Exec(context.Background(), PostTweetInput{UserID: "nadie", Text: "hola"}); !errors.Is(err, domain.ErrUnknownUser) {
+		t.Fatalf("post: want ErrUnknownUser, got %v", err)
+	}
+	follow := FollowUser{Follows: &memFollowRepo{following: map[string][]string{}}, Clock: fakeClock{now: 1}, IDGen: fakeID{id: "F1"}, Users: users}
+	if _, err := follow.Exec(context.Background(), FollowUserInput{FollowerID: "u1", FolloweeID: "nadie"}); !errors.Is(err, domain.ErrUnknownUser) {
+		t.Fatalf("follow: want ErrUnknownUser, got %v", err)
+	}
+	if _, err := follow.Exec(context.Background(), FollowUserInput{FollowerID: "u1", FolloweeID: "u2"}); err != nil {
+		t.Fatalf("follow: unexpected err: %v", err)
+	}
+}
+
+func TestCreateAndUpdateUser(t *testing.T) {
+	users := memUserRepo{}
+	create := CreateUser{Users: users, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "U1"}}
+	u, key, err := create.Exec(context.Background(), CreateUserInput{Handle: "ana", Bio: "hola"})
+	if err != nil || u.ID != "U1" || u.DisplayName != "ana" || u.CreatedAt != 10 || key != nil {
+		t.Fatalf("create = %#v key=%v err=%v", u, key, err)
+	}
+	create.IDGen = fakeID{id: "U2"}
+	if _, _, err := create.Exec(context.Background(), CreateUserInput{Handle: "ANA"}); !errors.Is(err, domain.ErrHandleTaken) {
+		t.Fatalf("want ErrHandleTaken, got %v", err)
+	}
+
+	update := UpdateUser{Users: users, Clock: fakeClock{now: 20}}
+	bio := "chau"
+	u, err = update.Exec(context.Background(), UpdateUserInput{UserID: "U1", ActorID: "U1", Patch: domain.UserPatch{Bio: &bio}})
+	if err != nil || u.Bio != "chau" || u.UpdatedAt != 20 || users["U1"].Bio != "chau" {
+		t.Fatalf("update = %#v err=%v", u, err)
+	}
+	if _, err := update.Exec(context.Background(), UpdateUserInput{UserID: "U1", ActorID: "U2", Patch: domain.UserPatch{Bio: &bio}}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("want ErrForbidden, got %v", err)
+	}
+	if _, err := update.Exec(context.Background(), UpdateUserInput{UserID: "nadie", ActorID: "nadie"}); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("want ErrUserNotFound, got %v", err)
+	}
+}
+
+// memSearcher filtra por substring y ordena por (created_at, id) DESC.
+type memSearcher struct {
+	tweets []domain.Tweet
+	got    domain.SearchQuery
+}
+
+func (m *memSearcher) Search(_ context.Context, q domain.SearchQuery, p ports.PageQuery) (ports.Page[domain.SearchHit], error) {
+	m.got = q
+	var out []domain.SearchHit
+	for _, t := range m.tweets {
+		match := q.From == "" || t.UserID == q.From
+		for _, term := range append(append([]string{}, q.Terms...), q.Phrases...) {
+			match = match && strings.Contains(t.Text, term)
+		}
+		if match && (p.Cursor == nil || t.CreatedAt < p.Cursor.CreatedAt) {
+			out = append(out, domain.SearchHit{Tweet: t, Score: t.CreatedAt})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt > out[j].CreatedAt })
+	limit := ports.PageLimit(p.Limit)
+	if len(out) > limit {
+		return ports.Page[domain.SearchHit]{Items: out[:limit], HasMore: true}, nil
+	}
+	return ports.Page[domain.SearchHit]{Items: out}, nil
+}
+
+func TestSearchTweets(t *testing.T) {
+	s := &memSearcher{tweets: []domain.Tweet{
+		{ID: "A", UserID: "u1", Text: "buen café", CreatedAt: 1},
+		{ID: "B", UserID: "u2", Text: "café frío", CreatedAt: 2},
+		{ID: "C", UserID: "u1", Text: "café con leche", CreatedAt: 3},
+	}}
+	uc := SearchTweets{Search: s, Users: newMemUsers("u1", "u2")}
+	page, err := uc.Exec(context.Background(), SearchTweetsInput{Query: "café from:U1", Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != "C" || page.NextCursor == "" || s.got.From != "u1" {
+		t.Fatalf("unexpected page: %#v", page)
+	}
+	page, err = uc.Exec(context.Background(), SearchTweetsInput{Query: "café from:u1", Limit: 1, Cursor: page.NextCursor})
+	if err != nil || len(page.Items) != 1 || page.Items[0].ID != "A" {
+		t.Fatalf("second page = %#v err=%v", page.Items, err)
+	}
+	if page, err := uc.Exec(context.Background(), SearchTweetsInput{Query: "café from:nadie"}); err != nil || len(page.Items) != 0 {
+		t.Fatalf("unknown handle = %#v err=%v", page.Items, err)
+	}
+	for _, in := range []SearchTweetsInput{{Query: "  "}, {Query: "café", Order: "random"}, {Query: `"sin cerrar`}} {
+		if _, err := uc.Exec(context.Background(), in); !errors.Is(err, domain.ErrInvalidSearchQuery) {
+			t.Fatalf("%+v: want ErrInvalidSearchQuery, got %v", in, err)
+		}
+	}
+}
+
+func TestSuspendUser_BlocksPostingAndHidesFromTimeline(t *testing.T) {
+	ctx := context.Background()
+	users := newMemUsers("u1", "u2", "u3", "spam")
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"u2":   {{ID: "A", UserID: "u2", Text: "ok", CreatedAt: 1}},
+		"spam": {{ID: "S", UserID: "spam", Text: "compra ya", CreatedAt: 2}},
+		"u3": {
+			{ID: "R1", UserID: "u3", RetweetOfID: "S", CreatedAt: 3},
+			{ID: "R2", UserID: "u3", RetweetOfID: "A", CreatedAt: 4},
+		},
+	}}
+	tr.byUser["spam"] = append(tr.byUser["spam"], domain.Tweet{ID: "R3", UserID: "spam", RetweetOfID: "A", CreatedAt: 5})
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2", "u3", "spam"}}}
+	timeline := GetTimeline{Tweets: tr, Follows: fr, Users: users}
+
+	suspend := SuspendUser{Users: users, Clock: fakeClock{now: 50}}
+	u, err := suspend.Exec(ctx, SuspendUserInput{UserID: "spam", Suspended: true})
+	if err != nil || u.SuspendedAt != 50 {
+		t.Fatalf("suspend: %#v err=%v", u, err)
+	}
+	if u, _ := (SuspendUser{Users: users, Clock: fakeClock{now: 60}}).Exec(ctx, SuspendUserInput{UserID: "spam", Suspended: true}); u.SuspendedAt != 50 {
+		t.Fatalf("second suspend must keep the original date: %#v", u)
+	}
+	if _, err := suspend.Exec(ctx, SuspendUserInput{UserID: "nadie", Suspended: true}); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("want ErrUserNotFound, got %v", err)
+	}
+
+	post := PostTweet{Tweets: tr, Clock: fakeClock{now: 70}, IDGen: fakeID{id: "T1"}, Users: users}
+	if _, err := post.Exec(ctx, PostTweetInput{UserID: "spam", Text: "sigo"}); !errors.Is(err, domain.ErrSuspended) {
+		t.Fatalf("post: want ErrSuspended, got %v", err)
+	}
+
+	// Ni sus tweets, ni sus retweets, ni los retweets de sus tweets.
+	page, err := timeline.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 10})
+	if err != nil {
+		t.Fatalf("timeline: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != "R2" || !reflect.DeepEqual(page.Items[0].RetweetedBy, []string{"u3"}) {
+		t.Fatalf("unexpected timeline: %#v", page.Items)
+	}
+
+	if _, err := suspend.Exec(ctx, SuspendUserInput{UserID: "spam", Suspended: false}); err != nil {
+		t.Fatalf("unsuspend: %v", err)
+	}
+	if _, err := post.Exec(ctx, PostTweetInput{UserID: "spam", Text: "volví"}); err != nil {
+		t.Fatalf("post after unsuspend: %v", err)
+	}
+	if page, _ := timeline.Exec(ctx, GetTimelineInput{UserID: "u1", ActorID: "u1", Limit: 10}); len(page.Items) != 3 {
+		t.Fatalf("expected spam back in the timeline, got %#v", page.Items)
+	}
+}
+
+func TestHardDeleteTweet(t *testing.T) {
+	ctx := context.Background()
+	tw := domain.Tweet{ID: "A", UserID: "u2", Text: "abuso", CreatedAt: 1}
+	rt := domain.Tweet{ID: "R1", UserID: "u3", RetweetOfID: "A", CreatedAt: 2}
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{"u2": {tw}, "u3": {rt}}}
+	fr := &memFollowRepo{following: map[string][]string{"u1": {"u2", "u3"}}}
+	home := newMemHomeRepo(fr)
+	home.entries["u1"] = []domain.Tweet{tw, rt}
+	uc := HardDeleteTweet{Tweets: tr, Home: home}
+
+	if err := uc.Exec(ctx, "A"); err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if len(tr.byUser["u2"]) != 0 || len(tr.byUser["u3"]) != 0 {
+		t.Fatalf("expected tweet and retweet gone, got %#v", tr.byUser)
+	}
+	if len(home.entries["u1"]) != 0 {
+		t.Fatalf("expected purge from materialized timeline, got %#v", home.entries["u1"])
+	}
+	if err := uc.Exec(ctx, "A"); !errors.Is(err, domain.ErrTweetNotFound) {
+		t.Fatalf("want ErrTweetNotFound, got %v", err)
+	}
+}
+
+func TestAdminRolesAndFollows(t *testing.T) {
+	ctx := context.Background()
+	users := newMemUsers("ana", "beto")
+	set := SetUserRole{Users: users}
+	if u, err := set.Exec(ctx, SetUserRoleInput{Handle: "ANA", Role: domain.RoleAdmin}); err != nil || !u.IsAdmin() || !users["ana"].IsAdmin() {
+		t.Fatalf("grant: %#v err=%v", u, err)
+	}
+	if _, err := set.Exec(ctx, SetUserRoleInput{Handle: "ana", Role: "root"}); !errors.Is(err, domain.ErrInvalidRole) {
+		t.Fatalf("want ErrInvalidRole, got %v", err)
+	}
+	if _, err := set.Exec(ctx, SetUserRoleInput{Handle: "nadie", Role: domain.RoleAdmin}); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("want ErrUserNotFound, got %v", err)
+	}
+
+	follows := GetUserFollows{Users: users, Follows: &memFollowRepo{following: map[string][]string{"ana": {"beto"}}}}
+	in := GetUserFollowsInput{FollowListInput: FollowListInput{UserID: "beto", Limit: 1}}
+	if got, err := follows.Exec(ctx, in); err != nil || len(got.Items) != 0 {
+		t.Fatalf("following: %#v err=%v", got, err)
+	}
+	in.Followers = true
+	if got, err := follows.Exec(ctx, in); err != nil || len(got.Items) != 1 || got.Items[0].FollowerID != "ana" {
+		t.Fatalf("followers: %#v err=%v", got, err)
+	}
+	in.UserID = "nadie"
+	if _, err := follows.Exec(ctx, in); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("want ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestGetFollowersAndFollowing(t *testing.T) {
+	ctx := context.Background()
+	users := newMemUsers("ana", "beto", "caro")
+	fr := &memFollowRepo{following: map[string][]string{"beto": {"ana"}, "caro": {"ana", "beto"}}}
+
+	followers, err := GetFollowers{Users: users, Follows: fr}.Exec(ctx, FollowListInput{UserID: "ana"})
+	if err != nil || len(followers.Items) != 2 || followers.Items[0].FollowerID != "caro" {
+		t.Fatalf("followers = %#v err=%v", followers, err)
+	}
+	following, err := GetFollowing{Users: users, Follows: fr}.Exec(ctx, FollowListInput{UserID: "caro", Limit: 1})
+	if err != nil || len(following.Items) != 1 || following.NextCursor == "" {
+		t.Fatalf("following = %#v err=%v", following, err)
+	}
+	if _, err := (GetFollowing{Users: users, Follows: fr}).Exec(ctx, FollowListInput{UserID: "nadie"}); !errors.Is(err, domain.ErrUserNotFound) {
+		t.Fatalf("want ErrUserNotFound, got %v", err)
+	}
+	if _, err := (GetFollowers{Users: users, Follows: fr}).Exec(ctx, FollowListInput{UserID: "ana", Cursor: "basura"}); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Fatalf("want ErrInvalidCursor, got %v", err)
+	}
+}
+
+type memBlockRepo struct{ blocks []domain.Block }
+
+func (m *memBlockRepo) Create(_ context.Context, b *domain.Block) error {
+	if ok, _ := m.Between(context.Background(), b.BlockerID, b.BlockedID); !ok {
+		m.blocks = append(m.blocks, *b)
+	}
+	return nil
+}
+func (m *memBlockRepo) Delete(_ context.Context, blockerID, blockedID string) error {
+	m.blocks = slices.DeleteFunc(m.blocks, func(b domain.Block) bool {
+		return b.BlockerID == blockerID && b.BlockedID == blockedID
+	})
+	return nil
+}
+func (m *memBlockRepo) Between(_ context.Context, a, b string) (bool, error) {
+	return slices.ContainsFunc(m.blocks, func(x domain.Block) bool {
+		return (x.BlockerID == a && x.BlockedID == b) || (x.BlockerID == b && x.BlockedID == a)
+	}), nil
+}
+func (m *memBlockRepo) BlockerIDs(_ context.Context, userID string) ([]string, error) {
+	var out []string
+	for _, b := range m.blocks {
+		if b.BlockedID == userID {
+			out = append(out, b.BlockerID)
+		}
+	}
+	return out, nil
+}
+func (m *memBlockRepo) Page(_ context.Context, blockerID string, q ports.PageQuery) (ports.Page[domain.Block], error) {
+	var out []domain.Block
+	for _, b := range m.blocks {
+		if b.BlockerID == blockerID {
+			out = append(out, b)
+		}
+	}
+	return ports.Page[domain.Block]{Items: out}, nil
+}
+
+func TestBlockUser(t *testing.T) {
+	ctx := context.Background()
+	users := newMemUsers("ana", "troll", "caro")
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"ana":  {{ID: "A", UserID: "ana", Text: "hola", CreatedAt: 1}},
+		"caro": {{ID: "R1", UserID: "caro", RetweetOfID: "A", CreatedAt: 2}, {ID: "C", UserID: "caro", Text: "ok", CreatedAt: 3}},
+	}}
+	fr := &memFollowRepo{following: map[string][]string{"troll": {"ana", "caro"}, "ana": {"troll"}}}
+	home := newMemHomeRepo(fr)
+	home.entries["troll"] = []domain.Tweet{tr.byUser["ana"][0]}
+	br := &memBlockRepo{}
+	block := BlockUser{Blocks: br, Follows: fr, Clock: fakeClock{now: 10}, IDGen: fakeID{id: "B1"}, Users: users, Home: home}
+
+	b, err := block.Exec(ctx, BlockUserInput{BlockerID: "ana", BlockedID: "troll"})
+	if err != nil || b.BlockedID != "troll" || b.CreatedAt != 10 {
+		t.Fatalf("block: %#v err=%v", b, err)
+	}
+	if want := [][2]string{{"ana", "troll"}, {"troll", "ana"}}; !reflect.DeepEqual(fr.unfollowed, want) {
+		t.Fatalf("unfollowed = %v, want %v", fr.unfollowed, want)
+	}
+	if len(home.entries["troll"]) != 0 {
+		t.Fatalf("expected troll's home purged, got %#v", home.entries["troll"])
+	}
+	if _, err := block.Exec(ctx, BlockUserInput{BlockerID: "ana", BlockedID: "nadie"}); !errors.Is(err, domain.ErrUnknownUser) {
+		t.Fatalf("want ErrUnknownUser, got %v", err)
+	}
+
+	// Ninguno de los dos puede volver a seguir al otro.
+	follow := FollowUser{Follows: fr, Clock: fakeClock{now: 11}, IDGen: fakeID{id: "F1"}, Users: users, Blocks: br}
+	for _, in := range []FollowUserInput{{FollowerID: "troll", FolloweeID: "ana"}, {FollowerID: "ana", FolloweeID: "troll"}} {
+		if _, err := follow.Exec(ctx, in); !errors.Is(err, domain.ErrBlocked) {
+			t.Fatalf("follow %v: want ErrBlocked, got %v", in, err)
+		}
+	}
+	// Ni responderle, retwittearla ni citarla, tampoco a través del retweet de caro.
+	retweet := Retweet{Tweets: tr, Clock: fakeClock{now: 11}, IDGen: fakeID{id: "RT"}, Blocks: br}
+	post := PostTweet{Tweets: tr, Clock: fakeClock{now: 11}, IDGen: fakeID{id: "Q"}, Blocks: br}
+	if _, err := post.Exec(ctx, PostTweetInput{UserID: "troll", Text: "jaja", InReplyToID: "A"}); !errors.Is(err, domain.ErrBlocked) {
+		t.Fatalf("reply: want ErrBlocked, got %v", err)
+	}
+	for _, id := range []string{"A", "R1"} {
+		if _, _, err := retweet.Exec(ctx, RetweetInput{TweetID: id, UserID: "troll"}); !errors.Is(err, domain.ErrBlocked) {
+			t.Fatalf("retweet %s: want ErrBlocked, got %v", id, err)
+		}
+		if _, err := post.Exec(ctx, PostTweetInput{UserID: "troll", Text: "jaja", QuotedTweetID: id}); !errors.Is(err, domain.ErrBlocked) {
+			t.Fatalf("quote %s: want ErrBlocked, got %v", id, err)
+		}
+	}
+	like := LikeTweet{Tweets: tr, Likes: &memLikeRepo{}, Clock: fakeClock{now: 11}, IDGen: fakeID{id: "L"}, Blocks: br}
+	for _, id := range []string{"A", "R1"} {
+		if _, err := like.Exec(ctx, LikeTweetInput{TweetID: id, UserID: "troll"}); !errors.Is(err, domain.ErrBlocked) {
+			t.Fatalf("like %s: want ErrBlocked, got %v", id, err)
+		}
+	}
+	if _, _, err := retweet.Exec(ctx, RetweetInput{TweetID: "C", UserID: "troll"}); err != nil {
+		t.Fatalf("retweet someone else: %v", err)
+	}
+
+	// El troll sigue viendo a caro, pero no los tweets de ana ni sus retweets.
+	fr.following["troll"] = []string{"ana", "caro"} // el fake no borra en Unfollow
+	timeline := GetTimeline{Tweets: tr, Follows: fr, Blocks: br}
+	page, err := timeline.Exec(ctx, GetTimelineInput{UserID: "troll", ActorID: "troll", Limit: 10})
+	if err != nil || len(page.Items) != 1 || page.Items[0].ID != "C" {
+		t.Fatalf("timeline = %#v err=%v", page.Items, err)
+	}
+	// ... ni leyendo el timeline de otro que sí la ve.
+	if _, err := timeline.Exec(ctx, GetTimelineInput{UserID: "caro", ActorID: "troll", Limit: 10}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("someone else's timeline: want ErrForbidden, got %v", err)
+	}
+
+	list := GetBlocks{Users: users, Blocks: br}
+	if p, err := list.Exec(ctx, GetBlocksInput{UserID: "ana", ActorID: "ana"}); err != nil || len(p.Items) != 1 {
+		t.Fatalf("blocks = %#v err=%v", p, err)
+	}
+	if _, err := list.Exec(ctx, GetBlocksInput{UserID: "ana", ActorID: "troll"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("want ErrForbidden, got %v", err)
+	}
+
+	if err := (UnblockUser{Blocks: br}).Exec(ctx, BlockUserInput{BlockerID: "ana", BlockedID: "troll"}); err != nil {
+		t.Fatalf("unblock: %v", err)
+	}
+	if _, err := follow.Exec(ctx, FollowUserInput{FollowerID: "troll", FolloweeID: "ana"}); err != nil {
+		t.Fatalf("follow after unblock: %v", err)
+	}
+}
+
+type memMuteRepo struct{ mutes []domain.Mute }
+
+func (m *memMuteRepo) Create(_ context.Context, mu *domain.Mute) error {
+	for i, x := range m.mutes {
+		if x.UserID == mu.UserID && x.Kind == mu.Kind && x.Value == mu.Value {
+			m.mutes[i].ExpiresAt = mu.ExpiresAt
+			*mu = m.mutes[i]
+			return nil
+		}
+	}
+	m.mutes = append(m.mutes, *mu)
+	return nil
+}
+func (m *memMuteRepo) Delete(_ context.Context, userID, id string) error {
+	m.mutes = slices.DeleteFunc(m.mutes, func(x domain.Mute) bool { return x.UserID == userID && x.ID == id })
+	return nil
+}
+func (m *memMuteRepo) Active(_ context.Context, userID string, now int64) ([]domain.Mute, error) {
+	var out []domain.Mute
+	for _, x := range m.mutes {
+		if x.UserID == userID && !x.Expired(now) {
+			out = append(out, x)
+		}
+	}
+	return out, nil
+}
+
+func TestMuteUser_FiltersTimelineWithFullPages(t *testing.T) {
+	ctx := context.Background()
+	users := newMemUsers("ana", "bob", "caro", "ruido")
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{}}
+	// bob alterna tweets normales y spoilers; ruido tuitea y caro lo retuitea.
+	for i := 1; i <= 10; i++ {
+		text := fmt.Sprintf("tweet %d", i)
+		if i%2 == 0 {
+			text = fmt.Sprintf("SPOILER: capítulo %d", i)
+		}
+		tr.byUser["bob"] = append(tr.byUser["bob"], domain.Tweet{ID: fmt.Sprintf("B%02d", i), UserID: "bob", Text: text, CreatedAt: int64(i)})
+	}
+	tr.byUser["ruido"] = []domain.Tweet{{ID: "N1", UserID: "ruido", Text: "hola", CreatedAt: 11}}
+	tr.byUser["caro"] = []domain.Tweet{{ID: "C1", UserID: "caro", RetweetOfID: "N1", CreatedAt: 12}}
+	tr.byUser["ana"] = []domain.Tweet{{ID: "A1", UserID: "ana", Text: "sin spoiler", CreatedAt: 13}}
+	fr := &memFollowRepo{following: map[string][]string{"ana": {"bob", "caro", "ruido"}}}
+	clock := &fakeClock{now: 100}
+	mr := &memMuteRepo{}
+	n := 0
+	mute := MuteUser{Mutes: mr, Clock: clock, IDGen: seqID{n: &n}, Users: users}
+
+	if _, err := mute.Exec(ctx, MuteUserInput{UserID: "ana", Kind: domain.MuteKeyword, Value: "spoiler", ExpiresIn: 60}); err != nil {
+		t.Fatalf("mute keyword: %v", err)
+	}
+	if _, err := mute.Exec(ctx, MuteUserInput{UserID: "ana", Kind: domain.MuteAccount, Value: "ruido"}); err != nil {
+		t.Fatalf("mute account: %v", err)
+	}
+	if _, err := mute.Exec(ctx, MuteUserInput{UserID: "ana", Kind: domain.MuteAccount, Value: "nadie"}); !errors.Is(err, domain.ErrUnknownUser) {
+		t.Fatalf("want ErrUnknownUser, got %v", err)
+	}
+	if _, err := mute.Exec(ctx, MuteUserInput{UserID: "ana", Kind: domain.MuteRegex, Value: "("}); err == nil {
+		t.Fatal("expected invalid regex error")
+	}
+
+	timeline := GetTimeline{Tweets: tr, Follows: fr, Mutes: mr, Clock: clock}
+	var got []string
+	cursor := ""
+	for i := 0; ; i++ {
+		page, err := timeline.Exec(ctx, GetTimelineInput{UserID: "ana", ActorID: "ana", Limit: 2, Cursor: cursor, IncludeSelf: true})
+		if err != nil {
+			t.Fatalf("timeline: %v", err)
+		}
+		if page.NextCursor != "" && len(page.Items) != 2 {
+			t.Fatalf("page %d not full: %#v", i, page.Items)
+		}
+		for _, tw := range page.Items {
+			got = append(got, tw.ID)
+		}
+		if cursor = page.NextCursor; cursor == "" || i > 10 {
+			break
+		}
+	}
+	// Los propios no se silencian; el retweet de caro se va con ruido.
+	if want := []string{"A1", "B09", "B07", "B05", "B03", "B01"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("timeline = %v, want %v", got, want)
+	}
+
+	// Hacia adelante (prev_cursor) se llena con lo más cercano al cursor.
+	from := domain.Cursor{CreatedAt: 1, ID: "B01", Newer: true}.Encode()
+	newer, err := timeline.Exec(ctx, GetTimelineInput{UserID: "ana", ActorID: "ana", Limit: 2, Cursor: from})
+	if err != nil || len(newer.Items) != 2 || newer.Items[0].ID != "B05" || newer.Items[1].ID != "B03" {
+		t.Fatalf("newer page = %#v err=%v", newer.Items, err)
+	}
+	if c, _ := domain.DecodeCursor(newer.PrevCursor); c.ID != "B05" || !c.Newer {
+		t.Fatalf("prev cursor = %+v", c)
+	}
+
+	// Vencido el mute de la palabra vuelven los spoilers.
+	clock.now = 160
+	page, _ := timeline.Exec(ctx, GetTimelineInput{UserID: "ana", ActorID: "ana", Limit: 3})
+	if ids := []string{page.Items[0].ID, page.Items[1].ID, page.Items[2].ID}; !reflect.DeepEqual(ids, []string{"B10", "B09", "B08"}) {
+		t.Fatalf("after expiry = %v", ids)
+	}
+
+	list := ListMutes{Mutes: mr, Clock: clock}
+	mutes, err := list.Exec(ctx, "ana")
+	if err != nil || len(mutes) != 1 || mutes[0].Value != "ruido" {
+		t.Fatalf("mutes = %#v err=%v", mutes, err)
+	}
+	if err := (UnmuteUser{Mutes: mr}).Exec(ctx, UnmuteUserInput{UserID: "ana", MuteID: mutes[0].ID}); err != nil {
+		t.Fatalf("unmute: %v", err)
+	}
+	if mutes, _ := list.Exec(ctx, "ana"); len(mutes) != 0 {
+		t.Fatalf("expected no mutes, got %#v", mutes)
+	}
+}
+
+// Interface assertions (por si cambiamos firmas sin querer)
+func TestFollowUser_ProtectedAccountNeedsApproval(t *testing.T) {
+	ctx := context.Background()
+	users := newMemUsers("ana", "bob", "caro")
+	ana := users["ana"]
+	ana.Protected = true
+	users["ana"] = ana
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"ana":  {{ID: "A", UserID: "ana", Text: "secreto #go", CreatedAt: 1}},
+		"caro": {{ID: "C", UserID: "caro", Text: "público #go", CreatedAt: 2}},
+	}}
+	fr := &memFollowRepo{following: map[string][]string{}}
+	reqs := memFollowRequestRepo{}
+	follow := FollowUser{Follows: fr, Clock: fakeClock{now: 5}, IDGen: fakeID{id: "F1"}, Users: users, Requests: reqs}
+	hashtag := GetHashtagTweets{Tweets: tr, Users: users, Follows: fr}
+	visible := func(viewer string) []string {
+		t.Helper()
+		p, err := hashtag.Exec(ctx, GetHashtagTweetsInput{Tag: "go", ViewerID: viewer})
+		if err != nil {
+			t.Fatalf("hashtag: %v", err)
+		}
+		var ids []string
+		for _, tw := range p.Items {
+			ids = append(ids, tw.ID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	res, err := follow.Exec(ctx, FollowUserInput{FollowerID: "bob", FolloweeID: "ana"})
+	if err != nil || res.Request == nil || res.Request.RequesterID != "bob" || len(fr.following["bob"]) != 0 {
+		t.Fatalf("follow protected = %#v err=%v following=%v", res, err, fr.following)
+	}
+	if res, err := follow.Exec(ctx, FollowUserInput{FollowerID: "bob", FolloweeID: "caro"}); err != nil || res.Request != nil {
+		t.Fatalf("follow public = %#v err=%v", res, err)
+	}
+	for viewer, want := range map[string][]string{"": {"C"}, "bob": {"C"}, "ana": {"A", "C"}} {
+		if got := visible(viewer); !reflect.DeepEqual(got, want) {
+			t.Fatalf("viewer %q sees %v, want %v", viewer, got, want)
+		}
+	}
+
+	list := ListFollowRequests{Requests: reqs}
+	if p, err := list.Exec(ctx, ListFollowRequestsInput{UserID: "ana"}); err != nil || len(p.Items) != 1 || p.Items[0].RequesterID != "bob" {
+		t.Fatalf("requests = %#v err=%v", p, err)
+	}
+	approve := ApproveFollowRequest{Requests: reqs, Follow: follow}
+	if _, err := approve.Exec(ctx, FollowRequestInput{TargetID: "ana", RequesterID: "caro"}); !errors.Is(err, domain.ErrFollowRequestNotFound) {
+		t.Fatalf("approve missing: want ErrFollowRequestNotFound, got %v", err)
+	}
+	f, err := approve.Exec(ctx, FollowRequestInput{TargetID: "ana", RequesterID: "bob"})
+	if err != nil || f.FollowerID != "bob" || f.FolloweeID != "ana" || len(reqs) != 0 {
+		t.Fatalf("approve = %#v err=%v requests=%v", f, err, reqs)
+	}
+	if got := visible("bob"); !reflect.DeepEqual(got, []string{"A", "C"}) {
+		t.Fatalf("approved follower sees %v", got)
+	}
+	// seguir de nuevo a quien ya aprobó no genera otra solicitud
+	if res, err := follow.Exec(ctx, FollowUserInput{FollowerID: "bob", FolloweeID: "ana"}); err != nil || res.Request != nil {
+		t.Fatalf("refollow = %#v err=%v", res, err)
+	}
+
+	// caro pide, ana la rechaza; caro vuelve a pedir y se arrepiente
+	if _, err := follow.Exec(ctx, FollowUserInput{FollowerID: "caro", FolloweeID: "ana"}); err != nil {
+		t.Fatalf("caro follow: %v", err)
+	}
+	reject := RejectFollowRequest{Requests: reqs}
+	if err := reject.Exec(ctx, FollowRequestInput{TargetID: "ana", RequesterID: "caro"}); err != nil || len(reqs) != 0 {
+		t.Fatalf("reject: %v requests=%v", err, reqs)
+	}
+	if err := reject.Exec(ctx, FollowRequestInput{TargetID: "ana", RequesterID: "caro"}); !errors.Is(err, domain.ErrFollowRequestNotFound) {
+		t.Fatalf("reject twice: want ErrFollowRequestNotFound, got %v", err)
+	}
+	if _, err := follow.Exec(ctx, FollowUserInput{FollowerID: "caro", FolloweeID: "ana"}); err != nil || len(reqs) != 1 {
+		t.Fatalf("caro follow again: %v requests=%v", err, reqs)
+	}
+	unfollow := UnfollowUser{Follows: fr, Requests: reqs}
+	if err := unfollow.Exec(ctx, UnfollowUserInput{FollowerID: "caro", FolloweeID: "ana"}); err != nil || len(reqs) != 0 {
+		t.Fatalf("cancel: %v requests=%v", err, reqs)
+	}
+}
+
+func TestProtectedTweet_NotFoundForRevisionsRetweetsAndLikes(t *testing.T) {
+	ctx := context.Background()
+	users := newMemUsers("ana", "bob", "caro")
+	ana := users["ana"]
+	ana.Protected = true
+	users["ana"] = ana
+	tr := &memTweetRepo{byUser: map[string][]domain.Tweet{
+		"ana": {{ID: "A", UserID: "ana", Text: "secreto", CreatedAt: 1}},
+		"bob": {{ID: "R", UserID: "bob", RetweetOfID: "A", CreatedAt: 2}},
+	}}
+	fr := &memFollowRepo{following: map[string][]string{"bob": {"ana"}, "caro": {"bob"}}}
+	lr := &memLikeRepo{}
+	revisions := GetTweetRevisions{Tweets: tr, Users: users, Follows: fr}
+	retweet := Retweet{Tweets: tr, Clock: fakeClock{now: 5}, IDGen: fakeID{id: "RT"}, Users: users, Follows: fr}
+	quote := PostTweet{Tweets: tr, Clock: fakeClock{now: 5}, IDGen: fakeID{id: "Q"}, Users: users, Follows: fr}
+	like := LikeTweet{Tweets: tr, Likes: lr, Clock: fakeClock{now: 5}, IDGen: fakeID{id: "L"}, Users: users, Follows: fr}
+	likers := GetTweetLikes{Tweets: tr, Likes: lr, Users: users, Follows: fr}
+
+	// Para quien no la sigue, la cuenta protegida no existe (tampoco vía el retweet de bob).
+	if _, err := revisions.Exec(ctx, GetTweetRevisionsInput{TweetID: "A", ViewerID: "caro"}); !errors.Is(err, domain.ErrTweetNotFound) {
+		t.Fatalf("revisions: want ErrTweetNotFound, got %v", err)
+	}
+	// responder sale igual que a un padre inexistente
+	if _, err := quote.Exec(ctx, PostTweetInput{UserID: "caro", Text: "hola", InReplyToID: "A"}); !errors.Is(err, domain.ErrParentNotFound) {
+		t.Fatalf("reply: want ErrParentNotFound, got %v", err)
+	}
+	for _, id := range []string{"A", "R"} {
+		if _, _, err := retweet.Exec(ctx, RetweetInput{TweetID: id, UserID: "caro"}); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("retweet %s: want ErrTweetNotFound, got %v", id, err)
+		}
+		if _, err := quote.Exec(ctx, PostTweetInput{UserID: "caro", Text: "mirá", QuotedTweetID: id}); !errors.Is(err, domain.ErrQuotedNotFound) {
+			t.Fatalf("quote %s: want ErrQuotedNotFound, got %v", id, err)
+		}
+		if _, err := like.Exec(ctx, LikeTweetInput{TweetID: id, UserID: "caro"}); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("like %s: want ErrTweetNotFound, got %v", id, err)
+		}
+		if _, err := likers.Exec(ctx, GetTweetLikesInput{TweetID: id}); !errors.Is(err, domain.ErrTweetNotFound) {
+			t.Fatalf("likers %s as anonymous: want ErrTweetNotFound, got %v", id, err)
+		}
+	}
+	// Ni leyendo el timeline de bob, que sí la sigue.
+	timeline := GetTimeline{Tweets: tr, Follows: fr, Users: users}
+	if _, err := timeline.Exec(ctx, GetTimelineInput{UserID: "bob", ActorID: "caro", Limit: 10}); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("bob's timeline as caro: want ErrForbidden, got %v", err)
+	}
+
+	// Un seguidor aprobado sí.
+	if _, err := revisions.Exec(ctx, GetTweetRevisionsInput{TweetID: "A", ViewerID: "bob"}); err != nil {
+		t.Fatalf("revisions as follower: %v", err)
+	}
+	if _, err := like.Exec(ctx, LikeTweetInput{TweetID: "A", UserID: "bob"}); err != nil {
+		t.Fatalf("like as follower: %v", err)
+	}
+	if p, err := likers.Exec(ctx, GetTweetLikesInput{TweetID: "R", ViewerID: "bob"}); err != nil || len(p.Items) != 1 {
+		t.Fatalf("likers as follower = %#v err=%v", p, err)
+	}
+	if _, _, err := retweet.Exec(ctx, RetweetInput{TweetID: "A", UserID: "ana"}); err != nil {
+		t.Fatalf("retweet own tweet: %v", err)
+	}
+}
+
+var _ ports.TweetRepo = (*memTweetRepo)(nil)
+var _ ports.FollowRepo = (*memFollowRepo)(nil)
+var _ ports.HomeTimelineRepo = (*memHomeRepo)(nil)
+var _ ports.LikeRepo = (*memLikeRepo)(nil)
+var _ ports.UserRepo = memUserRepo(nil)
+var _ ports.TweetSearcher = (*memSearcher)(nil)
+var _ ports.BlockRepo = (*memBlockRepo)(nil)
+var _ ports.MuteRepo = (*memMuteRepo)(nil)
+var _ ports.FollowRequestRepo = memFollowRequestRepo(nil)
+
+type memAPIKeyRepo map[string]domain.APIKey
+
+func (r memAPIKeyRepo) Create(_ context.Context, k *domain.APIKey) error {
+	r[k.ID] = *k
+	return nil
+}
+
+func (r memAPIKeyRepo) Get(_ context.Context, id string) (domain.APIKey, error) {
+	k, ok := r[id]
+	if !ok {
+		return domain.APIKey{}, domain.ErrAPIKeyNotFound
+	}
+	return k, nil
+}
+
+func (r memAPIKeyRepo) ByHash(_ context.Context, hash string) (domain.APIKey, error) {
+	for _, k := range r {
+		if k.Hash == hash {
+			return k, nil
+		}
+	}
+	return domain.APIKey{}, domain.ErrAPIKeyNotFound
+}
+
+func (r memAPIKeyRepo) ListByUser(_ context.Context, userID string) ([]domain.APIKey, error) {
+	var out []domain.APIKey
+	for _, k := range r {
+		if k.UserID == userID {
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+func (r memAPIKeyRepo) Revoke(_ context.Context, id string, at int64) error {
+	if k, ok := r[id]; ok && k.RevokedAt == 0 {
+		k.RevokedAt = at
+		r[id] = k
+	}
+	return nil
+}
+
+func (r memAPIKeyRepo) Rotate(ctx context.Context, oldID string, next *domain.APIKey, at int64) error {
+	_ = r.Revoke(ctx, oldID, at)
+	return r.Create(ctx, next)
+}
+
+type fakeSecret struct{ s string }
+
+func (f fakeSecret) NewSecret() string { return f.s }
+
+func TestAPIKeys_IssueAuthenticateRotateRevoke(t *testing.T) {
+	ctx := context.Background()
+	keys := memAPIKeyRepo{}
+	users := newMemUsers("u1", "u2")
+	create := CreateUser{Users: users, Clock: fakeClock{now: 1}, IDGen: fakeID{id: "U3"}, Keys: keys, Secrets: fakeSecret{s: "first"}}
+	u, first, err := create.Exec(ctx, CreateUserInput{Handle: "ana"})
+	if err != nil || first == nil || first.UserID != u.ID || first.Token != "tck_first" {
+		t.Fatalf("create = %#v key=%#v err=%v", u, first, err)
+	}
+
+	auth := Authenticate{Keys: keys}
+	if p, err := auth.Exec(ctx, first.Token); err != nil || p.UserID != "U3" || p.Method != domain.AuthAPIKey || !p.HasScope(domain.ScopeFollowsWrite) {
+		t.Fatalf("auth = %+v err=%v", p, err)
+	}
+	for _, tok := range []string{"", "first", "tck_otro"} {
+		if _, err := auth.Exec(ctx, tok); !errors.Is(err, domain.ErrUnauthenticated) {
+			t.Fatalf("%q: want ErrUnauthenticated, got %v", tok, err)
+		}
+	}
+
+	issue := IssueAPIKey{Keys: keys, Users: users, Clock: fakeClock{now: 2}, IDGen: fakeID{id: "K2"}, Secrets: fakeSecret{s: "u1"}}
+	if _, err := issue.Exec(ctx, IssueAPIKeyInput{UserID: "nadie"}); !errors.Is(err, domain.ErrUnknownUser) {
+		t.Fatalf("want ErrUnknownUser, got %v", err)
+	}
+	k2, err := issue.Exec(ctx, IssueAPIKeyInput{UserID: "u1", Name: "ci"})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	rotate := RotateAPIKey{Keys: keys, Clock: fakeClock{now: 3}, IDGen: fakeID{id: "K3"}, Secrets: fakeSecret{s: "u1b"}}
+	if _, err := rotate.Exec(ctx, APIKeyInput{UserID: "u2", KeyID: k2.ID}); !errors.Is(err, domain.ErrAPIKeyNotFound) {
+		t.Fatalf("rotate ajena: want ErrAPIKeyNotFound, got %v", err)
+	}
+	k3, err := rotate.Exec(ctx, APIKeyInput{UserID: "u1", KeyID: k2.ID})
+	if err != nil || k3.Name != "ci" || keys[k2.ID].RevokedAt != 3 {
+		t.Fatalf("rotate = %#v err=%v", k3, err)
+	}
+	if _, err := auth.Exec(ctx, k2.Token); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Fatalf("old key: want ErrUnauthenticated, got %v", err)
+	}
+	if _, err := rotate.Exec(ctx, APIKeyInput{UserID: "u1", KeyID: k2.ID}); !errors.Is(err, domain.ErrAPIKeyRevoked) {
+		t.Fatalf("want ErrAPIKeyRevoked, got %v", err)
+	}
+
+	revoke := RevokeAPIKey{Keys: keys, Clock: fakeClock{now: 4}}
+	for i := 0; i < 2; i++ {
+		if err := revoke.Exec(ctx, APIKeyInput{UserID: "u1", KeyID: k3.ID}); err != nil {
+			t.Fatalf("revoke #%d: %v", i, err)
+		}
+	}
+	if _, err := auth.Exec(ctx, k3.Token); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Fatalf("revoked key: want ErrUnauthenticated, got %v", err)
+	}
+}
+
+// fakeAccessTokens "firma" concatenando usuario y scopes.
+type fakeAccessTokens struct{}
+
+func (fakeAccessTokens) Sign(c domain.AccessClaims) (string, error) {
+	return "jwt." + c.UserID + "." + strings.Join(c.Scopes, ","), nil
+}
+
+func (fakeAccessTokens) Verify(token string) (domain.AccessClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != "jwt" {
+		return domain.AccessClaims{}, domain.ErrUnauthenticated
+	}
+	return domain.AccessClaims{UserID: parts[1], Scopes: strings.Split(parts[2], ",")}, nil
+}
+
+func (fakeAccessTokens) PublicKeys() []ports.JWK { return nil }
+
+type memRefreshRepo map[string]domain.RefreshToken
+
+func (r memRefreshRepo) Create(_ context.Context, t *domain.RefreshToken) error {
+	r[t.ID] = *t
+	return nil
+}
+
+func (r memRefreshRepo) ByHash(_ context.Context, hash string) (domain.RefreshToken, error) {
+	for _, t := range r {
+		if t.Hash == hash {
+			return t, nil
+		}
+	}
+	return domain.RefreshToken{}, domain.ErrUnauthenticated
+}
+
+func (r memRefreshRepo) Rotate(ctx context.Context, oldID string, next *domain.RefreshToken, at int64) error {
+	old := r[oldID]
+	if old.RevokedAt != 0 {
+		return domain.ErrUnauthenticated
+	}
+	old.RevokedAt = at
+	r[oldID] = old
+	return r.Create(ctx, next)
+}
+
+func (r memRefreshRepo) RevokeUser(_ context.Context, userID string, at int64) error {
+	for id, t := range r {
+		if t.UserID == userID && t.RevokedAt == 0 {
+			t.RevokedAt = at
+			r[id] = t
+		}
+	}
+	return nil
+}
+
+// seqID devuelve ids distintos en cada llamada.
+type seqID struct{ n *int }
+
+func (s seqID) NewID() string {
+	*s.n++
+	return fmt.Sprintf("R%d", *s.n)
+}
+
+type seqSecret struct{ n *int }
+
+func (s seqSecret) NewSecret() string {
+	*s.n++
+	return fmt.Sprintf("s%d", *s.n)
+}
+
+func TestTokens_IssueRefreshAndReuseDetection(t *testing.T) {
+	ctx := context.Background()
+	refresh := memRefreshRepo{}
+	clock := &fakeClock{now: 100}
+	var ids, secrets int
+	cfg := TokenConfig{
+		Tokens: fakeAccessTokens{}, Refresh: refresh, Clock: clock,
+		IDGen: seqID{n: &ids}, Secrets: seqSecret{n: &secrets}, AccessTTL: 60, RefreshTTL: 1000,
+	}
+	keys := memAPIKeyRepo{"K1": {ID: "K1", UserID: "u1"}}
+	issue := IssueTokens{TokenConfig: cfg}
+	if _, err := issue.Exec(ctx, IssueTokensInput{UserID: "u1", APIKeyID: "K1", Scopes: []string{"admin"}}); !errors.Is(err, domain.ErrInvalidScope) {
+		t.Fatalf("want ErrInvalidScope, got %v", err)
+	}
+	pair, err := issue.Exec(ctx, IssueTokensInput{UserID: "u1", APIKeyID: "K1", Scopes: []string{domain.ScopeTimelineRead}})
+	if err != nil || pair.AccessToken != "jwt.u1.timeline:read" || pair.ExpiresIn != 60 || pair.Scope != "timeline:read" {
+		t.Fatalf("issue = %+v err=%v", pair, err)
+	}
+
+	auth := Authenticate{Keys: memAPIKeyRepo{}, Tokens: fakeAccessTokens{}}
+	p, err := auth.Exec(ctx, pair.AccessToken)
+	if err != nil || p.Method != domain.AuthJWT || p.HasScope(domain.ScopeTweetsWrite) || !p.HasScope(domain.ScopeTimelineRead) {
+		t.Fatalf("auth jwt = %+v err=%v", p, err)
+	}
+
+	uc := RefreshTokens{TokenConfig: cfg, Keys: keys}
+	next, err := uc.Exec(ctx, pair.RefreshToken)
+	if err != nil || next.RefreshToken == pair.RefreshToken || next.Scope != "timeline:read" {
+		t.Fatalf("refresh = %+v err=%v", next, err)
+	}
+	// reusar el refresh ya rotado revoca también el vigente
+	if _, err := uc.Exec(ctx, pair.RefreshToken); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Fatalf("reuse: want ErrUnauthenticated, got %v", err)
+	}
+	if _, err := uc.Exec(ctx, next.RefreshToken); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Fatalf("after reuse detection: want ErrUnauthenticated, got %v", err)
+	}
+
+	fresh, _ := issue.Exec(ctx, IssueTokensInput{UserID: "u1", APIKeyID: "K1"})
+	clock.now += 1000
+	if _, err := uc.Exec(ctx, fresh.RefreshToken); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Fatalf("expired: want ErrUnauthenticated, got %v", err)
+	}
+	for _, tok := range []string{"", "tck_x", "tckr_inventado"} {
+		if _, err := uc.Exec(ctx, tok); !errors.Is(err, domain.ErrUnauthenticated) {
+			t.Fatalf("%q: want ErrUnauthenticated, got %v", tok, err)
+		}
+	}
+}
+
+func TestRefreshTokens_RejectsRevokedOrRotatedKey(t *testing.T) {
+	ctx := context.Background()
+	var ids, secrets int
+	cfg := TokenConfig{
+		Tokens: fakeAccessTokens{}, Refresh: memRefreshRepo{}, Clock: &fakeClock{now: 100},
+		IDGen: seqID{n: &ids}, Secrets: seqSecret{n: &secrets}, AccessTTL: 60, RefreshTTL: 1000,
+	}
+	keys := memAPIKeyRepo{}
+	for _, id := range []string{"K1", "K2"} {
+		keys[id] = domain.APIKey{ID: id, UserID: "u1", Hash: id}
+	}
+	issue := IssueTokens{TokenConfig: cfg}
+	uc := RefreshTokens{TokenConfig: cfg, Keys: keys}
+
+	viaK1, _ := issue.Exec(ctx, IssueTokensInput{UserID: "u1", APIKeyID: "K1"})
+	next, err := uc.Exec(ctx, viaK1.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh with active key: %v", err)
+	}
+	if err := keys.Revoke(ctx, "K1", 110); err != nil {
+		t.Fatal(err)
+	}
+	// la cadena sigue atada a K1 aunque ya se haya rotado el refresh
+	if _, err := uc.Exec(ctx, next.RefreshToken); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Fatalf("revoked key: want ErrUnauthenticated, got %v", err)
+	}
+
+	viaK2, _ := issue.Exec(ctx, IssueTokensInput{UserID: "u1", APIKeyID: "K2"})
+	if err := keys.Rotate(ctx, "K2", &domain.APIKey{ID: "K3", UserID: "u1", Hash: "K3"}, 120); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Exec(ctx, viaK2.RefreshToken); !errors.Is(err, domain.ErrUnauthenticated) {
+		t.Fatalf("rotated key: want ErrUnauthenticated, got %v", err)
+	}
+	if _, err := issue.Exec(ctx, IssueTokensInput{UserID: "u1"}); err == nil {
+		t.Fatal("issuing without an API key must fail")
+	}
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+type CreateUser struct {
+	Users ports.UserRepo
+	Clock ports.Clock
+	IDGen ports.IDGen
+
+	// Primera API key del usuario (opcional): sin ella no podría autenticarse.
+	Keys    ports.APIKeyRepo
+	Secrets ports.SecretGen
+}
+
+type CreateUserInput struct{ Handle, DisplayName, Bio string }
+
+// Exec devuelve la key emitida, o nil si no hay Keys configurado.
+func (uc CreateUser) Exec(ctx context.Context, in CreateUserInput) (domain.User, *IssuedAPIKey, error) {
+	u, err := domain.NewUser(uc.IDGen.NewID(), in.Handle, in.DisplayName, in.Bio, uc.Clock.NowUnix())
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	if err := uc.Users.Create(ctx, &u); err != nil {
+		return domain.User{}, nil, err
+	}
+	if uc.Keys == nil {
+		return u, nil, nil
+	}
+	issue := IssueAPIKey{Keys: uc.Keys, Clock: uc.Clock, IDGen: uc.IDGen, Secrets: uc.Secrets}
+	k, err := issue.Exec(ctx, IssueAPIKeyInput{UserID: u.ID, Name: "default"})
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	return u, &k, nil
+}
+
+type GetUser struct{ Users ports.UserRepo }
+
+func (uc GetUser) Exec(ctx context.Context, id string) (domain.User, error) {
+	return uc.Users.Get(ctx, id)
+}
+
+type UpdateUser struct {
+	Users ports.UserRepo
+	Clock ports.Clock
+}
+
+type UpdateUserInput struct {
+	UserID  string
+	ActorID string // quien hace el cambio: sólo el propio usuario
+	Patch   domain.UserPatch
+}
+
+func (uc UpdateUser) Exec(ctx context.Context, in UpdateUserInput) (domain.User, error) {
+	if in.ActorID != in.UserID {
+		return domain.User{}, domain.ErrForbidden
+	}
+	u, err := uc.Users.Get(ctx, in.UserID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	updated, err := u.Update(in.Patch, uc.Clock.NowUnix())
+	if err != nil {
+		return domain.User{}, err
+	}
+	if updated == u {
+		return u, nil
+	}
+	if err := uc.Users.Update(ctx, &updated); err != nil {
+		return domain.User{}, err
+	}
+	return updated, nil
+}
+
+// requireUsers falla con ErrUnknownUser si algún id no es un usuario. Sin
+// repo no se valida.
+func requireUsers(ctx context.Context, users ports.UserRepo, ids ...string) error {
+	if users == nil {
+		return nil
+	}
+	for _, id := range ids {
+		_, err := users.Get(ctx, id)
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return domain.ErrUnknownUser
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requireActive es requireUsers para un único autor que además rechaza
+// cuentas suspendidas con ErrSuspended.
+func requireActive(ctx context.Context, users ports.UserRepo, id string) error {
+	if users == nil {
+		return nil
+	}
+	u, err := users.Get(ctx, id)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return domain.ErrUnknownUser
+	}
+	if err != nil {
+		return err
+	}
+	if u.Suspended() {
+		return domain.ErrSuspended
+	}
+	return nil
+}
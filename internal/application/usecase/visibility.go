@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+	"tweetschallenge/internal/ports"
+)
+
+// withoutProtected filtra en el lugar los items cuyo author(t) es una cuenta
+// protegida que viewerID no sigue ("" = anónimo, no sigue a nadie). Cada uno
+// ve lo propio. Sin repos no filtra nada.
+func withoutProtected[T any](
+	ctx context.Context, users ports.UserRepo, follows ports.FollowRepo, viewerID string,
+	items []T, author func(T) string,
+) ([]T, error) {
+	if users == nil || follows == nil || len(items) == 0 {
+		return items, nil
+	}
+	var ids []string
+	for _, t := range items {
+		if id := author(t); id != "" && id != viewerID {
+			ids = append(ids, id)
+		}
+	}
+	protected, err := users.ProtectedIDs(ctx, ids)
+	if err != nil || len(protected) == 0 {
+		return items, err
+	}
+	hidden := map[string]bool{}
+	for _, id := range protected {
+		approved := false
+		if viewerID != "" {
+			if approved, err = follows.Exists(ctx, viewerID, id); err != nil {
+				return nil, err
+			}
+		}
+		hidden[id] = !approved
+	}
+	out := items[:0]
+	for _, t := range items {
+		if !hidden[author(t)] {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// visibleTweets aplica withoutProtected a los autores y, si hay retweets
+// hidratados, a los autores de los originales.
+func visibleTweets(ctx context.Context, users ports.UserRepo, follows ports.FollowRepo, viewerID string, items []domain.Tweet) ([]domain.Tweet, error) {
+	items, err := withoutProtected(ctx, users, follows, viewerID, items, func(t domain.Tweet) string { return t.UserID })
+	if err != nil {
+		return nil, err
+	}
+	return withoutProtected(ctx, users, follows, viewerID, items, retweetedAuthor)
+}
+
+// requireVisible devuelve ErrTweetNotFound si tw es de una cuenta protegida
+// que viewerID no sigue: para el lector ese tweet no existe.
+func requireVisible(ctx context.Context, users ports.UserRepo, follows ports.FollowRepo, viewerID string, tw domain.Tweet) error {
+	visible, err := withoutProtected(ctx, users, follows, viewerID, []domain.Tweet{tw},
+		func(t domain.Tweet) string { return t.UserID })
+	if err != nil {
+		return err
+	}
+	if len(visible) == 0 {
+		return domain.ErrTweetNotFound
+	}
+	return nil
+}
+
+// visibleOriginal resuelve tweetID a su original si es un retweet, exigiendo
+// que viewerID pueda ver los dos (requireVisible).
+func visibleOriginal(
+	ctx context.Context, tweets ports.TweetRepo, users ports.UserRepo, follows ports.FollowRepo,
+	viewerID, tweetID string,
+) (domain.Tweet, error) {
+	tw, err := tweets.Get(ctx, tweetID)
+	if err != nil {
+		return domain.Tweet{}, err
+	}
+	if err := requireVisible(ctx, users, follows, viewerID, tw); err != nil {
+		return domain.Tweet{}, err
+	}
+	if tw.RetweetOfID == "" {
+		return tw, nil
+	}
+	original, err := tweets.Get(ctx, tw.RetweetOfID)
+	if err != nil {
+		return domain.Tweet{}, err
+	}
+	return original, requireVisible(ctx, users, follows, viewerID, original)
+}
+
+// retweetedAuthor es el autor del original de un retweet hidratado ("" si no lo es).
+func retweetedAuthor(t domain.Tweet) string {
+	if t.Retweeted == nil {
+		return ""
+	}
+	return t.Retweeted.UserID
+}
@@ -1,47 +1,184 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
 	adapterclock "tweetschallenge/internal/adapters/clock"
 	adaptersdb "tweetschallenge/internal/adapters/db"
+	"tweetschallenge/internal/adapters/db/migrate"
 	adaptershttp "tweetschallenge/internal/adapters/http"
 	adapterid "tweetschallenge/internal/adapters/id"
+	adapterjwt "tweetschallenge/internal/adapters/jwt"
+	adapterratelimit "tweetschallenge/internal/adapters/ratelimit"
 	app "tweetschallenge/internal/application/usecase"
 )
 
 func BuildHTTPServer() (*gin.Engine, func(), error) {
-	// DB (in-memory SQLite)
-	db, err := adaptersdb.NewInMemoryGorm()
+	// DB (DB_DRIVER=sqlite|postgres; SQLite en memoria por defecto)
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
 	if err != nil {
 		return nil, nil, fmt.Errorf("db: %w", err)
 	}
-	if err := adaptersdb.AutoMigrate(db); err != nil {
-		return nil, nil, fmt.Errorf("migrate: %w", err)
-	}
-	if err := adaptersdb.AutoMigrateFollow(db); err != nil {
-		return nil, nil, fmt.Errorf("migrate follow: %w", err)
+	if autoMigrate() {
+		m, err := migrate.New(db)
+		if err != nil {
+			_ = adaptersdb.Close(db)
+			return nil, nil, err
+		}
+		if _, err := m.Up(context.Background()); err != nil {
+			_ = adaptersdb.Close(db)
+			return nil, nil, err
+		}
 	}
 
 	// Adapters
 	tweetRepo := adaptersdb.NewTweetRepoGorm(db)
 	followRepo := adaptersdb.NewFollowRepoGorm(db)
+	blockRepo := adaptersdb.NewBlockRepoGorm(db)
+	muteRepo := adaptersdb.NewMuteRepoGorm(db)
+	followRequestRepo := adaptersdb.NewFollowRequestRepoGorm(db)
+	homeRepo := adaptersdb.NewHomeTimelineRepoGorm(db)
+	likeRepo := adaptersdb.NewLikeRepoGorm(db)
+	userRepo := adaptersdb.NewUserRepoGorm(db)
+	keyRepo := adaptersdb.NewAPIKeyRepoGorm(db)
+	refreshRepo := adaptersdb.NewRefreshTokenRepoGorm(db)
+	searcher, err := adaptersdb.NewTweetSearchGorm(context.Background(), db)
+	if err != nil {
+		_ = adaptersdb.Close(db)
+		return nil, nil, fmt.Errorf("search index: %w", err)
+	}
 	clock := adapterclock.SystemClock{}
 	idgen := adapterid.ULID{}
-	limiter := adaptershttp.NewRateLimiterFromEnv()
+	secrets := adapterid.Secret{}
+	accessTokens, err := adapterjwt.Open(adapterjwt.ConfigFromEnv(), clock)
+	if err != nil {
+		_ = adaptersdb.Close(db)
+		return nil, nil, err
+	}
+	// Rate limit (REDIS_URL lo comparte entre réplicas)
+	limiter, closeLimiter, err := adapterratelimit.Open(adapterratelimit.ConfigFromEnv())
+	if err != nil {
+		_ = adaptersdb.Close(db)
+		return nil, nil, err
+	}
 
 	// Use cases
-	postTweet := app.PostTweet{Tweets: tweetRepo, Clock: clock, IDGen: idgen}
-	getTimeline := app.GetTimeline{Tweets: tweetRepo, Follows: followRepo}
-	followUser := app.FollowUser{Follows: followRepo, Clock: clock, IDGen: idgen}
-	unfollowUser := app.UnfollowUser{Follows: followRepo}
+	postTweet := app.PostTweet{
+		Tweets: tweetRepo, Clock: clock, IDGen: idgen, Users: userRepo, Blocks: blockRepo,
+		Follows: followRepo, Home: homeRepo, FanoutMaxFollowers: envInt("FANOUT_MAX_FOLLOWERS", 10000),
+	}
+	editTweet := app.EditTweet{
+		Tweets: tweetRepo, Clock: clock, IDGen: idgen, Users: userRepo,
+		EditWindowSec: int64(envInt("EDIT_WINDOW_SEC", 1800)),
+	}
+	revisions := app.GetTweetRevisions{Tweets: tweetRepo, Users: userRepo, Follows: followRepo}
+	getThread := app.GetThread{Tweets: tweetRepo, Users: userRepo, Follows: followRepo}
+	retweet := app.Retweet{
+		Tweets: tweetRepo, Clock: clock, IDGen: idgen, Blocks: blockRepo, Users: userRepo,
+		Follows: followRepo, Home: homeRepo, FanoutMaxFollowers: postTweet.FanoutMaxFollowers,
+	}
+	unretweet := app.Unretweet{Tweets: tweetRepo, Home: homeRepo, Clock: clock}
+	deleteTweet := app.DeleteTweet{Tweets: tweetRepo, Home: homeRepo, Clock: clock}
+	getTimeline := app.GetTimeline{
+		Tweets: tweetRepo, Follows: followRepo, Home: homeRepo, Users: userRepo,
+		Blocks: blockRepo, Mutes: muteRepo, Clock: clock,
+	}
+	getUserTweets := app.GetUserTweets{Tweets: tweetRepo, Users: userRepo, Follows: followRepo}
+	hashtagTweets := app.GetHashtagTweets{Tweets: tweetRepo, Users: userRepo, Follows: followRepo}
+	userMentions := app.GetUserMentions{Tweets: tweetRepo, Users: userRepo, Follows: followRepo}
+	createUser := app.CreateUser{Users: userRepo, Clock: clock, IDGen: idgen, Keys: keyRepo, Secrets: secrets}
+	getUser := app.GetUser{Users: userRepo}
+	updateUser := app.UpdateUser{Users: userRepo, Clock: clock}
+	followUser := app.FollowUser{
+		Follows: followRepo, Clock: clock, IDGen: idgen, Users: userRepo, Blocks: blockRepo, Requests: followRequestRepo,
+		Tweets: tweetRepo, Home: homeRepo, BackfillLimit: envInt("FANOUT_BACKFILL_LIMIT", 200),
+	}
+	unfollowUser := app.UnfollowUser{Follows: followRepo, Home: homeRepo, Requests: followRequestRepo}
+	followers := app.GetFollowers{Users: userRepo, Follows: followRepo}
+	following := app.GetFollowing{Users: userRepo, Follows: followRepo}
+	blockUser := app.BlockUser{
+		Blocks: blockRepo, Follows: followRepo, Clock: clock, IDGen: idgen, Users: userRepo, Home: homeRepo,
+		Requests: followRequestRepo,
+	}
+	unblockUser := app.UnblockUser{Blocks: blockRepo}
+	getBlocks := app.GetBlocks{Users: userRepo, Blocks: blockRepo}
+	muteUser := app.MuteUser{Mutes: muteRepo, Clock: clock, IDGen: idgen, Users: userRepo}
+	unmuteUser := app.UnmuteUser{Mutes: muteRepo}
+	listMutes := app.ListMutes{Mutes: muteRepo, Clock: clock}
+	listFollowRequests := app.ListFollowRequests{Requests: followRequestRepo}
+	approveFollowRequest := app.ApproveFollowRequest{Requests: followRequestRepo, Follow: followUser}
+	rejectFollowRequest := app.RejectFollowRequest{Requests: followRequestRepo}
+	likeTweet := app.LikeTweet{
+		Tweets: tweetRepo, Likes: likeRepo, Clock: clock, IDGen: idgen, Blocks: blockRepo,
+		Users: userRepo, Follows: followRepo,
+	}
+	unlikeTweet := app.UnlikeTweet{Tweets: tweetRepo, Likes: likeRepo}
+	tweetLikes := app.GetTweetLikes{Tweets: tweetRepo, Likes: likeRepo, Users: userRepo, Follows: followRepo}
+	userLikes := app.GetUserLikes{Likes: likeRepo, Users: userRepo, Follows: followRepo}
+	searchTweets := app.SearchTweets{Search: searcher, Users: userRepo, Follows: followRepo}
+	issueKey := app.IssueAPIKey{Keys: keyRepo, Users: userRepo, Clock: clock, IDGen: idgen, Secrets: secrets}
+	listKeys := app.ListAPIKeys{Keys: keyRepo}
+	rotateKey := app.RotateAPIKey{Keys: keyRepo, Clock: clock, IDGen: idgen, Secrets: secrets}
+	revokeKey := app.RevokeAPIKey{Keys: keyRepo, Clock: clock}
+	tokenCfg := app.TokenConfig{
+		Tokens: accessTokens, Refresh: refreshRepo, Clock: clock, IDGen: idgen, Secrets: secrets,
+		AccessTTL:  int64(envInt("JWT_ACCESS_TTL_SEC", 900)),
+		RefreshTTL: int64(envInt("JWT_REFRESH_TTL_SEC", 30*24*3600)),
+	}
+	issueTokens := app.IssueTokens{TokenConfig: tokenCfg}
+	refreshTokens := app.RefreshTokens{TokenConfig: tokenCfg, Keys: keyRepo}
+	auth := app.Authenticate{Keys: keyRepo, Tokens: accessTokens}
+	adminUserTweets := app.AdminUserTweets{Tweets: tweetRepo}
+	hardDelete := app.HardDeleteTweet{Tweets: tweetRepo, Home: homeRepo}
+	suspendUser := app.SuspendUser{Users: userRepo, Clock: clock}
+	userFollows := app.GetUserFollows{Users: userRepo, Follows: followRepo}
 
 	// HTTP
-	h := adaptershttp.BuildHandlers(postTweet, getTimeline, followUser, unfollowUser, limiter)
+	h := adaptershttp.BuildHandlers(adaptershttp.Deps{
+		PostTweet: postTweet, EditTweet: editTweet, DeleteTweet: deleteTweet, Revisions: revisions, GetThread: getThread,
+		Retweet: retweet, Unretweet: unretweet, GetTimeline: getTimeline, GetUserTweets: getUserTweets,
+		HashtagTweets: hashtagTweets, UserMentions: userMentions, SearchTweets: searchTweets,
+
+		CreateUser: createUser, GetUser: getUser, UpdateUser: updateUser,
+		FollowUser: followUser, UnfollowUser: unfollowUser, Followers: followers, Following: following,
+		BlockUser: blockUser, UnblockUser: unblockUser, GetBlocks: getBlocks,
+		MuteUser: muteUser, UnmuteUser: unmuteUser, ListMutes: listMutes,
+		ListFollowRequests: listFollowRequests, ApproveFollowRequest: approveFollowRequest, RejectFollowRequest: rejectFollowRequest,
+
+		LikeTweet: likeTweet, UnlikeTweet: unlikeTweet, TweetLikes: tweetLikes, UserLikes: userLikes,
+
+		IssueKey: issueKey, ListKeys: listKeys, RotateKey: rotateKey, RevokeKey: revokeKey,
+		IssueTokens: issueTokens, RefreshTokens: refreshTokens, AccessTokens: accessTokens, Auth: auth,
+
+		AdminUserTweets: adminUserTweets, HardDelete: hardDelete, SuspendUser: suspendUser, UserFollows: userFollows,
+		Limiter: limiter,
+	})
 	r := adaptershttp.NewRouter(h)
 
-	shutdown := func() {}
+	shutdown := func() {
+		_ = closeLimiter()
+		_ = adaptersdb.Close(db)
+	}
 	return r, shutdown, nil
 }
+
+// autoMigrate: por defecto se aplican las migraciones pendientes al arrancar;
+// DB_AUTO_MIGRATE=false deja el esquema en manos de `server migrate up`.
+func autoMigrate() bool {
+	v := os.Getenv("DB_AUTO_MIGRATE")
+	return !(v == "0" || v == "false" || v == "False")
+}
+
+func envInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	ErrForbidden       = errors.New("forbidden")
+	ErrAPIKeyNotFound  = errors.New("api key not found")
+	ErrAPIKeyRevoked   = errors.New("api key revoked")
+	ErrAPIKeyName      = errors.New("api key name must be at most 50 characters")
+)
+
+// APIKeyPrefix identifica los tokens de este servicio (p. ej. para secret scanning).
+const APIKeyPrefix = "tck_"
+
+const (
+	maxAPIKeyName = 50
+	apiKeyHintLen = len(APIKeyPrefix) + 6
+)
+
+// APIKey es una credencial de un usuario. El token sólo se muestra al
+// emitirla; se guarda su SHA-256 (Hash) y Hint para reconocerla en listados.
+type APIKey struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Name      string `json:"name,omitempty"`
+	Hint      string `json:"hint"`
+	Hash      string `json:"-"`
+	CreatedAt int64  `json:"created_at"`
+	RevokedAt int64  `json:"revoked_at,omitempty"`
+}
+
+// NewAPIKey arma la key y su token a partir de un secreto aleatorio.
+func NewAPIKey(id, userID, name, secret string, createdAt int64) (APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if len([]rune(name)) > maxAPIKeyName {
+		return APIKey{}, "", ErrAPIKeyName
+	}
+	if userID == "" || secret == "" {
+		return APIKey{}, "", errors.New("user_id and secret required")
+	}
+	token := APIKeyPrefix + secret
+	return APIKey{
+		ID: id, UserID: userID, Name: name,
+		Hint: token[:min(apiKeyHintLen, len(token))], Hash: HashAPIKey(token),
+		CreatedAt: createdAt,
+	}, token, nil
+}
+
+// HashAPIKey es el valor persistido y buscado: los tokens tienen 256 bits de
+// entropía, así que un hash rápido sin sal alcanza.
+func HashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (k APIKey) Active() bool { return k.RevokedAt == 0 }
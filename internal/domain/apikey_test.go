@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	k, token, err := NewAPIKey("K1", "u1", "  ci  ", "abcdefghij", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "tck_abcdefghij" || k.Name != "ci" || k.Hint != "tck_abcdef" || !k.Active() {
+		t.Fatalf("unexpected key: %#v token=%q", k, token)
+	}
+	if k.Hash != HashAPIKey(token) || strings.Contains(k.Hash, "abcdefghij") {
+		t.Fatalf("hash must be derived from the token: %q", k.Hash)
+	}
+	if _, _, err := NewAPIKey("K1", "u1", strings.Repeat("x", 51), "s", 1); err != ErrAPIKeyName {
+		t.Fatalf("want ErrAPIKeyName, got %v", err)
+	}
+	if _, _, err := NewAPIKey("K1", "u1", "", "", 1); err == nil {
+		t.Fatal("empty secret must fail")
+	}
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "errors"
+
+// ErrBlocked: uno de los dos usuarios bloqueó al otro.
+var ErrBlocked = errors.New("user is blocked")
+
+type Block struct {
+	ID        string `json:"id"`
+	BlockerID string `json:"blocker_id"`
+	BlockedID string `json:"blocked_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func NewBlock(id, blockerID, blockedID string, createdAt int64) (Block, error) {
+	if blockerID == "" || blockedID == "" {
+		return Block{}, errors.New("both ids required")
+	}
+	if blockerID == blockedID {
+		return Block{}, errors.New("cannot block self")
+	}
+	return Block{ID: id, BlockerID: blockerID, BlockedID: blockedID, CreatedAt: createdAt}, nil
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "testing"
+
+func TestNewBlock_OK(t *testing.T) {
+	b, err := NewBlock("b1", "u1", "u2", 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b.BlockerID != "u1" || b.BlockedID != "u2" || b.CreatedAt != 42 {
+		t.Fatalf("unexpected block: %#v", b)
+	}
+}
+
+func TestNewBlock_Invalid(t *testing.T) {
+	if _, err := NewBlock("b1", "u1", "u1", 1); err == nil {
+		t.Fatal("expected error for self-block")
+	}
+	if _, err := NewBlock("b1", "", "u2", 1); err == nil {
+		t.Fatal("expected error for missing blocker")
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor es una posición de keyset (created_at, id). Newer indica la
+// dirección: false pide items más viejos que la posición (next_cursor),
+// true items más nuevos (prev_cursor).
+type Cursor struct {
+	CreatedAt int64
+	ID        string
+	Newer     bool
+}
+
+func (c Cursor) Encode() string {
+	dir := "o"
+	if c.Newer {
+		dir = "n"
+	}
+	raw := dir + "|" + strconv.FormatInt(c.CreatedAt, 10) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(b), "|", 3)
+	if len(parts) != 3 || parts[2] == "" || (parts[0] != "o" && parts[0] != "n") {
+		return Cursor{}, ErrInvalidCursor
+	}
+	ts, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: ts, ID: parts[2], Newer: parts[0] == "n"}, nil
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestCursor_RoundTrip(t *testing.T) {
+	for _, c := range []Cursor{
+		{CreatedAt: 123, ID: "01HXYZ"},
+		{CreatedAt: 0, ID: "a|b", Newer: true},
+	} {
+		got, err := DecodeCursor(c.Encode())
+		if err != nil {
+			t.Fatalf("decode %+v: %v", c, err)
+		}
+		if got != c {
+			t.Fatalf("got %+v want %+v", got, c)
+		}
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"", "%%%", "eA", Cursor{CreatedAt: 1}.Encode()} {
+		if _, err := DecodeCursor(s); err != ErrInvalidCursor {
+			t.Fatalf("DecodeCursor(%q) err=%v, want ErrInvalidCursor", s, err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package domain
 
 import "errors"
 
+var ErrFollowRequestNotFound = errors.New("follow request not found")
+
 type Follow struct {
 	ID         string `json:"id"`
 	FollowerID string `json:"follower_id"`
@@ -18,3 +20,17 @@ func NewFollow(id, followerID, followeeID string, createdAt int64) (Follow, erro
 	}
 	return Follow{ID: id, FollowerID: followerID, FolloweeID: followeeID, CreatedAt: createdAt}, nil
 }
+
+// FollowRequest es un follow pendiente hacia una cuenta protegida; al
+// aprobarse se convierte en Follow.
+type FollowRequest struct {
+	ID          string `json:"id"`
+	RequesterID string `json:"requester_id"`
+	TargetID    string `json:"target_id"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// Request convierte el follow (ya validado) en una solicitud pendiente.
+func (f Follow) Request() FollowRequest {
+	return FollowRequest{ID: f.ID, RequesterID: f.FollowerID, TargetID: f.FolloweeID, CreatedAt: f.CreatedAt}
+}
@@ -27,3 +27,11 @@ func TestNewFollow_Missing(t *testing.T) {
 		t.Fatal("expected error for missing followee")
 	}
 }
+
+func TestFollow_Request(t *testing.T) {
+	f, _ := NewFollow("f1", "u1", "u2", 42)
+	r := f.Request()
+	if r.ID != "f1" || r.RequesterID != "u1" || r.TargetID != "u2" || r.CreatedAt != 42 {
+		t.Fatalf("unexpected request: %#v", r)
+	}
+}
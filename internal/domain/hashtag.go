@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidHashtag = errors.New("invalid hashtag")
+
+// Un hashtag empieza con # (o ＃ de ancho completo) precedido por inicio de
+// texto o un carácter que no forme palabra, así "a#b" o "&#39;" no cuentan.
+var hashtagRe = regexp.MustCompile(`(?:^|[^\p{L}\p{M}\p{N}_&])[#＃]([\p{L}\p{M}\p{N}_]+)`)
+
+// ExtractHashtags devuelve los hashtags del texto normalizados (sin #, NFC y
+// en minúsculas), sin repetir y en orden de aparición. Ignora los que están
+// dentro de URLs y los que no tienen ninguna letra (#1).
+func ExtractHashtags(text string) []string {
+	text = urlRe.ReplaceAllString(NormalizeText(text), " ")
+	var out []string
+	seen := map[string]bool{}
+	for _, m := range hashtagRe.FindAllStringSubmatch(text, -1) {
+		tag, err := NormalizeHashtag(m[1])
+		if err != nil || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// NormalizeHashtag acepta el tag con o sin # y lo deja en la forma indexada.
+func NormalizeHashtag(tag string) (string, error) {
+	tag = strings.TrimLeft(NormalizeText(tag), "#＃")
+	hasLetter := false
+	for _, r := range tag {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsMark(r) || unicode.IsNumber(r) || r == '_':
+		default:
+			return "", ErrInvalidHashtag
+		}
+	}
+	if !hasLetter {
+		return "", ErrInvalidHashtag
+	}
+	return strings.ToLower(tag), nil
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"hola #Go y #golang", []string{"go", "golang"}},
+		{"#Campaña2024 arranca, #CAMPAÑA2024 otra vez", []string{"campaña2024"}},
+		{"japonés #東京 y ancho completo ＃ｔａｇ", []string{"東京", "ｔａｇ"}},
+		{"café #café", []string{"café"}},
+		{"sin tag: a#b, &#39; y #123", nil},
+		{"url https://example.com/page#frag #real", []string{"real"}},
+		{"#con_guion_bajo.", []string{"con_guion_bajo"}},
+	}
+	for _, c := range cases {
+		if got := ExtractHashtags(c.text); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ExtractHashtags(%q) = %#v, want %#v", c.text, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	if got, err := NormalizeHashtag("#GoLang"); err != nil || got != "golang" {
+		t.Fatalf("got %q err=%v", got, err)
+	}
+	for _, bad := range []string{"", "#", "123", "a b", "a-b"} {
+		if _, err := NormalizeHashtag(bad); err != ErrInvalidHashtag {
+			t.Errorf("NormalizeHashtag(%q): want ErrInvalidHashtag, got %v", bad, err)
+		}
+	}
+}
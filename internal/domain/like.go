@@ -0,0 +1,23 @@
+package domain
+
+import "errors"
+
+type Like struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	TweetID   string `json:"tweet_id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func NewLike(id, userID, tweetID string, createdAt int64) (Like, error) {
+	if userID == "" || tweetID == "" {
+		return Like{}, errors.New("user_id and tweet_id required")
+	}
+	return Like{ID: id, UserID: userID, TweetID: tweetID, CreatedAt: createdAt}, nil
+}
+
+// LikedTweet es un tweet en la lista de likes de un usuario; LikedAt define el orden.
+type LikedTweet struct {
+	Tweet
+	LikedAt int64 `json:"liked_at"`
+}
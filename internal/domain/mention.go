@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxMentionLength = 50
+
+// Mention es una referencia @handle dentro del texto. Los offsets cubren
+// "@handle" en el texto normalizado: bytes para Go/SQL y code points (Char*)
+// para clientes que indexan por carácter. UserID es el usuario al que resolvió
+// el handle al publicar (Tweet.ResolveMentions).
+type Mention struct {
+	UserID    string `json:"user_id"`
+	Handle    string `json:"handle,omitempty"` // vacío en tweets previos a la migración 0012
+	ByteStart int    `json:"byte_start"`
+	ByteEnd   int    `json:"byte_end"`
+	CharStart int    `json:"char_start"`
+	CharEnd   int    `json:"char_end"`
+}
+
+// Igual que en hashtags: "mail@ejemplo.com" o "a@b" no son menciones.
+var mentionRe = regexp.MustCompile(`(?:^|[^\p{L}\p{M}\p{N}_&@.])([@＠])([A-Za-z0-9_]+)`)
+
+// ExtractMentions devuelve las menciones candidatas del texto (ya normalizado),
+// en orden de aparición, sólo con Handle: el caso de uso las resuelve contra
+// ports.UserRepo.
+func ExtractMentions(text string) []Mention {
+	// Las URLs se tapan con espacios del mismo largo para no mover offsets.
+	masked := urlRe.ReplaceAllStringFunc(text, func(u string) string { return strings.Repeat(" ", len(u)) })
+	var out []Mention
+	for _, m := range mentionRe.FindAllStringSubmatchIndex(masked, -1) {
+		start, end := m[2], m[5]
+		if m[5]-m[4] > maxMentionLength {
+			continue
+		}
+		chars := utf8.RuneCountInString(text[:start])
+		out = append(out, Mention{
+			Handle:    text[m[4]:m[5]],
+			ByteStart: start, ByteEnd: end,
+			CharStart: chars, CharEnd: chars + utf8.RuneCountInString(text[start:end]),
+		})
+	}
+	return out
+}
+
+// MentionedHandles devuelve los handles mencionados (HandleKey) sin repetir.
+func MentionedHandles(ms []Mention) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, m := range ms {
+		if k := HandleKey(m.Handle); !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// MentionedIDs devuelve los usuarios mencionados sin repetir.
+func MentionedIDs(ms []Mention) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, m := range ms {
+		if !seen[m.UserID] {
+			seen[m.UserID] = true
+			out = append(out, m.UserID)
+		}
+	}
+	return out
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractMentions(t *testing.T) {
+	text := "¡Hola @ana_b! cc ＠Beto, no: mail@ejemplo.com https://x.com/@nadie"
+	got := ExtractMentions(text)
+	want := []Mention{
+		{Handle: "ana_b", ByteStart: 7, ByteEnd: 13, CharStart: 6, CharEnd: 12},
+		{Handle: "Beto", ByteStart: 18, ByteEnd: 25, CharStart: 17, CharEnd: 22},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtractMentions = %#v, want %#v", got, want)
+	}
+	for _, m := range got {
+		if s := text[m.ByteStart:m.ByteEnd]; s != "@"+m.Handle && s != "＠"+m.Handle {
+			t.Fatalf("offsets do not cover the mention: %q", s)
+		}
+	}
+}
+
+func TestTweetResolveMentions(t *testing.T) {
+	tw, _ := NewTweet("T1", "u1", "@Ana @b @ana", 1)
+	if hs := MentionedHandles(tw.Mentions); !reflect.DeepEqual(hs, []string{"ana", "b"}) {
+		t.Fatalf("handles = %v", hs)
+	}
+	tw.ResolveMentions(map[string]string{"ana": "U1"})
+	if ids := MentionedIDs(tw.Mentions); !reflect.DeepEqual(ids, []string{"U1"}) || len(tw.Mentions) != 2 || tw.Mentions[0].Handle != "Ana" {
+		t.Fatalf("unexpected mentions: %#v", tw.Mentions)
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrInvalidMute = errors.New("invalid mute")
+
+// Tipos de mute: una cuenta (Value = user id), una palabra o frase (se busca
+// completa, sin distinguir mayúsculas) o una expresión regular RE2.
+const (
+	MuteAccount = "account"
+	MuteKeyword = "keyword"
+	MuteRegex   = "regex"
+)
+
+const maxMuteValueLength = 100
+
+type Mute struct {
+	ID        string `json:"id"`
+	UserID    string `json:"user_id"`
+	Kind      string `json:"kind"`
+	Value     string `json:"value"`
+	ExpiresAt int64  `json:"expires_at,omitempty"` // 0 = no expira
+	CreatedAt int64  `json:"created_at"`
+}
+
+// NewMute valida y normaliza el mute: las palabras se guardan en NFC y en
+// minúsculas, así repetir un mute con otra capitalización lo reemplaza.
+func NewMute(id, userID, kind, value string, createdAt, expiresAt int64) (Mute, error) {
+	if userID == "" {
+		return Mute{}, errors.New("user_id required")
+	}
+	if expiresAt != 0 && expiresAt <= createdAt {
+		return Mute{}, errors.New("expires_at must be in the future")
+	}
+	value = strings.TrimSpace(value)
+	switch kind {
+	case MuteAccount:
+		if value == "" {
+			return Mute{}, errors.New("account required")
+		}
+		if value == userID {
+			return Mute{}, errors.New("cannot mute self")
+		}
+	case MuteKeyword:
+		value = strings.ToLower(NormalizeText(value))
+		if err := validMutePhrase(value); err != nil {
+			return Mute{}, err
+		}
+	case MuteRegex:
+		if err := validMutePhrase(value); err != nil {
+			return Mute{}, err
+		}
+		if _, err := compileMute(kind, value); err != nil {
+			return Mute{}, ErrInvalidMute
+		}
+	default:
+		return Mute{}, ErrInvalidMute
+	}
+	return Mute{ID: id, UserID: userID, Kind: kind, Value: value, ExpiresAt: expiresAt, CreatedAt: createdAt}, nil
+}
+
+func validMutePhrase(v string) error {
+	if v == "" || utf8.RuneCountInString(v) > maxMuteValueLength {
+		return ErrInvalidMute
+	}
+	for _, r := range v {
+		if unicode.IsControl(r) {
+			return ErrInvalidMute
+		}
+	}
+	return nil
+}
+
+func (m Mute) Expired(now int64) bool { return m.ExpiresAt != 0 && m.ExpiresAt <= now }
+
+// compileMute arma el patrón de un mute de texto. Las palabras matchean
+// completas: "gol" oculta "¡gol!" y "#gol" pero no "golazo".
+func compileMute(kind, value string) (*regexp.Regexp, error) {
+	if kind == MuteKeyword {
+		value = `(?:^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(value) + `(?:$|[^\p{L}\p{N}_])`
+	}
+	return regexp.Compile(`(?i)` + value)
+}
+
+// MuteFilter decide qué tweets oculta una lista de mutes activos.
+type MuteFilter struct {
+	accounts map[string]bool
+	phrases  []*regexp.Regexp
+}
+
+func NewMuteFilter(mutes []Mute) MuteFilter {
+	f := MuteFilter{accounts: map[string]bool{}}
+	for _, m := range mutes {
+		if m.Kind == MuteAccount {
+			f.accounts[m.Value] = true
+			continue
+		}
+		// los mutes guardados ya se validaron; uno inválido se ignora
+		if re, err := compileMute(m.Kind, m.Value); err == nil {
+			f.phrases = append(f.phrases, re)
+		}
+	}
+	return f
+}
+
+func (f MuteFilter) Empty() bool { return len(f.accounts) == 0 && len(f.phrases) == 0 }
+
+// Accounts son los ids de las cuentas silenciadas.
+func (f MuteFilter) Accounts() map[string]bool { return f.accounts }
+
+// Hides indica si el texto matchea alguna palabra o regex silenciada. El
+// texto se normaliza igual que las palabras.
+func (f MuteFilter) Hides(text string) bool {
+	if len(f.phrases) == 0 || text == "" {
+		return false
+	}
+	text = NormalizeText(text)
+	for _, re := range f.phrases {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
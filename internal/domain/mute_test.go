@@ -0,0 +1,60 @@
+package domain
+
+import "testing"
+
+func TestNewMute(t *testing.T) {
+	m, err := NewMute("m1", "u1", MuteKeyword, "  Spoiler ", 10, 0)
+	if err != nil || m.Value != "spoiler" || m.Expired(1<<40) {
+		t.Fatalf("keyword: %#v err=%v", m, err)
+	}
+	m, err = NewMute("m2", "u1", MuteAccount, "u2", 10, 20)
+	if err != nil || m.Expired(19) || !m.Expired(20) {
+		t.Fatalf("account: %#v err=%v", m, err)
+	}
+
+	for name, tc := range map[string]struct {
+		kind, value string
+		expires     int64
+	}{
+		"self":         {MuteAccount, "u1", 0},
+		"empty":        {MuteKeyword, "   ", 0},
+		"bad kind":     {"hashtag", "x", 0},
+		"bad regex":    {MuteRegex, "(", 0},
+		"control char": {MuteKeyword, "a\x00b", 0},
+		"past expiry":  {MuteKeyword, "x", 10},
+	} {
+		if _, err := NewMute("m", "u1", tc.kind, tc.value, 10, tc.expires); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestMuteFilter(t *testing.T) {
+	mutes := []Mute{
+		{Kind: MuteAccount, Value: "u2"},
+		{Kind: MuteKeyword, Value: "gol"},
+		{Kind: MuteKeyword, Value: "final del mundial"},
+		{Kind: MuteRegex, Value: `\bcompra(s|r)?\b`},
+	}
+	f := NewMuteFilter(mutes)
+	if f.Empty() || !f.Accounts()["u2"] || f.Accounts()["u3"] {
+		t.Fatalf("accounts: %v", f.Accounts())
+	}
+	for text, want := range map[string]bool{
+		"¡GOL!":                           true,
+		"#gol de media cancha":            true,
+		"qué golazo":                      false,
+		"mañana es la Final del  mundial": false,
+		"la final del mundial":            true,
+		"COMPRAR ya":                      true,
+		"comprador":                       false,
+		"":                                false,
+	} {
+		if got := f.Hides(text); got != want {
+			t.Errorf("Hides(%q) = %v, want %v", text, got, want)
+		}
+	}
+	if !NewMuteFilter(nil).Empty() {
+		t.Fatal("expected empty filter")
+	}
+}
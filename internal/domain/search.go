@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+const maxSearchQueryLength = 500
+
+type SearchOrder string
+
+const (
+	SearchRecent    SearchOrder = "recent"
+	SearchRelevance SearchOrder = "relevance"
+)
+
+// SearchQuery es una búsqueda ya parseada. Terms y Phrases se combinan con
+// AND; Since es inclusivo y Until exclusivo (unix, 0 = sin límite).
+type SearchQuery struct {
+	Terms   []string
+	Phrases []string
+	From    string
+	Since   int64
+	Until   int64
+	Order   SearchOrder
+}
+
+func (q SearchQuery) HasText() bool { return len(q.Terms) > 0 || len(q.Phrases) > 0 }
+
+// SearchHit es un resultado de búsqueda; Score ordena la página (created_at
+// en recent, relevancia escalada en relevance) y sólo se usa para el cursor.
+type SearchHit struct {
+	Tweet
+	Score int64 `json:"-"`
+}
+
+// ParseSearchQuery entiende palabras sueltas, "frases exactas" y los
+// operadores from:handle, since:AAAA-MM-DD y until:AAAA-MM-DD (UTC).
+func ParseSearchQuery(raw, order string) (SearchQuery, error) {
+	q := SearchQuery{Order: SearchRecent}
+	switch SearchOrder(order) {
+	case "", SearchRecent:
+	case SearchRelevance:
+		q.Order = SearchRelevance
+	default:
+		return SearchQuery{}, ErrInvalidSearchQuery
+	}
+	raw = NormalizeText(strings.TrimSpace(raw))
+	if raw == "" || len(raw) > maxSearchQueryLength {
+		return SearchQuery{}, ErrInvalidSearchQuery
+	}
+
+	tokens, phrases, err := splitSearch(raw)
+	if err != nil {
+		return SearchQuery{}, err
+	}
+	q.Phrases = phrases
+	for _, tok := range tokens {
+		key, val, ok := strings.Cut(tok, ":")
+		switch strings.ToLower(key) {
+		case "from":
+			if !ok || val == "" {
+				return SearchQuery{}, ErrInvalidSearchQuery
+			}
+			q.From = strings.TrimPrefix(val, "@")
+			continue
+		case "since", "until":
+			d, err := time.Parse(time.DateOnly, val)
+			if err != nil {
+				return SearchQuery{}, ErrInvalidSearchQuery
+			}
+			if strings.EqualFold(key, "since") {
+				q.Since = d.Unix()
+			} else {
+				q.Until = d.Unix()
+			}
+			continue
+		}
+		q.Terms = append(q.Terms, tok)
+	}
+	if !q.HasText() && q.From == "" {
+		return SearchQuery{}, ErrInvalidSearchQuery
+	}
+	if q.Since != 0 && q.Until != 0 && q.Until <= q.Since {
+		return SearchQuery{}, ErrInvalidSearchQuery
+	}
+	return q, nil
+}
+
+// splitSearch separa por espacios respetando comillas; las comillas sin
+// cerrar son error para no buscar algo distinto de lo que se pidió.
+func splitSearch(raw string) (tokens, phrases []string, err error) {
+	var cur strings.Builder
+	inQuote := false
+	flush := func() {
+		s := strings.TrimSpace(cur.String())
+		cur.Reset()
+		if s == "" {
+			return
+		}
+		if inQuote {
+			phrases = append(phrases, s)
+		} else {
+			tokens = append(tokens, s)
+		}
+	}
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			flush()
+			inQuote = !inQuote
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuote {
+		return nil, nil, ErrInvalidSearchQuery
+	}
+	flush()
+	return tokens, phrases, nil
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	q, err := ParseSearchQuery(`café "la esquina" from:@ana since:2024-01-02 until:2024-01-03`, "relevance")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := SearchQuery{
+		Terms:   []string{"café"},
+		Phrases: []string{"la esquina"},
+		From:    "ana",
+		Since:   1704153600,
+		Until:   1704240000,
+		Order:   SearchRelevance,
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Fatalf("got %#v", q)
+	}
+
+	if q, _ := ParseSearchQuery("from:bob", ""); q.From != "bob" || q.HasText() || q.Order != SearchRecent {
+		t.Fatalf("from only: %#v", q)
+	}
+	// NFC: la "e" con acento combinado se busca como "é"
+	if q, _ := ParseSearchQuery("cafe\u0301", ""); q.Terms[0] != "caf\u00e9" {
+		t.Fatalf("not normalized: %q", q.Terms[0])
+	}
+}
+
+func TestParseSearchQuery_Invalid(t *testing.T) {
+	for _, tc := range []struct{ raw, order string }{
+		{"", ""},
+		{"hola", "popular"},
+		{`"sin cerrar`, ""},
+		{"since:ayer", ""},
+		{"from:", ""},
+		{"since:2024-01-02", ""}, // sólo operadores de fecha: nada que buscar
+		{"x since:2024-01-03 until:2024-01-02", ""},
+	} {
+		if _, err := ParseSearchQuery(tc.raw, tc.order); err != ErrInvalidSearchQuery {
+			t.Fatalf("%q/%q: want ErrInvalidSearchQuery, got %v", tc.raw, tc.order, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	MaxTweetLength = 280
+	// URLWeight es lo que cuenta cada URL sin importar su largo (como t.co).
+	URLWeight = 23
+)
+
+var (
+	ErrTextLength    = errors.New("text length must be 1..280")
+	ErrTextInvisible = errors.New("text has no visible characters")
+	ErrTextControl   = errors.New("text contains control characters")
+)
+
+var urlRe = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"]+`)
+
+// NormalizeText lleva el texto a NFC para que "é" compuesta y "e"+acento
+// se guarden (y se busquen) igual.
+func NormalizeText(s string) string { return norm.NFC.String(s) }
+
+// TweetLength cuenta caracteres percibidos (grapheme clusters): un emoji con
+// modificadores o una letra con acento combinado valen 1; cada URL vale URLWeight.
+func TweetLength(s string) int {
+	n, last := 0, 0
+	for _, loc := range urlRe.FindAllStringIndex(s, -1) {
+		n += uniseg.GraphemeClusterCount(s[last:loc[0]]) + URLWeight
+		last = loc[1]
+	}
+	return n + uniseg.GraphemeClusterCount(s[last:])
+}
+
+// ValidateText normaliza y valida el texto de un tweet.
+func ValidateText(text string) (string, error) {
+	text = NormalizeText(text)
+	visible := false
+	for _, r := range text {
+		if unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t' {
+			return "", ErrTextControl
+		}
+		if !isInvisible(r) {
+			visible = true
+		}
+	}
+	if l := TweetLength(text); l == 0 || l > MaxTweetLength {
+		return "", ErrTextLength
+	}
+	if !visible {
+		return "", ErrTextInvisible
+	}
+	return text, nil
+}
+
+// isInvisible: espacios, formato (ZWSP, ZWJ, BOM...), selectores de variación
+// y rellenos que se renderizan en blanco.
+func isInvisible(r rune) bool {
+	switch r {
+	case 'ᅟ', 'ᅠ', 'ㅤ', 'ﾠ', '⠀':
+		return true
+	}
+	return unicode.IsSpace(r) || unicode.In(r, unicode.Cf, unicode.Variation_Selector)
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTweetLength_Graphemes(t *testing.T) {
+	cases := []struct {
+		text string
+		want int
+	}{
+		{"hola", 4},
+		{"canción ñandú", 13},
+		{"e\u0301", 1}, // e + acento combinado
+		{"👍🏽", 1},      // emoji + modificador de tono
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466", 1}, // familia (secuencia ZWJ)
+		{"🇦🇷", 1}, // bandera
+		{"mirá https://example.com/" + strings.Repeat("a", 100) + " ya", 5 + URLWeight + 3},
+	}
+	for _, c := range cases {
+		if got := TweetLength(c.text); got != c.want {
+			t.Errorf("TweetLength(%q) = %d, want %d", c.text, got, c.want)
+		}
+	}
+}
+
+func TestValidateText(t *testing.T) {
+	if _, err := ValidateText(strings.Repeat("🎉", 280)); err != nil {
+		t.Fatalf("280 emoji should be valid: %v", err)
+	}
+	if _, err := ValidateText(strings.Repeat("ñ", 281)); !errors.Is(err, ErrTextLength) {
+		t.Fatalf("want ErrTextLength, got %v", err)
+	}
+	for _, s := range []string{"   ", "\u200b\u200d", "\u3164", "\u2800 \ufe0f"} {
+		if _, err := ValidateText(s); !errors.Is(err, ErrTextInvisible) {
+			t.Fatalf("ValidateText(%q): want ErrTextInvisible, got %v", s, err)
+		}
+	}
+	if _, err := ValidateText("hola\x00"); !errors.Is(err, ErrTextControl) {
+		t.Fatalf("want ErrTextControl, got %v", err)
+	}
+	if _, err := ValidateText("línea 1\nlínea 2"); err != nil {
+		t.Fatalf("newlines should be allowed: %v", err)
+	}
+
+	got, err := ValidateText("cafe\u0301")
+	if err != nil || got != "caf\u00e9" {
+		t.Fatalf("expected NFC normalization, got %q err=%v", got, err)
+	}
+}
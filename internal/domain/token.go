@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInsufficientScope = errors.New("insufficient scope")
+	ErrInvalidScope      = errors.New("unknown scope")
+)
+
+// Scopes de los access tokens (JWT). Las API keys tienen acceso completo.
+const (
+	ScopeTweetsWrite  = "tweets:write"
+	ScopeFollowsWrite = "follows:write"
+	ScopeTimelineRead = "timeline:read"
+	ScopeFollowsRead  = "follows:read"  // listados privados: solicitudes, bloqueos, mutes
+	ScopeProfileWrite = "profile:write" // PATCH del propio usuario
+)
+
+// AllScopes es el default al emitir tokens sin pedir scopes explícitos.
+var AllScopes = []string{ScopeTweetsWrite, ScopeFollowsWrite, ScopeTimelineRead, ScopeFollowsRead, ScopeProfileWrite}
+
+// ParseScopes valida y normaliza (sin duplicados, orden de AllScopes) una
+// lista de scopes; vacía equivale a AllScopes.
+func ParseScopes(in []string) ([]string, error) {
+	if len(in) == 0 {
+		return slices.Clone(AllScopes), nil
+	}
+	out := make([]string, 0, len(in))
+	for _, s := range AllScopes {
+		if slices.Contains(in, s) {
+			out = append(out, s)
+		}
+	}
+	for _, s := range in {
+		if !slices.Contains(AllScopes, s) {
+			return nil, ErrInvalidScope
+		}
+	}
+	return out, nil
+}
+
+// AuthMethod indica con qué credencial se autenticó el caller.
+type AuthMethod string
+
+const (
+	AuthAPIKey AuthMethod = "api_key"
+	AuthJWT    AuthMethod = "jwt"
+)
+
+// Principal es el caller autenticado de un request.
+type Principal struct {
+	UserID string
+	Method AuthMethod
+	Scopes []string // sólo aplica a AuthJWT
+	KeyID  string   // sólo aplica a AuthAPIKey
+}
+
+func (p Principal) HasScope(scope string) bool {
+	return p.Method == AuthAPIKey || slices.Contains(p.Scopes, scope)
+}
+
+// AccessClaims es el contenido firmado de un access token.
+type AccessClaims struct {
+	UserID    string
+	Scopes    []string
+	IssuedAt  int64
+	ExpiresAt int64
+}
+
+// RefreshTokenPrefix distingue los refresh tokens de las API keys (tck_).
+const RefreshTokenPrefix = "tckr_"
+
+// RefreshToken es opaco y de un solo uso: refrescar lo revoca y emite otro.
+// Como las API keys, sólo se persiste su SHA-256. APIKeyID es la key que
+// inició la cadena: revocarla (o rotarla) corta también los refresh.
+type RefreshToken struct {
+	ID        string
+	UserID    string
+	APIKeyID  string
+	Hash      string
+	Scopes    []string
+	CreatedAt int64
+	ExpiresAt int64
+	RevokedAt int64
+}
+
+func NewRefreshToken(id, userID, apiKeyID, secret string, scopes []string, now, ttl int64) (RefreshToken, string, error) {
+	if userID == "" || apiKeyID == "" || secret == "" || ttl <= 0 {
+		return RefreshToken{}, "", errors.New("user_id, api_key_id, secret and ttl required")
+	}
+	token := RefreshTokenPrefix + secret
+	return RefreshToken{
+		ID: id, UserID: userID, APIKeyID: apiKeyID, Hash: HashAPIKey(token), Scopes: slices.Clone(scopes),
+		CreatedAt: now, ExpiresAt: now + ttl,
+	}, token, nil
+}
+
+// Usable: no revocado ni vencido en now.
+func (r RefreshToken) Usable(now int64) bool {
+	return r.RevokedAt == 0 && now < r.ExpiresAt
+}
+
+// JoinScopes arma el claim `scope` (separado por espacios, como en OAuth 2).
+func JoinScopes(scopes []string) string { return strings.Join(scopes, " ") }
+
+func SplitScopes(s string) []string { return strings.Fields(s) }
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseScopes(t *testing.T) {
+	got, err := ParseScopes(nil)
+	if err != nil || !slices.Equal(got, AllScopes) {
+		t.Fatalf("default = %v err=%v", got, err)
+	}
+	got, err = ParseScopes([]string{ScopeTimelineRead, ScopeTweetsWrite, ScopeTimelineRead})
+	if err != nil || !slices.Equal(got, []string{ScopeTweetsWrite, ScopeTimelineRead}) {
+		t.Fatalf("normalized = %v err=%v", got, err)
+	}
+	if _, err := ParseScopes([]string{"admin"}); err != ErrInvalidScope {
+		t.Fatalf("want ErrInvalidScope, got %v", err)
+	}
+}
+
+func TestPrincipalHasScope(t *testing.T) {
+	if !(Principal{Method: AuthAPIKey}).HasScope(ScopeFollowsWrite) {
+		t.Fatal("API keys have every scope")
+	}
+	p := Principal{Method: AuthJWT, Scopes: []string{ScopeTimelineRead}}
+	if p.HasScope(ScopeTweetsWrite) || !p.HasScope(ScopeTimelineRead) {
+		t.Fatalf("unexpected scopes check for %+v", p)
+	}
+}
+
+func TestNewRefreshToken(t *testing.T) {
+	rt, token, err := NewRefreshToken("R1", "u1", "K1", "secreto", []string{ScopeTweetsWrite}, 100, 50)
+	if err != nil || token != "tckr_secreto" || rt.Hash != HashAPIKey(token) || rt.APIKeyID != "K1" || rt.ExpiresAt != 150 {
+		t.Fatalf("unexpected token: %#v %q err=%v", rt, token, err)
+	}
+	if !rt.Usable(149) || rt.Usable(150) {
+		t.Fatal("refresh token must expire at ExpiresAt")
+	}
+	rt.RevokedAt = 120
+	if rt.Usable(121) {
+		t.Fatal("revoked refresh token must not be usable")
+	}
+}
@@ -2,19 +2,143 @@ package domain
 
 import "errors"
 
+var (
+	ErrTweetNotFound      = errors.New("tweet not found")
+	ErrNotAuthor          = errors.New("only the author can modify this tweet")
+	ErrEditWindowExpired  = errors.New("edit window expired")
+	ErrParentNotFound     = errors.New("in_reply_to tweet not found")
+	ErrQuotedNotFound     = errors.New("quoted tweet not found")
+	ErrRetweetNotEditable = errors.New("retweets cannot be edited")
+)
+
 type Tweet struct {
 	ID        string `json:"id"`
 	UserID    string `json:"user_id"`
 	Text      string `json:"text"`
 	CreatedAt int64  `json:"created_at"`
+	// Respuestas: ConversationID es el id del tweet raíz (el propio si no es respuesta).
+	InReplyToID    string `json:"in_reply_to_id,omitempty"`
+	ConversationID string `json:"conversation_id"`
+	// Un retweet es un tweet propio del que retwittea, sin texto, que apunta
+	// al original; una cita tiene texto y referencia otro tweet.
+	RetweetOfID   string    `json:"retweet_of_id,omitempty"`
+	QuotedTweetID string    `json:"quoted_tweet_id,omitempty"`
+	EditedAt      int64     `json:"edited_at,omitempty"`
+	DeletedAt     int64     `json:"deleted_at,omitempty"` // tombstone: != 0 si fue borrado
+	LikeCount     int64     `json:"like_count"`           // contador desnormalizado (ver LikeRepo)
+	Hashtags      []string  `json:"hashtags,omitempty"`   // derivados del texto (ExtractHashtags)
+	Mentions      []Mention `json:"mentions,omitempty"`   // sólo usuarios existentes (ver ResolveMentions)
+
+	// Sólo lectura (timelines): original de un retweet con su autor y quiénes
+	// lo retwittearon dentro de la página.
+	Retweeted   *Tweet   `json:"retweeted_tweet,omitempty"`
+	RetweetedBy []string `json:"retweeted_by,omitempty"`
+}
+
+// ResolveMentions completa el UserID de cada mención a partir de byHandle
+// (HandleKey -> id) y descarta las que no corresponden a ningún usuario.
+func (t *Tweet) ResolveMentions(byHandle map[string]string) {
+	var kept []Mention
+	for _, m := range t.Mentions {
+		if id, ok := byHandle[HandleKey(m.Handle)]; ok {
+			m.UserID = id
+			kept = append(kept, m)
+		}
+	}
+	t.Mentions = kept
+}
+
+// TweetRevision guarda un texto anterior de un tweet editado. CreatedAt es
+// desde cuándo estuvo vigente ese texto y ReplacedAt cuándo se reemplazó.
+type TweetRevision struct {
+	ID         string `json:"id"`
+	TweetID    string `json:"tweet_id"`
+	Revision   int    `json:"revision"`
+	Text       string `json:"text"`
+	CreatedAt  int64  `json:"created_at"`
+	ReplacedAt int64  `json:"replaced_at"`
 }
 
 func NewTweet(id, userID, text string, createdAt int64) (Tweet, error) {
 	if userID == "" {
 		return Tweet{}, errors.New("user_id required")
 	}
-	if l := len(text); l == 0 || l > 280 {
-		return Tweet{}, errors.New("text length must be 1..280")
+	text, err := ValidateText(text)
+	if err != nil {
+		return Tweet{}, err
+	}
+	return Tweet{
+		ID: id, UserID: userID, Text: text, CreatedAt: createdAt, ConversationID: id,
+		Hashtags: ExtractHashtags(text), Mentions: ExtractMentions(text),
+	}, nil
+}
+
+// NewReply crea un tweet que responde a parent, dentro de su conversación.
+func NewReply(id, userID, text string, createdAt int64, parent Tweet) (Tweet, error) {
+	t, err := NewTweet(id, userID, text, createdAt)
+	if err != nil {
+		return Tweet{}, err
+	}
+	t.InReplyToID = parent.ID
+	t.ConversationID = parent.ConversationID
+	if t.ConversationID == "" {
+		t.ConversationID = parent.ID
+	}
+	return t, nil
+}
+
+// NewRetweet crea el retweet de original por userID. Retwittear un retweet
+// apunta siempre al tweet original.
+func NewRetweet(id, userID string, createdAt int64, original Tweet) (Tweet, error) {
+	if userID == "" {
+		return Tweet{}, errors.New("user_id required")
+	}
+	target := original.ID
+	if original.RetweetOfID != "" {
+		target = original.RetweetOfID
+	}
+	return Tweet{ID: id, UserID: userID, CreatedAt: createdAt, ConversationID: id, RetweetOfID: target}, nil
+}
+
+// Quote marca t como cita de quoted. Citar un retweet cita el original.
+func (t *Tweet) Quote(quoted Tweet) {
+	t.QuotedTweetID = quoted.ID
+	if quoted.RetweetOfID != "" {
+		t.QuotedTweetID = quoted.RetweetOfID
+	}
+}
+
+// ThreadEntry es un tweet dentro del árbol de una conversación. Depth es 0
+// para la raíz; los tweets borrados aparecen como tombstones para no romper
+// la estructura.
+type ThreadEntry struct {
+	Tweet
+	Depth int    `json:"depth"`
+	Path  string `json:"-"` // orden DFS (raíz/.../id)
+}
+
+// Edit valida el nuevo texto con las mismas reglas que NewTweet y devuelve el
+// tweet actualizado junto con la revisión que preserva el texto anterior.
+// Sólo cambian el texto y lo derivado de él; el resto (respuesta, cita,
+// contadores) se conserva.
+func (t Tweet) Edit(revisionID, text string, now, window int64) (Tweet, TweetRevision, error) {
+	if t.RetweetOfID != "" {
+		return Tweet{}, TweetRevision{}, ErrRetweetNotEditable
+	}
+	if window > 0 && now-t.CreatedAt > window {
+		return Tweet{}, TweetRevision{}, ErrEditWindowExpired
+	}
+	next, err := NewTweet(t.ID, t.UserID, text, t.CreatedAt)
+	if err != nil {
+		return Tweet{}, TweetRevision{}, err
+	}
+	updated := t
+	updated.Text, updated.Hashtags, updated.Mentions = next.Text, next.Hashtags, next.Mentions
+	updated.EditedAt = now
+	since := t.CreatedAt
+	if t.EditedAt != 0 {
+		since = t.EditedAt
 	}
-	return Tweet{ID: id, UserID: userID, Text: text, CreatedAt: createdAt}, nil
+	rev := TweetRevision{ID: revisionID, TweetID: t.ID, Text: t.Text, CreatedAt: since, ReplacedAt: now}
+	return updated, rev, nil
 }
@@ -33,3 +33,46 @@ func TestNewTweet_TextLength(t *testing.T) {
 		t.Fatal("expected error for >280 chars")
 	}
 }
+
+func TestTweetEdit(t *testing.T) {
+	tw := Tweet{ID: "id1", UserID: "u1", Text: "hloa #go", CreatedAt: 100, ConversationID: "id0", InReplyToID: "id0", LikeCount: 3, Hashtags: []string{"go"}}
+
+	updated, rev, err := tw.Edit("r1", "hola", 130, 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Text != "hola" || updated.EditedAt != 130 || updated.CreatedAt != 100 ||
+		updated.LikeCount != 3 || updated.InReplyToID != "id0" || updated.ConversationID != "id0" || len(updated.Hashtags) != 0 {
+		t.Fatalf("unexpected tweet: %#v", updated)
+	}
+	if rev.Text != "hloa #go" || rev.TweetID != "id1" || rev.CreatedAt != 100 || rev.ReplacedAt != 130 {
+		t.Fatalf("unexpected revision: %#v", rev)
+	}
+
+	_, rev, _ = updated.Edit("r2", "hola!", 140, 60)
+	if rev.Text != "hola" || rev.CreatedAt != 130 {
+		t.Fatalf("second revision should start at previous edit: %#v", rev)
+	}
+}
+
+func TestTweetEdit_Rules(t *testing.T) {
+	tw := Tweet{ID: "id1", UserID: "u1", Text: "hola", CreatedAt: 100}
+	if _, _, err := tw.Edit("r1", "tarde", 161, 60); err != ErrEditWindowExpired {
+		t.Fatalf("want ErrEditWindowExpired, got %v", err)
+	}
+	if _, _, err := tw.Edit("r1", "", 110, 60); err == nil {
+		t.Fatal("expected validation error for empty text")
+	}
+}
+
+func TestNewReply_InheritsConversation(t *testing.T) {
+	root, _ := NewTweet("A", "u1", "root", 1)
+	reply, err := NewReply("B", "u2", "re", 2, root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	nested, _ := NewReply("C", "u1", "re re", 3, reply)
+	if reply.InReplyToID != "A" || nested.InReplyToID != "B" || nested.ConversationID != "A" || root.ConversationID != "A" {
+		t.Fatalf("unexpected conversation: %#v %#v", reply, nested)
+	}
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUnknownUser       = errors.New("user does not exist") // referido desde otro recurso (tweet, follow)
+	ErrHandleTaken       = errors.New("handle already taken")
+	ErrInvalidHandle     = errors.New("handle must be 1-15 letters, digits or _")
+	ErrDisplayNameLength = errors.New("display_name must be 1-50 characters on a single line")
+	ErrBioLength         = errors.New("bio must be at most 160 characters")
+	ErrSuspended         = errors.New("account is suspended")
+	ErrInvalidRole       = errors.New("role must be user or admin")
+)
+
+// Roles: admin habilita /v1/admin; se asigna por CLI, nunca por la API pública.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 160
+)
+
+// Mismo alfabeto que las menciones (@handle), con el largo de Twitter.
+var handleRe = regexp.MustCompile(`^[A-Za-z0-9_]{1,15}$`)
+
+// User es una cuenta. Handle es único sin distinguir mayúsculas (HandleKey)
+// y es lo que se escribe en las menciones; ID es estable aunque cambie.
+type User struct {
+	ID          string `json:"id"`
+	Handle      string `json:"handle"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	CreatedAt   int64  `json:"created_at"`
+	UpdatedAt   int64  `json:"updated_at"`
+	// Contadores desnormalizados: los mantiene la persistencia, no el dominio.
+	FollowerCount  int64  `json:"follower_count"`
+	FollowingCount int64  `json:"following_count"`
+	TweetCount     int64  `json:"tweet_count"` // tweets vivos propios (sin retweets)
+	Role           string `json:"role"`
+	SuspendedAt    int64  `json:"suspended_at,omitempty"` // 0 = activa
+	// Protected: sus tweets sólo los ven sus seguidores, que él aprueba.
+	Protected bool `json:"protected"`
+}
+
+func (u User) IsAdmin() bool   { return u.Role == RoleAdmin }
+func (u User) Suspended() bool { return u.SuspendedAt != 0 }
+
+// ValidRole indica si role es uno de los roles conocidos.
+func ValidRole(role string) bool { return role == RoleUser || role == RoleAdmin }
+
+// UserPatch son los cambios de perfil; nil = no tocar.
+type UserPatch struct {
+	Handle      *string
+	DisplayName *string
+	Bio         *string
+	Protected   *bool
+}
+
+// NewUser valida y normaliza un usuario nuevo; sin display name usa el handle.
+func NewUser(id, handle, displayName, bio string, createdAt int64) (User, error) {
+	u := User{ID: id, CreatedAt: createdAt, UpdatedAt: createdAt, Role: RoleUser}
+	if displayName == "" {
+		displayName = handle
+	}
+	return u.apply(UserPatch{Handle: &handle, DisplayName: &displayName, Bio: &bio})
+}
+
+// Update aplica el patch revalidando cada campo presente.
+func (u User) Update(p UserPatch, now int64) (User, error) {
+	updated, err := u.apply(p)
+	if err != nil {
+		return User{}, err
+	}
+	updated.UpdatedAt = now
+	return updated, nil
+}
+
+func (u User) apply(p UserPatch) (User, error) {
+	if p.Handle != nil {
+		if !handleRe.MatchString(*p.Handle) {
+			return User{}, ErrInvalidHandle
+		}
+		u.Handle = *p.Handle
+	}
+	if p.DisplayName != nil {
+		name := strings.TrimSpace(NormalizeText(*p.DisplayName))
+		if n := uniseg.GraphemeClusterCount(name); n == 0 || n > MaxDisplayNameLength || strings.ContainsAny(name, "\r\n") {
+			return User{}, ErrDisplayNameLength
+		}
+		u.DisplayName = name
+	}
+	if p.Bio != nil {
+		bio := strings.TrimSpace(NormalizeText(*p.Bio))
+		if uniseg.GraphemeClusterCount(bio) > MaxBioLength {
+			return User{}, ErrBioLength
+		}
+		u.Bio = bio
+	}
+	if p.Protected != nil {
+		u.Protected = *p.Protected
+	}
+	return u, nil
+}
+
+// HandleKey es la forma canónica de un handle para compararlo o buscarlo.
+func HandleKey(handle string) string { return strings.ToLower(handle) }
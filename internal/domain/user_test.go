@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewUser(t *testing.T) {
+	u, err := NewUser("U1", "Ana_B", "", "  hola  ", 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if u.Handle != "Ana_B" || u.DisplayName != "Ana_B" || u.Bio != "hola" || u.UpdatedAt != 10 {
+		t.Fatalf("unexpected user: %#v", u)
+	}
+	if u.Role != RoleUser || u.IsAdmin() || u.Suspended() {
+		t.Fatalf("new users must be active non-admins: %#v", u)
+	}
+	for _, h := range []string{"", "con espacio", "demasiado_largo_x", "ñandú", "@ana"} {
+		if _, err := NewUser("U1", h, "", "", 1); err != ErrInvalidHandle {
+			t.Fatalf("handle %q: want ErrInvalidHandle, got %v", h, err)
+		}
+	}
+	if _, err := NewUser("U1", "ana", "dos\nlíneas", "", 1); err != ErrDisplayNameLength {
+		t.Fatalf("want ErrDisplayNameLength, got %v", err)
+	}
+	if _, err := NewUser("U1", "ana", "", strings.Repeat("👍🏽", 161), 1); err != ErrBioLength {
+		t.Fatalf("want ErrBioLength, got %v", err)
+	}
+}
+
+func TestUserUpdate(t *testing.T) {
+	u, _ := NewUser("U1", "ana", "Ana", "bio", 10)
+	name := "Ana B."
+	got, err := u.Update(UserPatch{DisplayName: &name}, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.DisplayName != "Ana B." || got.Handle != "ana" || got.Bio != "bio" || got.UpdatedAt != 20 || got.CreatedAt != 10 {
+		t.Fatalf("unexpected user: %#v", got)
+	}
+	bad := "no válido"
+	if _, err := u.Update(UserPatch{Handle: &bad}, 20); err != ErrInvalidHandle {
+		t.Fatalf("want ErrInvalidHandle, got %v", err)
+	}
+	protected := true
+	if got, _ := got.Update(UserPatch{Protected: &protected}, 30); !got.Protected || got.DisplayName != "Ana B." {
+		t.Fatalf("protect: %#v", got)
+	}
+}
+
+func TestValidRole(t *testing.T) {
+	for _, r := range []string{RoleUser, RoleAdmin} {
+		if !ValidRole(r) {
+			t.Fatalf("%q should be valid", r)
+		}
+	}
+	for _, r := range []string{"", "Admin", "root"} {
+		if ValidRole(r) {
+			t.Fatalf("%q should be invalid", r)
+		}
+	}
+}
@@ -2,16 +2,27 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/alicebob/miniredis/v2"
+
+	adaptersdb "tweetschallenge/internal/adapters/db"
+	app "tweetschallenge/internal/application/usecase"
 	"tweetschallenge/internal/bootstrap"
 )
 
 func doReq(r http.Handler, method, path string, body any) *httptest.ResponseRecorder {
+	return doAs(r, "", method, path, body)
+}
+
+// doAs hace el request autenticado con la API key token (vacío = anónimo).
+func doAs(r http.Handler, token, method, path string, body any) *httptest.ResponseRecorder {
 	var buf *bytes.Buffer
 	if body != nil {
 		b, _ := json.Marshal(body)
@@ -21,11 +32,37 @@ func doReq(r http.Handler, method, path string, body any) *httptest.ResponseReco
 	}
 	req := httptest.NewRequest(method, path, buf)
 	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 	return w
 }
 
+// signUp da de alta usuarios por handle y devuelve handle -> id y
+// handle -> API key.
+func signUp(t *testing.T, r http.Handler, handles ...string) (ids, tokens map[string]string) {
+	t.Helper()
+	ids, tokens = map[string]string{}, map[string]string{}
+	for _, h := range handles {
+		w := doReq(r, http.MethodPost, "/v1/users", map[string]string{"handle": h})
+		var out struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+			APIKey struct {
+				Token string `json:"token"`
+			} `json:"api_key"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != http.StatusCreated {
+			t.Fatalf("sign up %s: %d %s", h, w.Code, w.Body.String())
+		}
+		ids[h], tokens[h] = out.Data.ID, out.APIKey.Token
+	}
+	return ids, tokens
+}
+
 func TestTimeline_OnlyFollowing(t *testing.T) {
 	// Desactivar rate limit para no interferir
 	os.Setenv("RATE_LIMIT_ENABLED", "false")
@@ -36,25 +73,24 @@ func TestTimeline_OnlyFollowing(t *testing.T) {
 		t.Fatalf("build server: %v", err)
 	}
 	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
 
 	// u1 y u2 postean, pero u1 sigue a u2; timeline de u1 debe traer SOLO de u2
-	w := doReq(router, http.MethodPost, "/v1/follows", map[string]string{
-		"follower_id": "u1", "followee_id": "u2",
-	})
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
 	if w.Code != http.StatusCreated {
 		t.Fatalf("follow status %d body %s", w.Code, w.Body.String())
 	}
 
-	w = doReq(router, http.MethodPost, "/v1/tweets", map[string]string{"user_id": "u1", "text": "mine"})
+	w = doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "mine"})
 	if w.Code != http.StatusCreated {
 		t.Fatalf("tweet u1 status %d body %s", w.Code, w.Body.String())
 	}
-	w = doReq(router, http.MethodPost, "/v1/tweets", map[string]string{"user_id": "u2", "text": "from u2"})
+	w = doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "from u2"})
 	if w.Code != http.StatusCreated {
 		t.Fatalf("tweet u2 status %d body %s", w.Code, w.Body.String())
 	}
 
-	w = doReq(router, http.MethodGet, "/v1/timeline/u1", nil)
+	w = doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
 	if w.Code != http.StatusOK {
 		t.Fatalf("timeline status %d body %s", w.Code, w.Body.String())
 	}
@@ -68,11 +104,47 @@ func TestTimeline_OnlyFollowing(t *testing.T) {
 	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
 		t.Fatalf("decode: %v body=%s", err, w.Body.String())
 	}
-	if len(out.Data) != 1 || out.Data[0].UserID != "u2" {
+	if len(out.Data) != 1 || out.Data[0].UserID != uid["u2"] {
 		t.Fatalf("want only tweets from u2, got: %+v", out.Data)
 	}
 }
 
+func TestTimeline_OnlyOwnerOrAdmin(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	t.Setenv("SQLITE_MODE", "file")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "tweets.db"))
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2", "mod")
+	doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
+	doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "from u2"})
+
+	path := "/v1/timeline/" + uid["u1"]
+	if w := doAs(router, tok["u1"], http.MethodGet, path, nil); w.Code != http.StatusOK {
+		t.Fatalf("own timeline: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["u2"], http.MethodGet, path, nil); w.Code != http.StatusForbidden {
+		t.Fatalf("someone else's timeline: want 403, got %d %s", w.Code, w.Body.String())
+	}
+
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	_, err = app.SetUserRole{Users: adaptersdb.NewUserRepoGorm(db)}.Exec(context.Background(), app.SetUserRoleInput{Handle: "mod", Role: "admin"})
+	_ = adaptersdb.Close(db)
+	if err != nil {
+		t.Fatalf("grant admin: %v", err)
+	}
+	if w := doAs(router, tok["mod"], http.MethodGet, path, nil); w.Code != http.StatusOK {
+		t.Fatalf("admin: want 200, got %d %s", w.Code, w.Body.String())
+	}
+}
+
 func TestRateLimit_CreateTweet(t *testing.T) {
 	os.Setenv("RATE_LIMIT_ENABLED", "true")
 	os.Setenv("RATE_LIMIT_MAX_TWEETS", "1")
@@ -88,35 +160,74 @@ func TestRateLimit_CreateTweet(t *testing.T) {
 		t.Fatalf("build server: %v", err)
 	}
 	defer shutdown()
+	_, tok := signUp(t, router, "u1")
 
 	// Primer tweet: 201
-	w := doReq(router, http.MethodPost, "/v1/tweets", map[string]string{"user_id": "u1", "text": "hola"})
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "hola"})
 	if w.Code != http.StatusCreated {
 		t.Fatalf("first tweet status %d body %s", w.Code, w.Body.String())
 	}
 	// Segundo en misma ventana: 429
-	w = doReq(router, http.MethodPost, "/v1/tweets", map[string]string{"user_id": "u1", "text": "spam"})
+	w = doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "spam"})
 	if w.Code != http.StatusTooManyRequests {
 		t.Fatalf("expected 429, got %d body %s", w.Code, w.Body.String())
 	}
 }
 
+func TestRateLimit_SharedAcrossReplicasWithRedis(t *testing.T) {
+	mr := miniredis.RunT(t)
+	t.Setenv("REDIS_URL", "redis://"+mr.Addr())
+	t.Setenv("RATE_LIMIT_MAX_TWEETS", "2")
+	t.Setenv("SQLITE_MODE", "file")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "tweets.db"))
+
+	// dos máquinas detrás del mismo balanceador: misma base, mismo Redis
+	a, shutdownA, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build a: %v", err)
+	}
+	defer shutdownA()
+	b, shutdownB, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build b: %v", err)
+	}
+	defer shutdownB()
+	_, tok := signUp(t, a, "u1")
+
+	for i, r := range []http.Handler{a, b, a} {
+		want := http.StatusCreated
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if w := doAs(r, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "hola"}); w.Code != want {
+			t.Fatalf("tweet %d: want %d, got %d %s", i, want, w.Code, w.Body.String())
+		}
+	}
+
+	// con Redis caído se deja publicar en lugar de cortar el servicio
+	mr.Close()
+	if w := doAs(b, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "sigue"}); w.Code != http.StatusCreated {
+		t.Fatalf("redis down: want 201, got %d %s", w.Code, w.Body.String())
+	}
+}
+
 func TestFollow_Idempotent(t *testing.T) {
 	router, shutdown, err := bootstrap.BuildHTTPServer()
 	if err != nil {
 		t.Fatalf("build: %v", err)
 	}
 	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
 
-	body := map[string]string{"follower_id": "u1", "followee_id": "u2"}
+	body := map[string]string{"followee_id": uid["u2"]}
 
-	w := doReq(router, http.MethodPost, "/v1/follows", body)
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/follows", body)
 	if w.Code != http.StatusCreated {
 		t.Fatalf("1st follow got %d", w.Code)
 	}
 
 	// repetir mismo follow: debe seguir OK (idempotente)
-	w = doReq(router, http.MethodPost, "/v1/follows", body)
+	w = doAs(router, tok["u1"], http.MethodPost, "/v1/follows", body)
 	if w.Code != http.StatusCreated {
 		t.Fatalf("2nd follow got %d", w.Code)
 	}
@@ -128,11 +239,10 @@ func TestUnfollow_Idempotent(t *testing.T) {
 		t.Fatalf("build: %v", err)
 	}
 	defer shutdown()
+	_, tok := signUp(t, router, "uX")
 
 	// hacer unfollow de un par inexistente: 204 igual (idempotente)
-	w := doReq(router, http.MethodDelete, "/v1/follows", map[string]string{
-		"follower_id": "uX", "followee_id": "uY",
-	})
+	w := doAs(router, tok["uX"], http.MethodDelete, "/v1/follows", map[string]string{"followee_id": "uY"})
 	if w.Code != http.StatusNoContent {
 		t.Fatalf("unfollow non-existing got %d body=%s", w.Code, w.Body.String())
 	}
@@ -144,9 +254,10 @@ func TestCreateTweet_BadPayload(t *testing.T) {
 		t.Fatalf("build: %v", err)
 	}
 	defer shutdown()
+	_, tok := signUp(t, router, "u1")
 
-	// falta user_id y/o text => 400
-	w := doReq(router, http.MethodPost, "/v1/tweets", map[string]string{})
+	// falta text => 400
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{})
 	if w.Code != http.StatusBadRequest {
 		t.Fatalf("bad payload got %d body=%s", w.Code, w.Body.String())
 	}
@@ -161,13 +272,14 @@ func TestRateLimit_Disabled_AllowsMultiple(t *testing.T) {
 		t.Fatalf("build: %v", err)
 	}
 	defer shutdown()
+	_, tok := signUp(t, router, "u1")
 
 	// con RL deshabilitado, dos tweets del mismo user en la misma ventana deben ser 201 ambos
-	w := doReq(router, http.MethodPost, "/v1/tweets", map[string]string{"user_id": "u1", "text": "a"})
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "a"})
 	if w.Code != http.StatusCreated {
 		t.Fatalf("1st tweet got %d", w.Code)
 	}
-	w = doReq(router, http.MethodPost, "/v1/tweets", map[string]string{"user_id": "u1", "text": "b"})
+	w = doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "b"})
 	if w.Code != http.StatusCreated {
 		t.Fatalf("2nd tweet got %d", w.Code)
 	}
@@ -182,8 +294,9 @@ func TestTimeline_EmptyWhenNoFollowing(t *testing.T) {
 		t.Fatalf("build: %v", err)
 	}
 	defer shutdown()
+	uid, tok := signUp(t, router, "u1")
 
-	w := doReq(router, http.MethodGet, "/v1/timeline/u1", nil)
+	w := doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
 	if w.Code != http.StatusOK {
 		t.Fatalf("status %d body=%s", w.Code, w.Body.String())
 	}
@@ -197,3 +310,1474 @@ func TestTimeline_EmptyWhenNoFollowing(t *testing.T) {
 		t.Fatalf("expected empty timeline, got %v", out.Data)
 	}
 }
+
+func TestSQLiteFileMode_PersistsAcrossRestarts(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	t.Setenv("SQLITE_MODE", "file")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "tweets.db"))
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	uid, tok := signUp(t, router, "u1", "u2")
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("follow status %d body %s", w.Code, w.Body.String())
+	}
+	w = doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "sobrevive"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("tweet status %d body %s", w.Code, w.Body.String())
+	}
+	shutdown()
+
+	// "reinicio": nuevo servidor sobre el mismo archivo
+	router, shutdown, err = bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("rebuild: %v", err)
+	}
+	defer shutdown()
+
+	w = doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
+	var out struct {
+		Data []struct {
+			Text string `json:"text"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v body=%s", err, w.Body.String())
+	}
+	if len(out.Data) != 1 || out.Data[0].Text != "sobrevive" {
+		t.Fatalf("want tweet to survive restart, got %+v", out.Data)
+	}
+}
+
+func TestTimeline_CursorPagination(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
+
+	doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
+	for _, txt := range []string{"t1", "t2", "t3"} {
+		if w := doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": txt}); w.Code != http.StatusCreated {
+			t.Fatalf("tweet status %d", w.Code)
+		}
+	}
+
+	type page struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+		Paging struct {
+			NextCursor string `json:"next_cursor"`
+			PrevCursor string `json:"prev_cursor"`
+		} `json:"paging"`
+	}
+	get := func(path string) page {
+		w := doAs(router, tok["u1"], http.MethodGet, path, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status %d body %s", path, w.Code, w.Body.String())
+		}
+		var p page
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return p
+	}
+
+	seen := map[string]bool{}
+	p := get("/v1/timeline/" + uid["u1"] + "?limit=2")
+	for _, d := range p.Data {
+		seen[d.ID] = true
+	}
+	if len(p.Data) != 2 || p.Paging.NextCursor == "" {
+		t.Fatalf("first page: %+v", p)
+	}
+	p = get("/v1/timeline/" + uid["u1"] + "?limit=2&cursor=" + p.Paging.NextCursor)
+	if len(p.Data) != 1 || seen[p.Data[0].ID] || p.Paging.NextCursor != "" {
+		t.Fatalf("second page: %+v", p)
+	}
+
+	if w := doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"]+"?cursor=garbage", nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid cursor got %d", w.Code)
+	}
+	if w := doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"]+"?offset=1", nil); w.Code != http.StatusOK || w.Header().Get("Deprecation") == "" {
+		t.Fatalf("offset fallback got %d deprecation=%q", w.Code, w.Header().Get("Deprecation"))
+	}
+}
+
+func TestTimeline_BackfillOnFollowAndPurgeOnUnfollow(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
+
+	// u2 postea antes de que u1 lo siga: el follow debe traer su historial
+	doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "viejo"})
+	pair := map[string]string{"followee_id": uid["u2"]}
+	doAs(router, tok["u1"], http.MethodPost, "/v1/follows", pair)
+
+	count := func() int {
+		w := doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
+		var out struct {
+			Data []any `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return len(out.Data)
+	}
+	if n := count(); n != 1 {
+		t.Fatalf("want backfilled tweet, got %d", n)
+	}
+	doAs(router, tok["u1"], http.MethodDelete, "/v1/follows", pair)
+	if n := count(); n != 0 {
+		t.Fatalf("want empty timeline after unfollow, got %d", n)
+	}
+}
+
+func TestUserTweets_AndIncludeSelf(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
+
+	doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
+	doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "mine"})
+	doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "from u2"})
+
+	users := func(path string) []string {
+		w := doAs(router, tok["u1"], http.MethodGet, path, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s status %d body %s", path, w.Code, w.Body.String())
+		}
+		var out struct {
+			Data []struct {
+				UserID string `json:"user_id"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		var ids []string
+		for _, d := range out.Data {
+			ids = append(ids, d.UserID)
+		}
+		return ids
+	}
+
+	if got := users("/v1/users/" + uid["u1"] + "/tweets"); len(got) != 1 || got[0] != uid["u1"] {
+		t.Fatalf("profile tweets = %v", got)
+	}
+	if got := users("/v1/timeline/" + uid["u1"] + "?include_self=true"); len(got) != 2 {
+		t.Fatalf("timeline with self = %v", got)
+	}
+	if got := users("/v1/timeline/" + uid["u1"]); len(got) != 1 || got[0] != uid["u2"] {
+		t.Fatalf("timeline without self = %v", got)
+	}
+}
+
+func TestDeleteTweet_OwnershipAndTombstone(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
+
+	doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
+	w := doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "borrame"})
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	path := "/v1/tweets/" + created.Data.ID
+
+	if w := doAs(router, tok["u1"], http.MethodDelete, path, nil); w.Code != http.StatusForbidden {
+		t.Fatalf("delete by non-author got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["u2"], http.MethodDelete, path, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("delete by author got %d body=%s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["u2"], http.MethodDelete, path, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("second delete got %d", w.Code)
+	}
+
+	w = doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
+	var out struct {
+		Data []any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(out.Data) != 0 {
+		t.Fatalf("deleted tweet still in timeline: %v", out.Data)
+	}
+}
+
+func TestEditTweet_WithRevisions(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	_, tok := signUp(t, router, "u1", "u2")
+
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "hloa"})
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	path := "/v1/tweets/" + created.Data.ID
+
+	if w := doAs(router, tok["u2"], http.MethodPatch, path, map[string]string{"text": "hack"}); w.Code != http.StatusForbidden {
+		t.Fatalf("edit by non-author got %d", w.Code)
+	}
+	doAs(router, tok["u2"], http.MethodPost, path+"/likes", nil)
+	w = doAs(router, tok["u1"], http.MethodPatch, path, map[string]string{"text": "hola"})
+	var edited struct {
+		Data struct {
+			Text      string `json:"text"`
+			LikeCount int64  `json:"like_count"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &edited); err != nil || w.Code != http.StatusOK ||
+		edited.Data.Text != "hola" || edited.Data.LikeCount != 1 {
+		t.Fatalf("edit got %d body=%s", w.Code, w.Body.String())
+	}
+
+	w = doReq(router, http.MethodGet, path+"/revisions", nil)
+	var revs struct {
+		Data []struct {
+			Text     string `json:"text"`
+			Revision int    `json:"revision"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &revs); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(revs.Data) != 1 || revs.Data[0].Text != "hloa" || revs.Data[0].Revision != 1 {
+		t.Fatalf("unexpected revisions: %+v", revs.Data)
+	}
+	if w := doReq(router, http.MethodGet, "/v1/tweets/nope/revisions", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("revisions of missing tweet got %d", w.Code)
+	}
+}
+
+func TestReplies_Thread(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	_, tok := signUp(t, router, "u1", "u2")
+
+	post := func(token, text, parent string) string {
+		t.Helper()
+		w := doAs(router, token, http.MethodPost, "/v1/tweets", map[string]string{"text": text, "in_reply_to_id": parent})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("post %q got %d body=%s", text, w.Code, w.Body.String())
+		}
+		var out struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return out.Data.ID
+	}
+	root := post(tok["u1"], "root", "")
+	reply := post(tok["u2"], "reply", root)
+	post(tok["u1"], "nested", reply)
+
+	if w := doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "x", "in_reply_to_id": "nope"}); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reply to missing tweet got %d", w.Code)
+	}
+
+	w := doReq(router, http.MethodGet, "/v1/tweets/"+reply+"/thread?limit=2", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("thread got %d body=%s", w.Code, w.Body.String())
+	}
+	var page struct {
+		Data []struct {
+			Text           string `json:"text"`
+			Depth          int    `json:"depth"`
+			InReplyToID    string `json:"in_reply_to_id"`
+			ConversationID string `json:"conversation_id"`
+		} `json:"data"`
+		Paging struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"paging"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 2 || page.Data[0].Text != "root" || page.Data[1].Depth != 1 || page.Data[1].InReplyToID != root || page.Data[1].ConversationID != root {
+		t.Fatalf("unexpected first page: %+v", page.Data)
+	}
+	if page.Paging.NextCursor == "" {
+		t.Fatal("expected next_cursor")
+	}
+	w = doReq(router, http.MethodGet, "/v1/tweets/"+root+"/thread?limit=2&cursor="+page.Paging.NextCursor, nil)
+	page.Data = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].Text != "nested" || page.Data[0].Depth != 2 {
+		t.Fatalf("unexpected second page: %+v", page.Data)
+	}
+	if w := doReq(router, http.MethodGet, "/v1/tweets/nope/thread", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("thread of missing tweet got %d", w.Code)
+	}
+}
+
+func TestRetweets_TimelineDedupAndUndo(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2", "u3", "author")
+
+	w := doAs(router, tok["author"], http.MethodPost, "/v1/tweets", map[string]string{"text": "original"})
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	rtPath := "/v1/tweets/" + created.Data.ID + "/retweets"
+
+	for _, u := range []string{"u2", "u3"} {
+		doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid[u]})
+		if w := doAs(router, tok[u], http.MethodPost, rtPath, nil); w.Code != http.StatusCreated {
+			t.Fatalf("retweet by %s got %d body=%s", u, w.Code, w.Body.String())
+		}
+	}
+	if w := doAs(router, tok["u2"], http.MethodPost, rtPath, nil); w.Code != http.StatusOK {
+		t.Fatalf("repeated retweet got %d", w.Code)
+	}
+	if w := doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "cita", "quoted_tweet_id": created.Data.ID}); w.Code != http.StatusCreated {
+		t.Fatalf("quote got %d body=%s", w.Code, w.Body.String())
+	}
+
+	type item struct {
+		Text           string   `json:"text"`
+		QuotedTweetID  string   `json:"quoted_tweet_id"`
+		RetweetedBy    []string `json:"retweeted_by"`
+		RetweetedTweet *struct {
+			UserID string `json:"user_id"`
+			Text   string `json:"text"`
+		} `json:"retweeted_tweet"`
+	}
+	timeline := func() []item {
+		t.Helper()
+		w := doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
+		var out struct {
+			Data []item `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return out.Data
+	}
+	items := timeline()
+	if len(items) != 2 || items[0].QuotedTweetID != created.Data.ID {
+		t.Fatalf("expected quote + one collapsed retweet, got %+v", items)
+	}
+	rt := items[1]
+	if rt.RetweetedTweet == nil || rt.RetweetedTweet.UserID != uid["author"] || rt.RetweetedTweet.Text != "original" || len(rt.RetweetedBy) != 2 {
+		t.Fatalf("unexpected retweet item: %+v", rt)
+	}
+
+	for _, u := range []string{"u2", "u3"} {
+		if w := doAs(router, tok[u], http.MethodDelete, rtPath, nil); w.Code != http.StatusNoContent {
+			t.Fatalf("undo retweet got %d", w.Code)
+		}
+	}
+	if items := timeline(); len(items) != 1 {
+		t.Fatalf("expected only the quote after undo, got %+v", items)
+	}
+	if w := doAs(router, tok["u2"], http.MethodPost, "/v1/tweets/nope/retweets", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("retweet of missing tweet got %d", w.Code)
+	}
+}
+
+func TestLikes_CountersAndListings(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2", "u3")
+
+	w := doAs(router, tok["u2"], http.MethodPost, "/v1/tweets", map[string]string{"text": "likeame"})
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	likesPath := "/v1/tweets/" + created.Data.ID + "/likes"
+	for _, u := range []string{"u1", "u1", "u3"} {
+		if w := doAs(router, tok[u], http.MethodPost, likesPath, nil); w.Code != http.StatusCreated {
+			t.Fatalf("like got %d body=%s", w.Code, w.Body.String())
+		}
+	}
+	doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
+
+	w = doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
+	var tl struct {
+		Data []struct {
+			LikeCount int `json:"like_count"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &tl); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(tl.Data) != 1 || tl.Data[0].LikeCount != 2 {
+		t.Fatalf("unexpected timeline: %s", w.Body.String())
+	}
+
+	w = doReq(router, http.MethodGet, likesPath, nil)
+	var likers struct {
+		Data []struct {
+			UserID string `json:"user_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &likers); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(likers.Data) != 2 {
+		t.Fatalf("unexpected likers: %s", w.Body.String())
+	}
+
+	if w := doAs(router, tok["u1"], http.MethodDelete, likesPath, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("unlike got %d", w.Code)
+	}
+	w = doReq(router, http.MethodGet, "/v1/users/"+uid["u3"]+"/likes", nil)
+	var liked struct {
+		Data []struct {
+			ID        string `json:"id"`
+			LikeCount int    `json:"like_count"`
+			LikedAt   int64  `json:"liked_at"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &liked); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(liked.Data) != 1 || liked.Data[0].ID != created.Data.ID || liked.Data[0].LikeCount != 1 || liked.Data[0].LikedAt == 0 {
+		t.Fatalf("unexpected user likes: %s", w.Body.String())
+	}
+	if w := doAs(router, tok["u1"], http.MethodPost, "/v1/tweets/nope/likes", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("like of missing tweet got %d", w.Code)
+	}
+}
+
+func TestHashtags_Timeline(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2", "u3")
+
+	for _, p := range []struct{ user, text string }{
+		{"u1", "lanzamos #Verano2025"},
+		{"u2", "nada que ver"},
+		{"u3", "me sumo a #verano2025 y #promo"},
+	} {
+		if w := doAs(router, tok[p.user], http.MethodPost, "/v1/tweets", map[string]string{"text": p.text}); w.Code != http.StatusCreated {
+			t.Fatalf("post got %d", w.Code)
+		}
+	}
+
+	w := doReq(router, http.MethodGet, "/v1/hashtags/VERANO2025/tweets?limit=1", nil)
+	var page struct {
+		Data []struct {
+			UserID   string   `json:"user_id"`
+			Hashtags []string `json:"hashtags"`
+		} `json:"data"`
+		Paging struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"paging"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].UserID != uid["u3"] || len(page.Data[0].Hashtags) != 2 || page.Paging.NextCursor == "" {
+		t.Fatalf("unexpected first page: %s", w.Body.String())
+	}
+	w = doReq(router, http.MethodGet, "/v1/hashtags/verano2025/tweets?limit=1&cursor="+page.Paging.NextCursor, nil)
+	page.Data = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].UserID != uid["u1"] {
+		t.Fatalf("unexpected second page: %s", w.Body.String())
+	}
+	if w := doReq(router, http.MethodGet, "/v1/hashtags/123/tweets", nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid tag got %d", w.Code)
+	}
+}
+
+func TestMentions_ResolvedAndListed(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "ana")
+
+	doAs(router, tok["ana"], http.MethodPost, "/v1/tweets", map[string]string{"text": "hola"})
+	w := doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"text": "cc @ana y @anaa"})
+	var created struct {
+		Data struct {
+			Mentions []struct {
+				UserID    string `json:"user_id"`
+				ByteStart int    `json:"byte_start"`
+				CharEnd   int    `json:"char_end"`
+			} `json:"mentions"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if m := created.Data.Mentions; len(m) != 1 || m[0].UserID != uid["ana"] || m[0].ByteStart != 3 || m[0].CharEnd != 7 {
+		t.Fatalf("unexpected mentions: %s", w.Body.String())
+	}
+
+	w = doReq(router, http.MethodGet, "/v1/users/"+uid["ana"]+"/mentions", nil)
+	var page struct {
+		Data []struct {
+			UserID string `json:"user_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].UserID != uid["u1"] {
+		t.Fatalf("unexpected mentions timeline: %s", w.Body.String())
+	}
+	w = doReq(router, http.MethodGet, "/v1/users/anaa/mentions", nil)
+	page.Data = nil
+	_ = json.Unmarshal(w.Body.Bytes(), &page)
+	if len(page.Data) != 0 {
+		t.Fatalf("typo should not be mentioned: %s", w.Body.String())
+	}
+}
+
+func TestSearch_OperatorsAndPaging(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
+
+	for _, p := range []struct{ user, text string }{
+		{"u1", "se cayó el login otra vez"},
+		{"u2", "el login anda lento"},
+		{"u1", "login caído de nuevo"},
+		{"u2", "todo bien por acá"},
+	} {
+		if w := doAs(router, tok[p.user], http.MethodPost, "/v1/tweets", map[string]string{"text": p.text}); w.Code != http.StatusCreated {
+			t.Fatalf("post got %d", w.Code)
+		}
+	}
+
+	var page struct {
+		Data []struct {
+			UserID string `json:"user_id"`
+			Text   string `json:"text"`
+		} `json:"data"`
+		Paging struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"paging"`
+	}
+	w := doReq(router, http.MethodGet, "/v1/search?q=login+from:u1&limit=1", nil)
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].Text != "login caído de nuevo" || page.Paging.NextCursor == "" {
+		t.Fatalf("unexpected first page: %s", w.Body.String())
+	}
+	w = doReq(router, http.MethodGet, "/v1/search?q=login+from:u1&limit=1&cursor="+page.Paging.NextCursor, nil)
+	page.Data = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Data) != 1 || page.Data[0].Text != "se cayó el login otra vez" {
+		t.Fatalf("unexpected second page: %s", w.Body.String())
+	}
+
+	w = doReq(router, http.MethodGet, `/v1/search?q=%22anda+lento%22`, nil)
+	page.Data = nil
+	_ = json.Unmarshal(w.Body.Bytes(), &page)
+	if len(page.Data) != 1 || page.Data[0].UserID != uid["u2"] {
+		t.Fatalf("unexpected phrase search: %s", w.Body.String())
+	}
+
+	for _, q := range []string{"", "login&order=popular", "since:ayer"} {
+		if w := doReq(router, http.MethodGet, "/v1/search?q="+q, nil); w.Code != http.StatusBadRequest {
+			t.Fatalf("q=%q got %d", q, w.Code)
+		}
+	}
+}
+
+func TestUsers_ProfileAndUnknownUsers(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+
+	w := doReq(router, http.MethodPost, "/v1/users", map[string]string{"handle": "ana", "display_name": "Ana", "bio": "hola"})
+	var created struct {
+		Data struct {
+			ID          string `json:"id"`
+			Handle      string `json:"handle"`
+			DisplayName string `json:"display_name"`
+			Bio         string `json:"bio"`
+		} `json:"data"`
+		APIKey struct {
+			Token string `json:"token"`
+		} `json:"api_key"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || w.Code != http.StatusCreated || created.Data.ID == "" || created.APIKey.Token == "" {
+		t.Fatalf("create: %d %s", w.Code, w.Body.String())
+	}
+	id, token := created.Data.ID, created.APIKey.Token
+	if w := doReq(router, http.MethodPost, "/v1/users", map[string]string{"handle": "ANA"}); w.Code != http.StatusConflict {
+		t.Fatalf("duplicate handle got %d", w.Code)
+	}
+	if w := doReq(router, http.MethodPost, "/v1/users", map[string]string{"handle": "con espacio"}); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("invalid handle got %d", w.Code)
+	}
+
+	_, tok := signUp(t, router, "otro")
+	if w := doAs(router, tok["otro"], http.MethodPatch, "/v1/users/"+id, map[string]string{"bio": "hack"}); w.Code != http.StatusForbidden {
+		t.Fatalf("patch by another user got %d", w.Code)
+	}
+	w = doAs(router, token, http.MethodPatch, "/v1/users/"+id, map[string]string{"bio": "chau"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch got %d %s", w.Code, w.Body.String())
+	}
+	w = doReq(router, http.MethodGet, "/v1/users/"+id, nil)
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil || created.Data.Bio != "chau" || created.Data.DisplayName != "Ana" {
+		t.Fatalf("get after patch: %s", w.Body.String())
+	}
+	if w := doReq(router, http.MethodGet, "/v1/users/nadie", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("unknown user got %d", w.Code)
+	}
+
+	if w := doAs(router, token, http.MethodPost, "/v1/follows", map[string]string{"followee_id": "nadie"}); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("follow unknown user got %d", w.Code)
+	}
+}
+
+func TestAPIKeys_AuthRotateRevoke(t *testing.T) {
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
+
+	w := doReq(router, http.MethodPost, "/v1/tweets", map[string]string{"text": "anónimo"})
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("no credentials got %d %v", w.Code, w.Header())
+	}
+	if w := doAs(router, "tck_inventada", http.MethodPost, "/v1/tweets", map[string]string{"text": "x"}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("unknown key got %d", w.Code)
+	}
+	// el autor sale de la key aunque el body traiga otro user_id
+	w = doAs(router, tok["u1"], http.MethodPost, "/v1/tweets", map[string]string{"user_id": uid["u2"], "text": "soy u1"})
+	var tw struct {
+		Data struct {
+			UserID string `json:"user_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &tw); err != nil || w.Code != http.StatusCreated || tw.Data.UserID != uid["u1"] {
+		t.Fatalf("create tweet: %d %s", w.Code, w.Body.String())
+	}
+
+	// X-API-Key también vale
+	req := httptest.NewRequest(http.MethodPost, "/v1/api-keys", bytes.NewBufferString(`{"name":"ci"}`))
+	req.Header.Set("X-API-Key", tok["u1"])
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var issued struct {
+		Data struct {
+			ID    string `json:"id"`
+			Name  string `json:"name"`
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &issued); err != nil || rec.Code != http.StatusCreated || issued.Data.Name != "ci" || issued.Data.Token == "" {
+		t.Fatalf("issue: %d %s", rec.Code, rec.Body.String())
+	}
+
+	w = doAs(router, tok["u1"], http.MethodGet, "/v1/api-keys", nil)
+	var list struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 2 {
+		t.Fatalf("list: %d %s", w.Code, w.Body.String())
+	}
+	if _, leaked := list.Data[0]["token"]; leaked {
+		t.Fatalf("list must not include tokens: %s", w.Body.String())
+	}
+
+	keyPath := "/v1/api-keys/" + issued.Data.ID
+	if w := doAs(router, tok["u2"], http.MethodPost, keyPath+"/rotate", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("rotate someone else's key got %d", w.Code)
+	}
+	w = doAs(router, tok["u1"], http.MethodPost, keyPath+"/rotate", nil)
+	var rotated struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &rotated); err != nil || w.Code != http.StatusCreated {
+		t.Fatalf("rotate: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, issued.Data.Token, http.MethodGet, "/v1/api-keys", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("rotated-out key got %d", w.Code)
+	}
+	if w := doAs(router, tok["u1"], http.MethodPost, keyPath+"/rotate", nil); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("rotate revoked key got %d", w.Code)
+	}
+
+	w = doAs(router, rotated.Data.Token, http.MethodGet, "/v1/api-keys", nil)
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 3 {
+		t.Fatalf("list after rotate: %s", w.Body.String())
+	}
+	for _, k := range list.Data {
+		if k["name"] == "ci" && k["revoked_at"] == nil {
+			if w := doAs(router, rotated.Data.Token, http.MethodDelete, "/v1/api-keys/"+k["id"].(string), nil); w.Code != http.StatusNoContent {
+				t.Fatalf("revoke got %d", w.Code)
+			}
+		}
+	}
+	if w := doAs(router, rotated.Data.Token, http.MethodGet, "/v1/api-keys", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked key got %d", w.Code)
+	}
+	if w := doAs(router, tok["u1"], http.MethodDelete, keyPath, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("revoke twice got %d", w.Code)
+	}
+}
+
+func TestJWT_ScopesRefreshAndJWKS(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "u1", "u2")
+
+	w := doReq(router, http.MethodGet, "/.well-known/jwks.json", nil)
+	var jwks struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Alg string `json:"alg"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &jwks); err != nil || len(jwks.Keys) != 1 || jwks.Keys[0].Alg != "EdDSA" {
+		t.Fatalf("jwks: %d %s", w.Code, w.Body.String())
+	}
+
+	type pair struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		Scope        string `json:"scope"`
+	}
+	decode := func(w *httptest.ResponseRecorder) pair {
+		t.Helper()
+		var p pair
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil || w.Code != http.StatusCreated || p.AccessToken == "" {
+			t.Fatalf("token response: %d %s", w.Code, w.Body.String())
+		}
+		return p
+	}
+	if w := doReq(router, http.MethodPost, "/v1/auth/token", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("token without credentials got %d", w.Code)
+	}
+	if w := doAs(router, tok["u1"], http.MethodPost, "/v1/auth/token", map[string][]string{"scopes": {"admin"}}); w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("unknown scope got %d", w.Code)
+	}
+	p := decode(doAs(router, tok["u1"], http.MethodPost, "/v1/auth/token", map[string][]string{"scopes": {"tweets:write", "timeline:read"}}))
+	if p.Scope != "tweets:write timeline:read" {
+		t.Fatalf("scope = %q", p.Scope)
+	}
+
+	if w := doAs(router, p.AccessToken, http.MethodPost, "/v1/tweets", map[string]string{"text": "con jwt"}); w.Code != http.StatusCreated {
+		t.Fatalf("tweet with jwt got %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, p.AccessToken, http.MethodGet, "/v1/timeline/"+uid["u1"], nil); w.Code != http.StatusOK {
+		t.Fatalf("timeline with jwt got %d", w.Code)
+	}
+	w = doAs(router, p.AccessToken, http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["u2"]})
+	if w.Code != http.StatusForbidden || w.Header().Get("WWW-Authenticate") == "" {
+		t.Fatalf("follow without follows:write got %d", w.Code)
+	}
+	if w := doAs(router, p.AccessToken, http.MethodPost, "/v1/api-keys", nil); w.Code != http.StatusForbidden {
+		t.Fatalf("issuing an API key with a jwt got %d", w.Code)
+	}
+	for _, r := range [][2]string{
+		{http.MethodPatch, "/v1/users/" + uid["u1"]},
+		{http.MethodGet, "/v1/follow-requests"},
+		{http.MethodGet, "/v1/users/" + uid["u1"] + "/blocks"},
+		{http.MethodGet, "/v1/mutes"},
+	} {
+		if w := doAs(router, p.AccessToken, r[0], r[1], map[string]string{"bio": "x"}); w.Code != http.StatusForbidden {
+			t.Fatalf("%s %s without scope got %d", r[0], r[1], w.Code)
+		}
+	}
+	graph := decode(doAs(router, tok["u1"], http.MethodPost, "/v1/auth/token", map[string][]string{"scopes": {"follows:read"}}))
+	if w := doAs(router, graph.AccessToken, http.MethodGet, "/v1/mutes", nil); w.Code != http.StatusOK {
+		t.Fatalf("mutes with follows:read got %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, p.AccessToken+"x", http.MethodPost, "/v1/tweets", map[string]string{"text": "x"}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("tampered jwt got %d", w.Code)
+	}
+
+	next := decode(doReq(router, http.MethodPost, "/v1/auth/refresh", map[string]string{"refresh_token": p.RefreshToken}))
+	if next.Scope != p.Scope || next.RefreshToken == p.RefreshToken {
+		t.Fatalf("refresh = %+v", next)
+	}
+	if w := doReq(router, http.MethodPost, "/v1/auth/refresh", map[string]string{"refresh_token": p.RefreshToken}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("reused refresh token got %d", w.Code)
+	}
+	if w := doReq(router, http.MethodPost, "/v1/auth/refresh", map[string]string{"refresh_token": next.RefreshToken}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("refresh after reuse detection got %d", w.Code)
+	}
+
+	// revocar la API key que emitió el par corta también los refresh
+	w = doAs(router, tok["u1"], http.MethodPost, "/v1/api-keys", map[string]string{"name": "cli"})
+	var key struct {
+		Data struct {
+			ID    string `json:"id"`
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &key); err != nil || w.Code != http.StatusCreated {
+		t.Fatalf("issue key: %d %s", w.Code, w.Body.String())
+	}
+	viaKey := decode(doAs(router, key.Data.Token, http.MethodPost, "/v1/auth/token", nil))
+	if w := doAs(router, tok["u1"], http.MethodDelete, "/v1/api-keys/"+key.Data.ID, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("revoke key: %d", w.Code)
+	}
+	if w := doReq(router, http.MethodPost, "/v1/auth/refresh", map[string]string{"refresh_token": viaKey.RefreshToken}); w.Code != http.StatusUnauthorized {
+		t.Fatalf("refresh after key revocation got %d", w.Code)
+	}
+}
+
+func TestAdmin_ModerationEndpoints(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "true")
+	t.Setenv("RATE_LIMIT_MAX_TWEETS", "1")
+	t.Setenv("SQLITE_MODE", "file")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "tweets.db"))
+
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "mod", "u1", "spam")
+
+	// El rol admin sólo se asigna por fuera de la API (como `server admin grant`).
+	db, err := adaptersdb.Open(adaptersdb.ConfigFromEnv())
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	_, err = app.SetUserRole{Users: adaptersdb.NewUserRepoGorm(db)}.Exec(context.Background(), app.SetUserRoleInput{Handle: "mod", Role: "admin"})
+	_ = adaptersdb.Close(db)
+	if err != nil {
+		t.Fatalf("grant admin: %v", err)
+	}
+
+	if w := doAs(router, tok["u1"], http.MethodGet, "/v1/admin/users/"+uid["spam"]+"/follows", nil); w.Code != http.StatusForbidden {
+		t.Fatalf("non-admin: want 403, got %d", w.Code)
+	}
+	// un JWT del admin, aunque tenga scopes, no modera
+	w := doAs(router, tok["mod"], http.MethodPost, "/v1/auth/token", map[string][]string{"scopes": {"timeline:read"}})
+	var jwt struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &jwt); err != nil || w.Code != http.StatusCreated {
+		t.Fatalf("token: %d %s", w.Code, w.Body.String())
+	}
+	for _, r := range [][2]string{
+		{http.MethodGet, "/v1/admin/users/" + uid["spam"] + "/follows"},
+		{http.MethodPost, "/v1/admin/users/" + uid["spam"] + "/suspension"},
+		{http.MethodDelete, "/v1/admin/tweets/nadie"},
+	} {
+		if w := doAs(router, jwt.AccessToken, r[0], r[1], nil); w.Code != http.StatusForbidden {
+			t.Fatalf("%s %s with admin jwt: want 403, got %d", r[0], r[1], w.Code)
+		}
+	}
+	if w := doReq(router, http.MethodGet, "/v1/admin/users/"+uid["spam"]+"/follows", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous: want 401, got %d", w.Code)
+	}
+
+	doAs(router, tok["u1"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["spam"]})
+	w = doAs(router, tok["spam"], http.MethodPost, "/v1/tweets", map[string]string{"text": "compra ya"})
+	var tw struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &tw)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("tweet status %d body %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["spam"], http.MethodPost, "/v1/tweets", map[string]string{"text": "otra vez"}); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("want 429, got %d", w.Code)
+	}
+
+	// follows y tweets de cualquier usuario
+	type follows struct {
+		Data []struct {
+			FollowerID string `json:"follower_id"`
+		} `json:"data"`
+	}
+	for dir, want := range map[string]int{"": 0, "following": 0, "followers": 1} {
+		w = doAs(router, tok["mod"], http.MethodGet, "/v1/admin/users/"+uid["spam"]+"/follows?limit=1&direction="+dir, nil)
+		var out follows
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != http.StatusOK || len(out.Data) != want ||
+			(want == 1 && out.Data[0].FollowerID != uid["u1"]) {
+			t.Fatalf("follows %q: %d %s", dir, w.Code, w.Body.String())
+		}
+	}
+	if w := doAs(router, tok["mod"], http.MethodGet, "/v1/admin/users/"+uid["spam"]+"/follows?direction=both", nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("bad direction: want 400, got %d", w.Code)
+	}
+	if w := doAs(router, tok["mod"], http.MethodGet, "/v1/admin/users/nadie/tweets", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("unknown user: want 404, got %d", w.Code)
+	}
+
+	// reset del rate limit
+	if w := doAs(router, tok["mod"], http.MethodDelete, "/v1/admin/users/"+uid["spam"]+"/rate-limit", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("reset rate limit: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["spam"], http.MethodPost, "/v1/tweets", map[string]string{"text": "otra vez"}); w.Code != http.StatusCreated {
+		t.Fatalf("after reset: want 201, got %d %s", w.Code, w.Body.String())
+	}
+
+	// suspensión: no publica y desaparece del timeline
+	w = doAs(router, tok["mod"], http.MethodPost, "/v1/admin/users/"+uid["spam"]+"/suspension", nil)
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte(`"suspended_at"`)) {
+		t.Fatalf("suspend: %d %s", w.Code, w.Body.String())
+	}
+	doAs(router, tok["mod"], http.MethodDelete, "/v1/admin/users/"+uid["spam"]+"/rate-limit", nil)
+	if w := doAs(router, tok["spam"], http.MethodPost, "/v1/tweets", map[string]string{"text": "sigo"}); w.Code != http.StatusForbidden {
+		t.Fatalf("suspended post: want 403, got %d %s", w.Code, w.Body.String())
+	}
+	timeline := func() int {
+		t.Helper()
+		w := doAs(router, tok["u1"], http.MethodGet, "/v1/timeline/"+uid["u1"], nil)
+		var out struct {
+			Data []json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("timeline: %d %s", w.Code, w.Body.String())
+		}
+		return len(out.Data)
+	}
+	if n := timeline(); n != 0 {
+		t.Fatalf("suspended author leaked into timeline: %d items", n)
+	}
+	w = doAs(router, tok["mod"], http.MethodGet, "/v1/admin/users/"+uid["spam"]+"/tweets", nil)
+	if w.Code != http.StatusOK || !bytes.Contains(w.Body.Bytes(), []byte("compra ya")) {
+		t.Fatalf("admin tweets: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["mod"], http.MethodDelete, "/v1/admin/users/"+uid["spam"]+"/suspension", nil); w.Code != http.StatusOK {
+		t.Fatalf("unsuspend: %d %s", w.Code, w.Body.String())
+	}
+	if n := timeline(); n != 2 {
+		t.Fatalf("want 2 items after unsuspend, got %d", n)
+	}
+
+	// borrado duro
+	if w := doAs(router, tok["mod"], http.MethodDelete, "/v1/admin/tweets/"+tw.Data.ID, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("hard delete: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["mod"], http.MethodDelete, "/v1/admin/tweets/"+tw.Data.ID, nil); w.Code != http.StatusNotFound {
+		t.Fatalf("second hard delete: want 404, got %d", w.Code)
+	}
+	if n := timeline(); n != 1 {
+		t.Fatalf("want 1 item after hard delete, got %d", n)
+	}
+}
+
+func TestFollowers_AndFollowingPaged(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "star", "f1", "f2", "f3")
+	for _, h := range []string{"f1", "f2", "f3"} {
+		if w := doAs(router, tok[h], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["star"]}); w.Code != http.StatusCreated {
+			t.Fatalf("follow %s: %d %s", h, w.Code, w.Body.String())
+		}
+	}
+	doAs(router, tok["star"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["f1"]})
+
+	type page struct {
+		Data []struct {
+			FollowerID string `json:"follower_id"`
+			FolloweeID string `json:"followee_id"`
+		} `json:"data"`
+		Paging struct {
+			NextCursor string `json:"next_cursor"`
+		} `json:"paging"`
+	}
+	get := func(path string) page {
+		t.Helper()
+		w := doReq(router, http.MethodGet, path, nil)
+		var p page
+		if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", path, w.Code, w.Body.String())
+		}
+		return p
+	}
+
+	// Misma fecha en los tres follows: el orden lo desempata el id del seguidor.
+	seen := map[string]bool{}
+	p := get("/v1/users/" + uid["star"] + "/followers?limit=2")
+	if len(p.Data) != 2 || p.Paging.NextCursor == "" {
+		t.Fatalf("page 1: %+v", p)
+	}
+	for _, f := range p.Data {
+		seen[f.FollowerID] = true
+	}
+	p = get("/v1/users/" + uid["star"] + "/followers?limit=2&cursor=" + p.Paging.NextCursor)
+	if len(p.Data) != 1 || p.Paging.NextCursor != "" {
+		t.Fatalf("page 2: %+v", p)
+	}
+	seen[p.Data[0].FollowerID] = true
+	if len(seen) != 3 || !seen[uid["f1"]] || !seen[uid["f2"]] || !seen[uid["f3"]] {
+		t.Fatalf("followers across pages: %v", seen)
+	}
+
+	p = get("/v1/users/" + uid["star"] + "/following")
+	if len(p.Data) != 1 || p.Data[0].FolloweeID != uid["f1"] {
+		t.Fatalf("following: %+v", p)
+	}
+	if w := doReq(router, http.MethodGet, "/v1/users/nadie/followers", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("unknown user: want 404, got %d", w.Code)
+	}
+	if w := doReq(router, http.MethodGet, "/v1/users/"+uid["star"]+"/following?cursor=basura", nil); w.Code != http.StatusBadRequest {
+		t.Fatalf("bad cursor: want 400, got %d", w.Code)
+	}
+}
+
+func TestUsers_ProfileCounters(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "ana", "bob")
+
+	type counters struct {
+		FollowerCount  int64 `json:"follower_count"`
+		FollowingCount int64 `json:"following_count"`
+		TweetCount     int64 `json:"tweet_count"`
+	}
+	profile := func(h string) counters {
+		t.Helper()
+		w := doReq(router, http.MethodGet, "/v1/users/"+uid[h], nil)
+		var out struct {
+			Data counters `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("profile %s: %d %s", h, w.Code, w.Body.String())
+		}
+		return out.Data
+	}
+
+	for i := 0; i < 2; i++ { // el segundo follow es un no-op
+		doAs(router, tok["bob"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["ana"]})
+	}
+	var tw struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	for _, text := range []string{"uno", "dos"} {
+		w := doAs(router, tok["ana"], http.MethodPost, "/v1/tweets", map[string]string{"text": text})
+		_ = json.Unmarshal(w.Body.Bytes(), &tw)
+	}
+	doAs(router, tok["bob"], http.MethodPost, "/v1/tweets/"+tw.Data.ID+"/retweets", nil)
+	if got := profile("ana"); got != (counters{FollowerCount: 1, TweetCount: 2}) {
+		t.Fatalf("ana = %+v", got)
+	}
+	if got := profile("bob"); got != (counters{FollowingCount: 1}) {
+		t.Fatalf("bob = %+v", got)
+	}
+
+	doAs(router, tok["ana"], http.MethodDelete, "/v1/tweets/"+tw.Data.ID, nil)
+	doAs(router, tok["bob"], http.MethodDelete, "/v1/follows", map[string]string{"followee_id": uid["ana"]})
+	if got := profile("ana"); got != (counters{TweetCount: 1}) {
+		t.Fatalf("ana after delete/unfollow = %+v", got)
+	}
+	if got := profile("bob"); got != (counters{}) {
+		t.Fatalf("bob after unfollow = %+v", got)
+	}
+}
+
+func TestBlocks_CutFollowsAndHideFromTimeline(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "ana", "troll", "caro")
+	for _, f := range [][2]string{{"troll", "ana"}, {"troll", "caro"}, {"ana", "troll"}} {
+		doAs(router, tok[f[0]], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid[f[1]]})
+	}
+	var tw struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	w := doAs(router, tok["ana"], http.MethodPost, "/v1/tweets", map[string]string{"text": "hola"})
+	_ = json.Unmarshal(w.Body.Bytes(), &tw)
+	doAs(router, tok["caro"], http.MethodPost, "/v1/tweets/"+tw.Data.ID+"/retweets", nil)
+	doAs(router, tok["caro"], http.MethodPost, "/v1/tweets", map[string]string{"text": "ok"})
+
+	block := map[string]string{"blocked_id": uid["troll"]}
+	if w := doAs(router, tok["ana"], http.MethodPost, "/v1/blocks", block); w.Code != http.StatusCreated {
+		t.Fatalf("block: %d %s", w.Code, w.Body.String())
+	}
+	w = doReq(router, http.MethodGet, "/v1/users/"+uid["ana"], nil)
+	var profile struct {
+		Data struct {
+			FollowerCount  int64 `json:"follower_count"`
+			FollowingCount int64 `json:"following_count"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &profile); err != nil || profile.Data.FollowerCount != 0 || profile.Data.FollowingCount != 0 {
+		t.Fatalf("follows not cut: %s", w.Body.String())
+	}
+	for _, f := range [][2]string{{"troll", "ana"}, {"ana", "troll"}} {
+		if w := doAs(router, tok[f[0]], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid[f[1]]}); w.Code != http.StatusForbidden {
+			t.Fatalf("follow %s -> %s: want 403, got %d %s", f[0], f[1], w.Code, w.Body.String())
+		}
+	}
+
+	// El retweet de caro tampoco le muestra el tweet de ana.
+	w = doAs(router, tok["troll"], http.MethodGet, "/v1/timeline/"+uid["troll"], nil)
+	var timeline struct {
+		Data []struct {
+			Text string `json:"text"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &timeline); err != nil || len(timeline.Data) != 1 || timeline.Data[0].Text != "ok" {
+		t.Fatalf("troll timeline: %d %s", w.Code, w.Body.String())
+	}
+	// Ni a través del timeline de caro, que sigue a ana.
+	doAs(router, tok["caro"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["ana"]})
+	if w := doAs(router, tok["troll"], http.MethodGet, "/v1/timeline/"+uid["caro"], nil); w.Code != http.StatusForbidden {
+		t.Fatalf("caro's timeline as troll: want 403, got %d %s", w.Code, w.Body.String())
+	}
+	// Tampoco puede responderle, darle like, retwittearla ni citarla.
+	if w := doAs(router, tok["troll"], http.MethodPost, "/v1/tweets/"+tw.Data.ID+"/likes", nil); w.Code != http.StatusForbidden {
+		t.Fatalf("like blocker: want 403, got %d %s", w.Code, w.Body.String())
+	}
+	reply := map[string]string{"text": "jaja", "in_reply_to_id": tw.Data.ID}
+	if w := doAs(router, tok["troll"], http.MethodPost, "/v1/tweets", reply); w.Code != http.StatusForbidden {
+		t.Fatalf("reply to blocker: want 403, got %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["troll"], http.MethodPost, "/v1/tweets/"+tw.Data.ID+"/retweets", nil); w.Code != http.StatusForbidden {
+		t.Fatalf("retweet blocker: want 403, got %d %s", w.Code, w.Body.String())
+	}
+	quote := map[string]string{"text": "jaja", "quoted_tweet_id": tw.Data.ID}
+	if w := doAs(router, tok["troll"], http.MethodPost, "/v1/tweets", quote); w.Code != http.StatusForbidden {
+		t.Fatalf("quote blocker: want 403, got %d %s", w.Code, w.Body.String())
+	}
+
+	path := "/v1/users/" + uid["ana"] + "/blocks"
+	w = doAs(router, tok["ana"], http.MethodGet, path, nil)
+	var blocks struct {
+		Data []struct {
+			BlockedID string `json:"blocked_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &blocks); err != nil || len(blocks.Data) != 1 || blocks.Data[0].BlockedID != uid["troll"] {
+		t.Fatalf("blocks: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["troll"], http.MethodGet, path, nil); w.Code != http.StatusForbidden {
+		t.Fatalf("someone else's blocks: want 403, got %d", w.Code)
+	}
+	if w := doReq(router, http.MethodGet, path, nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous: want 401, got %d", w.Code)
+	}
+
+	if w := doAs(router, tok["ana"], http.MethodDelete, "/v1/blocks", block); w.Code != http.StatusNoContent {
+		t.Fatalf("unblock: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["troll"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid["ana"]}); w.Code != http.StatusCreated {
+		t.Fatalf("follow after unblock: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestMutes_FilterTimelineKeepingFullPages(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "ana", "bob", "caro")
+	for _, h := range []string{"bob", "caro"} {
+		doAs(router, tok["ana"], http.MethodPost, "/v1/follows", map[string]string{"followee_id": uid[h]})
+	}
+	for _, text := range []string{"uno", "SPOILER dos", "tres", "spoiler cuatro", "spoiler cinco"} {
+		doAs(router, tok["bob"], http.MethodPost, "/v1/tweets", map[string]string{"text": text})
+	}
+	doAs(router, tok["caro"], http.MethodPost, "/v1/tweets", map[string]string{"text": "hola"})
+
+	type mute struct {
+		ID    string `json:"id"`
+		Kind  string `json:"kind"`
+		Value string `json:"value"`
+	}
+	create := func(body map[string]any) (int, mute) {
+		t.Helper()
+		w := doAs(router, tok["ana"], http.MethodPost, "/v1/mutes", body)
+		var out struct {
+			Data mute `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &out)
+		return w.Code, out.Data
+	}
+	if code, m := create(map[string]any{"kind": "keyword", "value": "Spoiler", "expires_in": 3600}); code != http.StatusCreated || m.Value != "spoiler" {
+		t.Fatalf("mute keyword: %d %+v", code, m)
+	}
+	code, caro := create(map[string]any{"kind": "account", "value": uid["caro"]})
+	if code != http.StatusCreated {
+		t.Fatalf("mute account: %d", code)
+	}
+	for _, body := range []map[string]any{
+		{"kind": "regex", "value": "("},
+		{"kind": "hashtag", "value": "x"},
+		{"kind": "account", "value": uid["ana"]},
+	} {
+		if code, _ := create(body); code != 422 {
+			t.Fatalf("mute %v: want 422, got %d", body, code)
+		}
+	}
+
+	timeline := func() []string {
+		t.Helper()
+		w := doAs(router, tok["ana"], http.MethodGet, "/v1/timeline/"+uid["ana"]+"?limit=2", nil)
+		var out struct {
+			Data []struct {
+				Text string `json:"text"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("timeline: %d %s", w.Code, w.Body.String())
+		}
+		var texts []string
+		for _, tw := range out.Data {
+			texts = append(texts, tw.Text)
+		}
+		return texts
+	}
+	// Los tres tweets más nuevos están silenciados y aun así la página viene llena.
+	if got := timeline(); len(got) != 2 || got[0] != "tres" || got[1] != "uno" {
+		t.Fatalf("muted timeline: %v", got)
+	}
+
+	w := doAs(router, tok["ana"], http.MethodGet, "/v1/mutes", nil)
+	var list struct {
+		Data []mute `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 2 {
+		t.Fatalf("list mutes: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["ana"], http.MethodDelete, "/v1/mutes/"+caro.ID, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("unmute: %d %s", w.Code, w.Body.String())
+	}
+	if got := timeline(); len(got) != 2 || got[0] != "hola" || got[1] != "tres" {
+		t.Fatalf("timeline after unmute: %v", got)
+	}
+	if w := doReq(router, http.MethodGet, "/v1/mutes", nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous: want 401, got %d", w.Code)
+	}
+}
+
+func TestProtectedAccounts_FollowRequestsAndVisibility(t *testing.T) {
+	t.Setenv("RATE_LIMIT_ENABLED", "false")
+	router, shutdown, err := bootstrap.BuildHTTPServer()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer shutdown()
+	uid, tok := signUp(t, router, "ana", "bob", "caro")
+	if w := doAs(router, tok["ana"], http.MethodPatch, "/v1/users/"+uid["ana"], map[string]any{"protected": true}); w.Code != http.StatusOK {
+		t.Fatalf("protect: %d %s", w.Code, w.Body.String())
+	}
+	w := doAs(router, tok["ana"], http.MethodPost, "/v1/tweets", map[string]string{"text": "sólo amigos #privado"})
+	var secret struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &secret); err != nil || w.Code != http.StatusCreated {
+		t.Fatalf("tweet: %d %s", w.Code, w.Body.String())
+	}
+
+	type tweets struct {
+		Data []struct {
+			Text string `json:"text"`
+		} `json:"data"`
+	}
+	count := func(token, path string) int {
+		t.Helper()
+		w := doAs(router, token, http.MethodGet, path, nil)
+		var out tweets
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("GET %s: %d %s", path, w.Code, w.Body.String())
+		}
+		return len(out.Data)
+	}
+	profile := "/v1/users/" + uid["ana"] + "/tweets"
+	for _, path := range []string{profile, "/v1/hashtags/privado/tweets", "/v1/search?q=amigos"} {
+		if n := count("", path); n != 0 {
+			t.Fatalf("anonymous %s: %d tweets", path, n)
+		}
+		if n := count(tok["ana"], path); n != 1 {
+			t.Fatalf("owner %s: %d tweets", path, n)
+		}
+	}
+	if w := doAs(router, "tck_inventado", http.MethodGet, profile, nil); w.Code != http.StatusUnauthorized {
+		t.Fatalf("bad credential: want 401, got %d", w.Code)
+	}
+
+	follow := map[string]string{"followee_id": uid["ana"]}
+	if w := doAs(router, tok["bob"], http.MethodPost, "/v1/follows", follow); w.Code != http.StatusAccepted {
+		t.Fatalf("follow protected: want 202, got %d %s", w.Code, w.Body.String())
+	}
+	doAs(router, tok["caro"], http.MethodPost, "/v1/follows", follow)
+	if n := count(tok["bob"], profile); n != 0 {
+		t.Fatalf("pending follower sees %d tweets", n)
+	}
+
+	w = doAs(router, tok["ana"], http.MethodGet, "/v1/follow-requests", nil)
+	var reqs struct {
+		Data []struct {
+			RequesterID string `json:"requester_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &reqs); err != nil || len(reqs.Data) != 2 || reqs.Data[0].RequesterID != uid["caro"] {
+		t.Fatalf("follow requests: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["ana"], http.MethodPost, "/v1/follow-requests/"+uid["bob"]+"/approve", nil); w.Code != http.StatusCreated {
+		t.Fatalf("approve: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["ana"], http.MethodPost, "/v1/follow-requests/"+uid["caro"]+"/reject", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("reject: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["ana"], http.MethodPost, "/v1/follow-requests/"+uid["caro"]+"/approve", nil); w.Code != http.StatusNotFound {
+		t.Fatalf("approve rejected: want 404, got %d", w.Code)
+	}
+
+	if n := count(tok["bob"], profile); n != 1 {
+		t.Fatalf("approved follower sees %d tweets", n)
+	}
+	if n := count(tok["bob"], "/v1/timeline/"+uid["bob"]); n != 1 {
+		t.Fatalf("approved follower timeline: %d tweets", n)
+	}
+	if n := count(tok["caro"], "/v1/timeline/"+uid["caro"]); n != 0 {
+		t.Fatalf("rejected follower timeline: %d tweets", n)
+	}
+	// ni a través del timeline de bob
+	if w := doAs(router, tok["caro"], http.MethodGet, "/v1/timeline/"+uid["bob"], nil); w.Code != http.StatusForbidden {
+		t.Fatalf("bob's timeline as caro: want 403, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Para quien no la sigue, el tweet no existe: ni revisiones, ni likes, ni retweets.
+	tweet := "/v1/tweets/" + secret.Data.ID
+	for _, r := range []struct{ token, method, path string }{
+		{"", http.MethodGet, tweet + "/revisions"},
+		{"", http.MethodGet, tweet + "/likes"},
+		{tok["caro"], http.MethodPost, tweet + "/likes"},
+		{tok["caro"], http.MethodPost, tweet + "/retweets"},
+	} {
+		if w := doAs(router, r.token, r.method, r.path, nil); w.Code != http.StatusNotFound {
+			t.Fatalf("%s %s as %q: want 404, got %d %s", r.method, r.path, r.token, w.Code, w.Body.String())
+		}
+	}
+	// responder tampoco: sale igual que un padre inexistente
+	for _, parent := range []string{secret.Data.ID, "nope"} {
+		reply := map[string]string{"text": "hola", "in_reply_to_id": parent}
+		if w := doAs(router, tok["caro"], http.MethodPost, "/v1/tweets", reply); w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("reply to %s: want 422, got %d %s", parent, w.Code, w.Body.String())
+		}
+	}
+	reply := map[string]string{"text": "hola", "in_reply_to_id": secret.Data.ID}
+	if w := doAs(router, tok["bob"], http.MethodPost, "/v1/tweets", reply); w.Code != http.StatusCreated {
+		t.Fatalf("reply as follower: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["bob"], http.MethodPost, tweet+"/likes", nil); w.Code != http.StatusCreated {
+		t.Fatalf("like as follower: %d %s", w.Code, w.Body.String())
+	}
+	if w := doAs(router, tok["bob"], http.MethodGet, tweet+"/revisions", nil); w.Code != http.StatusOK {
+		t.Fatalf("revisions as follower: %d %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,24 @@
+package ports
+
+import "tweetschallenge/internal/domain"
+
+// AccessTokens firma y verifica access tokens (JWT) con claves locales.
+type AccessTokens interface {
+	Sign(c domain.AccessClaims) (string, error)
+	// Verify chequea firma, kid, issuer y vencimiento; si algo falla
+	// devuelve domain.ErrUnauthenticated.
+	Verify(token string) (domain.AccessClaims, error)
+	// PublicKeys son las claves asimétricas vigentes para verificar (JWKS);
+	// las HS256 son secretas y no se publican.
+	PublicKeys() []JWK
+}
+
+// JWK es una clave pública en formato RFC 7517 (sólo OKP/Ed25519).
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+}
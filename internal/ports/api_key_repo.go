@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+)
+
+type APIKeyRepo interface {
+	Create(ctx context.Context, k *domain.APIKey) error
+	Get(ctx context.Context, id string) (domain.APIKey, error) // domain.ErrAPIKeyNotFound
+	ByHash(ctx context.Context, hash string) (domain.APIKey, error)
+	// ListByUser incluye las revocadas, más nuevas primero.
+	ListByUser(ctx context.Context, userID string) ([]domain.APIKey, error)
+	// Revoke es idempotente: no pisa revoked_at si ya estaba revocada.
+	Revoke(ctx context.Context, id string, at int64) error
+	// Rotate revoca oldID y crea next en una misma transacción.
+	Rotate(ctx context.Context, oldID string, next *domain.APIKey, at int64) error
+}
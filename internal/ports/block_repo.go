@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"tweetschallenge/internal/domain"
+)
+
+type BlockRepo interface {
+	// Create es idempotente: bloquear dos veces conserva el bloqueo original.
+	Create(ctx context.Context, b *domain.Block) error
+	Delete(ctx context.Context, blockerID, blockedID string) error
+	// Between indica si alguno de los dos bloqueó al otro.
+	Between(ctx context.Context, a, b string) (bool, error)
+	// BlockerIDs son los usuarios que bloquearon a userID.
+	BlockerIDs(ctx context.Context, userID string) ([]string, error)
+	// Page pagina los bloqueos de blockerID por (created_at, blocked_id) DESC.
+	Page(ctx context.Context, blockerID string, q PageQuery) (Page[domain.Block], error)
+}
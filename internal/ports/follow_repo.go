@@ -8,6 +8,24 @@ import (
 type FollowRepo interface {
 	Create(ctx context.Context, f *domain.Follow) error
 	Unfollow(ctx context.Context, followerID, followeeID string) error
+	Exists(ctx context.Context, followerID, followeeID string) (bool, error)
 	FollowingIDs(ctx context.Context, followerID string) ([]string, error)
 	FollowersIDs(ctx context.Context, followeeID string) ([]string, error)
+	// CountFollowers cuenta sin cargar los ids (el fan-out decide con esto si el autor es heavy).
+	CountFollowers(ctx context.Context, followeeID string) (int64, error)
+	// FollowersPage pagina los follows hacia userID por (created_at, follower_id) DESC.
+	FollowersPage(ctx context.Context, userID string, q PageQuery) (Page[domain.Follow], error)
+	// FollowingPage pagina los follows de userID por (created_at, followee_id) DESC.
+	FollowingPage(ctx context.Context, userID string, q PageQuery) (Page[domain.Follow], error)
+}
+
+// FollowRequestRepo guarda las solicitudes pendientes hacia cuentas protegidas.
+type FollowRequestRepo interface {
+	// Create es idempotente: repetir la solicitud conserva la original.
+	Create(ctx context.Context, r *domain.FollowRequest) error
+	// Get devuelve domain.ErrFollowRequestNotFound si no hay solicitud pendiente.
+	Get(ctx context.Context, requesterID, targetID string) (domain.FollowRequest, error)
+	Delete(ctx context.Context, requesterID, targetID string) error
+	// Page pagina las solicitudes hacia targetID por (created_at, requester_id) DESC.
+	Page(ctx context.Context, targetID string, q PageQuery) (Page[domain.FollowRequest], error)
 }
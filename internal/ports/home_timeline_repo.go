@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+)
+
+// HomeTimelineRepo guarda el timeline materializado (fan-out on write) de
+// cada usuario. Los autores "heavy" (demasiados seguidores) no se
+// materializan y se leen con fan-out on read.
+type HomeTimelineRepo interface {
+	Push(ctx context.Context, ownerIDs []string, t domain.Tweet) error
+	Backfill(ctx context.Context, ownerID string, tweets []domain.Tweet) error
+	PurgeAuthor(ctx context.Context, ownerID, authorID string) error
+	RemoveTweet(ctx context.Context, tweetID string) error
+	Page(ctx context.Context, ownerID string, q PageQuery) (Page[domain.Tweet], error)
+	MarkHeavy(ctx context.Context, authorID string, markedAt int64) error
+	IsHeavy(ctx context.Context, authorID string) (bool, error)
+	HeavyFollowees(ctx context.Context, followerID string) ([]string, error)
+}
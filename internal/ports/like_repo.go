@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+)
+
+// LikeRepo mantiene tweet_models.like_count en la misma transacción que el like,
+// así los listados de tweets traen el contador sin consultas extra.
+type LikeRepo interface {
+	// Create es idempotente por (UserID, TweetID).
+	Create(ctx context.Context, l *domain.Like) error
+	// Unlike es idempotente.
+	Unlike(ctx context.Context, userID, tweetID string) error
+	// LikersPage pagina por (created_at, user_id) DESC.
+	LikersPage(ctx context.Context, tweetID string, q PageQuery) (Page[domain.Like], error)
+	// LikedTweetsPage pagina por (liked_at, tweet_id) DESC y omite tweets borrados.
+	LikedTweetsPage(ctx context.Context, userID string, q PageQuery) (Page[domain.LikedTweet], error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"tweetschallenge/internal/domain"
+)
+
+type MuteRepo interface {
+	// Create guarda el mute; si el usuario ya tenía uno igual (kind, value)
+	// sólo actualiza la expiración y m queda con el id y la fecha originales.
+	Create(ctx context.Context, m *domain.Mute) error
+	// Delete es idempotente y sólo borra mutes de userID.
+	Delete(ctx context.Context, userID, id string) error
+	// Active lista los mutes de userID no vencidos a now, más recientes primero.
+	Active(ctx context.Context, userID string, now int64) ([]domain.Mute, error)
+}
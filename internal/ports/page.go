@@ -0,0 +1,33 @@
+package ports
+
+import "tweetschallenge/internal/domain"
+
+// Tamaño de página cuando no se pide uno y tope para lo que se pida.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageLimit normaliza el límite pedido: default si no es positivo, tope si se pasa.
+func PageLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// PageQuery pide una página por keyset; sin Cursor arranca por lo más nuevo.
+type PageQuery struct {
+	Limit  int
+	Cursor *domain.Cursor
+}
+
+// Page devuelve Items ordenados de más nuevo a más viejo. HasMore indica si
+// quedan items en la dirección pedida por el cursor.
+type Page[T any] struct {
+	Items   []T
+	HasMore bool
+}
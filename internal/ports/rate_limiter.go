@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// RateLimiter cuenta requests por clave (el usuario) en ventanas fijas.
+type RateLimiter interface {
+	// Allow suma la request a la ventana vigente de key y dice si entra en el cupo.
+	Allow(ctx context.Context, key string) (bool, error)
+	// Reset vacía la ventana de key: la próxima request arranca una nueva.
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+)
+
+type RefreshTokenRepo interface {
+	Create(ctx context.Context, t *domain.RefreshToken) error
+	ByHash(ctx context.Context, hash string) (domain.RefreshToken, error) // domain.ErrUnauthenticated
+	// Rotate revoca oldID y crea next en una transacción. Si oldID ya estaba
+	// revocado (dos refresh concurrentes) devuelve domain.ErrUnauthenticated.
+	Rotate(ctx context.Context, oldID string, next *domain.RefreshToken, at int64) error
+	// RevokeUser revoca todos los refresh tokens vivos del usuario.
+	RevokeUser(ctx context.Context, userID string, at int64) error
+}
@@ -0,0 +1,4 @@
+package ports
+
+// SecretGen genera secretos aleatorios (criptográficamente seguros) para tokens.
+type SecretGen interface{ NewSecret() string }
@@ -7,7 +7,34 @@ import (
 )
 
 type TweetRepo interface {
+	// Create devuelve domain.ErrParentNotFound si t.InReplyToID no existe.
 	Create(ctx context.Context, t *domain.Tweet) error
+	// Get devuelve domain.ErrTweetNotFound si no existe o está borrado.
+	Get(ctx context.Context, id string) (domain.Tweet, error)
+	// GetMany omite ids inexistentes o borrados; el orden no está garantizado.
+	GetMany(ctx context.Context, ids []string) ([]domain.Tweet, error)
+	// Retweet es idempotente por (UserID, RetweetOfID): si ya existe uno vivo
+	// lo carga en t y devuelve created=false.
+	Retweet(ctx context.Context, t *domain.Tweet) (created bool, err error)
+	// FindRetweet devuelve domain.ErrTweetNotFound si userID no retwitteó originalID.
+	FindRetweet(ctx context.Context, userID, originalID string) (domain.Tweet, error)
+	// Edit actualiza el texto y guarda rev en la misma transacción; asigna rev.Revision.
+	Edit(ctx context.Context, t *domain.Tweet, rev *domain.TweetRevision) error
+	Revisions(ctx context.Context, tweetID string) ([]domain.TweetRevision, error)
+	// SoftDelete deja un tombstone (deleted_at, sin texto) en lugar de borrar la fila.
+	SoftDelete(ctx context.Context, id string, deletedAt int64) error
+	// HardDelete borra la fila (viva o tombstone), sus retweets y todo lo que
+	// cuelga de ellos (revisiones, hashtags, menciones, likes) en una
+	// transacción. Devuelve los ids borrados o domain.ErrTweetNotFound.
+	HardDelete(ctx context.Context, id string) ([]string, error)
 	Timeline(ctx context.Context, userID string, limit, offset int) ([]domain.Tweet, error)
+	// Deprecated: usar TimelineForUsersPage (keyset); OFFSET se degrada con la profundidad.
 	TimelineForUsers(ctx context.Context, userIDs []string, limit, offset int) ([]domain.Tweet, error)
+	TimelineForUsersPage(ctx context.Context, userIDs []string, q PageQuery) (Page[domain.Tweet], error)
+	// HashtagTimelinePage pagina los tweets vivos con el tag (ya normalizado) por (created_at, id) DESC.
+	HashtagTimelinePage(ctx context.Context, tag string, q PageQuery) (Page[domain.Tweet], error)
+	// MentionTimelinePage pagina los tweets vivos que mencionan a userID por (created_at, id) DESC.
+	MentionTimelinePage(ctx context.Context, userID string, q PageQuery) (Page[domain.Tweet], error)
+	// Conversation devuelve el árbol de la conversación en orden DFS, tombstones incluidos.
+	Conversation(ctx context.Context, conversationID string, q PageQuery) (Page[domain.ThreadEntry], error)
 }
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+)
+
+type TweetSearcher interface {
+	// Search pagina por (Score, id) DESC; omite borrados y retweets.
+	Search(ctx context.Context, q domain.SearchQuery, page PageQuery) (Page[domain.SearchHit], error)
+}
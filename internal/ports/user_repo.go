@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"tweetschallenge/internal/domain"
+)
+
+type UserRepo interface {
+	Create(ctx context.Context, u *domain.User) error // domain.ErrHandleTaken
+	Get(ctx context.Context, id string) (domain.User, error)
+	Update(ctx context.Context, u *domain.User) error // domain.ErrHandleTaken
+	// ByHandles busca sin distinguir mayúsculas; los handles inexistentes se omiten.
+	ByHandles(ctx context.Context, handles []string) ([]domain.User, error)
+	SetRole(ctx context.Context, id, role string) error          // domain.ErrUserNotFound
+	SetSuspended(ctx context.Context, id string, at int64) error // at = 0 levanta la suspensión
+	// SuspendedIDs devuelve el subconjunto de ids con la cuenta suspendida.
+	SuspendedIDs(ctx context.Context, ids []string) ([]string, error)
+	// ProtectedIDs devuelve el subconjunto de ids con la cuenta protegida.
+	ProtectedIDs(ctx context.Context, ids []string) ([]string, error)
+}